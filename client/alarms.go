@@ -0,0 +1,67 @@
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+type Alarm struct {
+	ID             int    `json:"id"`
+	Type           string `json:"type"`
+	Enabled        bool   `json:"enabled"`
+	ValueThreshold int    `json:"value_threshold"`
+	TimeThreshold  int    `json:"time_threshold"`
+	VHostRegex     string `json:"vhost_regex,omitempty"`
+	QueueRegex     string `json:"queue_regex,omitempty"`
+	Recipients     []int  `json:"recipients"`
+}
+
+type AlarmCreateRequest struct {
+	Type           string `json:"type"`
+	Enabled        bool   `json:"enabled"`
+	ValueThreshold int    `json:"value_threshold,omitempty"`
+	TimeThreshold  int    `json:"time_threshold,omitempty"`
+	VHostRegex     string `json:"vhost_regex,omitempty"`
+	QueueRegex     string `json:"queue_regex,omitempty"`
+	Recipients     []int  `json:"recipients,omitempty"`
+}
+
+type AlarmCreateResponse struct {
+	ID int `json:"id"`
+}
+
+func (c *Client) ListAlarms(instanceID string) ([]Alarm, error) {
+	endpoint := "/instances/" + instanceID + "/alarms"
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []Alarm
+	if err := json.Unmarshal(respBody, &alarms); err != nil {
+		return nil, err
+	}
+
+	return alarms, nil
+}
+
+func (c *Client) CreateAlarm(instanceID string, req *AlarmCreateRequest) (*AlarmCreateResponse, error) {
+	endpoint := "/instances/" + instanceID + "/alarms"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var response AlarmCreateResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *Client) DeleteAlarm(instanceID string, alarmID int) error {
+	endpoint := "/instances/" + instanceID + "/alarms/" + strconv.Itoa(alarmID)
+	_, err := c.makeRequest("DELETE", endpoint, nil)
+	return err
+}
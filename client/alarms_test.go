@@ -0,0 +1,79 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListAlarms(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/instances/1234/alarms", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id":1,"type":"cpu","enabled":true,"value_threshold":90,"time_threshold":600,"recipients":[5,6]}]`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	alarms, err := client.ListAlarms("1234")
+
+	assert.NoError(t, err)
+	assert.Len(t, alarms, 1)
+	assert.Equal(t, "cpu", alarms[0].Type)
+	assert.Equal(t, 90, alarms[0].ValueThreshold)
+	assert.Equal(t, 600, alarms[0].TimeThreshold)
+	assert.Equal(t, []int{5, 6}, alarms[0].Recipients)
+}
+
+func TestCreateAlarm(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/instances/1234/alarms", r.URL.Path)
+
+		body, _ := io.ReadAll(r.Body)
+		var req AlarmCreateRequest
+		assert.NoError(t, json.Unmarshal(body, &req))
+		assert.Equal(t, "memory", req.Type)
+		assert.Equal(t, 80, req.ValueThreshold)
+		assert.Equal(t, []int{1, 2}, req.Recipients)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 42}`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	resp, err := client.CreateAlarm("1234", &AlarmCreateRequest{
+		Type:           "memory",
+		Enabled:        true,
+		ValueThreshold: 80,
+		TimeThreshold:  60,
+		Recipients:     []int{1, 2},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, resp.ID)
+}
+
+func TestDeleteAlarm(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/instances/1234/alarms/42", r.URL.Path)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	err := client.DeleteAlarm("1234", 42)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// HTTPDoer is the part of *http.Client the Client depends on. Tests can
+// supply their own implementation to avoid a real network round trip.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientAPI covers the Client methods used by commands, so command logic
+// can be tested against a fake instead of a real client.
+type ClientAPI interface {
+	ListInstances() ([]Instance, error)
+	ListInstancesWithOptions(opts ListOptions) ([]Instance, error)
+	GetInstance(id int) (*Instance, error)
+	CreateInstance(req *InstanceCreateRequest) (*InstanceCreateResponse, error)
+	UpdateInstance(id int, req *InstanceUpdateRequest) error
+	DeleteInstance(id int) error
+	ResizeInstanceDisk(id int, req *DiskResizeRequest) error
+	ResizeDisk(id int, sizeGB int) error
+	InstanceHealthy(id int) (bool, error)
+	GetInstanceURLs(id int) (map[string]string, error)
+	ListInstanceEvents(id int, since time.Time) ([]InstanceEvent, error)
+
+	RotatePassword(instanceID string) (*PasswordRotation, error)
+	GetPasswordRotationStatus(instanceID string) (*PasswordRotation, error)
+	RotateInstanceAPIKey(instanceID string) (string, error)
+
+	ListNodes(instanceID string) ([]Node, error)
+	GetNode(id int, node string) (*NodeDetails, error)
+	RebootInstance(instanceID string, nodes []string) error
+	GetAvailableVersions(instanceID string) (*VersionInfo, error)
+	ListPlugins(instanceID string) ([]Plugin, error)
+	EnablePlugin(instanceID, pluginName string) error
+	DisablePlugin(instanceID, pluginName string) error
+	GetRabbitMQConfig(instanceID string) (map[string]interface{}, error)
+	UpdateRabbitMQConfig(instanceID string, config map[string]interface{}) error
+	ListAlarms(instanceID string) ([]Alarm, error)
+	CreateAlarm(instanceID string, req *AlarmCreateRequest) (*AlarmCreateResponse, error)
+	DeleteAlarm(instanceID string, alarmID int) error
+	ListRecipients(instanceID string) ([]Recipient, error)
+	CreateRecipient(instanceID string, req *RecipientCreateRequest) (*Recipient, error)
+	DeleteRecipient(instanceID string, recipientID int) error
+	ListIntegrations(instanceID string) ([]Integration, error)
+	CreateIntegration(instanceID, integrationType string, params map[string]string) (*IntegrationCreateResponse, error)
+	DeleteIntegration(instanceID string, integrationID int) error
+	ListLogIntegrations(instanceID string) ([]Integration, error)
+	CreateLogIntegration(instanceID, integrationType string, params map[string]string) (*IntegrationCreateResponse, error)
+	DeleteLogIntegration(instanceID string, integrationID int) error
+	GetFirewall(instanceID string) ([]FirewallRule, error)
+	UpdateFirewall(instanceID string, rules []FirewallRule) error
+
+	ListVPCs() ([]VPC, error)
+	ListVPCsWithOptions(opts ListOptions) ([]VPC, error)
+	GetVPC(id int) (*VPC, error)
+
+	ListPlans(backend string) ([]Plan, error)
+	GetPlan(name string) (*Plan, error)
+	ListRegions(provider string) ([]Region, error)
+	ListVersions() ([]string, error)
+	GetAccount() (*Account, error)
+	ServerTime() (time.Time, error)
+	LatestRelease() (string, error)
+}
+
+var _ ClientAPI = (*Client)(nil)
@@ -3,34 +3,40 @@ package client
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
+	"strconv"
 )
 
 var BaseURL = "https://customer.cloudamqp.com/api"
 
 var MetadataURL = "https://api.cloudamqp.com/api"
 
+// APIURL returns the API endpoint clients created with New talk to:
+// CLOUDAMQP_URL when set, BaseURL otherwise.
+func APIURL() string {
+	if envURL := os.Getenv("CLOUDAMQP_URL"); envURL != "" {
+		return envURL
+	}
+	return BaseURL
+}
+
 type Client struct {
 	apiKey     string
 	baseURL    string
-	httpClient *http.Client
+	httpClient HTTPDoer
 	version    string
 }
 
 func New(apiKey, version string) *Client {
-	baseURL := "https://customer.cloudamqp.com/api"
-	if envURL := os.Getenv("CLOUDAMQP_URL"); envURL != "" {
-		baseURL = envURL
-	}
 	return &Client{
 		apiKey:     apiKey,
-		baseURL:    baseURL,
-		httpClient: &http.Client{},
+		baseURL:    APIURL(),
+		httpClient: newHTTPClient(),
 		version:    version,
 	}
 }
@@ -39,7 +45,7 @@ func NewWithBaseURL(apiKey, baseURL, version string) *Client {
 	return &Client{
 		apiKey:     apiKey,
 		baseURL:    baseURL,
-		httpClient: &http.Client{},
+		httpClient: newHTTPClient(),
 		version:    version,
 	}
 }
@@ -55,68 +61,168 @@ func NewWithHTTPClient(apiKey, baseURL, version string, httpClient *http.Client)
 	}
 }
 
+// NewWithDoer creates a new client that sends requests through doer.
+func NewWithDoer(apiKey, baseURL, version string, doer HTTPDoer) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		httpClient: doer,
+		version:    version,
+	}
+}
+
+// TestAPIKey is the API key of clients made by NewForTest.
+const TestAPIKey = "test-api-key"
+
+// NewForTest creates a client for tests against a local server such as
+// httptest.NewServer. It authenticates with TestAPIKey, reports version
+// "test" and connects directly, ignoring proxy settings in the environment.
+func NewForTest(baseURL string) *Client {
+	return NewWithHTTPClient(TestAPIKey, baseURL, "test", &http.Client{})
+}
+
+// APIError is returned when the API responds with an error status.
+type APIError struct {
+	StatusCode int
+	Message    string
+	// RequestID is the X-Request-Id response header, if any. Include it
+	// when contacting support.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request id: %s)", e.RequestID)
+	}
+	return msg
+}
+
+// IsNotFound reports whether err is an APIError for a 404 Not Found response.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// newAPIError builds an APIError from an error response, preferring the
+// message in a JSON {"error": "..."} body over the raw body.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	message := string(body)
+	var errorResp struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.Error != "" {
+		message = errorResp.Error
+	}
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+}
+
 func (c *Client) makeRequest(method, endpoint string, body any) ([]byte, error) {
-	var reqBody io.Reader
+	respBody, _, err := c.doRequest(method, c.baseURL+endpoint, body, nil)
+	return respBody, err
+}
+
+// doRequest sends an authenticated request to requestURL, with the extra
+// headers given, and returns the response body and headers. Requests wait
+// for the rate limiter, and failures covered by RetryOn, such as 429 Too
+// Many Requests, are retried after Retry-After with the same headers.
+func (c *Client) doRequest(method, requestURL string, body any, header http.Header) ([]byte, http.Header, error) {
+	var payload []byte
 	var contentType string
 
 	if body != nil {
 		switch v := body.(type) {
 		case url.Values:
 			contentType = "application/x-www-form-urlencoded"
-			reqBody = strings.NewReader(v.Encode())
+			payload = []byte(v.Encode())
 		default:
 			contentType = "application/json"
 			jsonData, err := json.Marshal(body)
 			if err != nil {
-				return nil, fmt.Errorf("failed to marshal request body: %w", err)
+				return nil, nil, fmt.Errorf("failed to marshal request body: %w", err)
 			}
-			reqBody = bytes.NewReader(jsonData)
+			payload = jsonData
 		}
 	}
 
-	req, err := http.NewRequest(method, c.baseURL+endpoint, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	for attempt := 0; ; attempt++ {
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(payload)
+		}
 
-	req.SetBasicAuth("", c.apiKey)
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
-	}
-	req.Header.Set("User-Agent", fmt.Sprintf("cloudamqp-cli/%s", c.version))
+		req, err := http.NewRequestWithContext(RequestContext, method, requestURL, reqBody)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		req.SetBasicAuth("", c.apiKey)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		req.Header.Set("User-Agent", fmt.Sprintf("cloudamqp-cli/%s", c.version))
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+		if err := waitForRateLimit(req.Context()); err != nil {
+			return nil, nil, err
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if attempt < maxRetries && RetryOn.retryError(req, err) {
+				if err := sleep(req.Context(), retryAfter(nil, attempt)); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+			return nil, nil, fmt.Errorf("request failed: %w", err)
+		}
 
-	if resp.StatusCode >= 400 {
-		var errorResp struct {
-			Error string `json:"error"`
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			// The server has started answering, so it has seen the request;
+			// retryError only lets idempotent requests through
+			if attempt < maxRetries && RetryOn.retryError(req, err) {
+				if err := sleep(req.Context(), retryAfter(nil, attempt)); err != nil {
+					return nil, nil, err
+				}
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to read response: %w", err)
 		}
-		if err := json.Unmarshal(respBody, &errorResp); err == nil && errorResp.Error != "" {
-			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, errorResp.Error)
+
+		if attempt < maxRetries && RetryOn.retryResponse(req, resp) {
+			if err := sleep(req.Context(), retryAfter(resp.Header, attempt)); err != nil {
+				return nil, nil, err
+			}
+			continue
 		}
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBody))
-	}
 
-	return respBody, nil
+		if resp.StatusCode >= 400 {
+			return nil, nil, newAPIError(resp, respBody)
+		}
+
+		return respBody, resp.Header, nil
+	}
 }
 
 func (c *Client) makeExternalRequest(method, requestURL string) ([]byte, error) {
-	req, err := http.NewRequest(method, requestURL, nil)
+	req, err := http.NewRequestWithContext(RequestContext, method, requestURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", fmt.Sprintf("cloudamqp-cli/%s", c.version))
 
+	if err := waitForRateLimit(req.Context()); err != nil {
+		return nil, err
+	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -129,7 +235,11 @@ func (c *Client) makeExternalRequest(method, requestURL string) ([]byte, error)
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    string(respBody),
+			RequestID:  resp.Header.Get("X-Request-Id"),
+		}
 	}
 
 	return respBody, nil
@@ -167,6 +277,35 @@ func (c *Client) ListNodes(instanceID string) ([]Node, error) {
 	return nodes, nil
 }
 
+// NodeDetails is a single node with the runtime details that ListNodes
+// leaves out. Sizes are in bytes and Uptime in milliseconds.
+type NodeDetails struct {
+	Node
+	Uptime        int64            `json:"uptime"`
+	MemUsed       int64            `json:"mem_used"`
+	MemLimit      int64            `json:"mem_limit"`
+	Memory        map[string]int64 `json:"memory,omitempty"`
+	DiskFree      int64            `json:"disk_free"`
+	DiskFreeLimit int64            `json:"disk_free_limit"`
+	Partitions    []string         `json:"partitions"`
+}
+
+// GetNode returns the details of the node named node in the instance.
+func (c *Client) GetNode(id int, node string) (*NodeDetails, error) {
+	endpoint := "/instances/" + strconv.Itoa(id) + "/nodes/" + url.PathEscape(node)
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var details NodeDetails
+	if err := json.Unmarshal(respBody, &details); err != nil {
+		return nil, err
+	}
+
+	return &details, nil
+}
+
 // Plugin management
 type Plugin struct {
 	Name        string `json:"name"`
@@ -208,16 +347,100 @@ func (c *Client) DisablePlugin(instanceID, pluginName string) error {
 }
 
 // Account operations
-func (c *Client) RotatePassword(instanceID string) error {
+// Password rotation states reported by PasswordRotation.Status.
+const (
+	PasswordRotationPending  = "pending"
+	PasswordRotationComplete = "complete"
+)
+
+// PasswordRotation is the state of an instance password rotation. URL is the
+// connection URL including Password once the new credentials are known.
+type PasswordRotation struct {
+	Status   string `json:"status"`
+	Password string `json:"password,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// RotatePassword starts a password rotation. The API may answer with the new
+// credentials; when it answers with an empty body the rotation is reported
+// as pending and GetPasswordRotationStatus tells when it is done.
+func (c *Client) RotatePassword(instanceID string) (*PasswordRotation, error) {
 	endpoint := "/instances/" + instanceID + "/account/rotate-password"
-	_, err := c.makeRequest("POST", endpoint, nil)
-	return err
+	respBody, err := c.makeRequest("POST", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rotation := &PasswordRotation{Status: PasswordRotationPending}
+	if len(bytes.TrimSpace(respBody)) > 0 {
+		if err := json.Unmarshal(respBody, rotation); err != nil {
+			return nil, err
+		}
+	}
+	if rotation.Password == "" && rotation.URL != "" {
+		rotation.Password = urlPassword(rotation.URL)
+	}
+	if rotation.Status == "" {
+		rotation.Status = PasswordRotationPending
+	}
+	return rotation, nil
 }
 
-func (c *Client) RotateInstanceAPIKey(instanceID string) error {
+// GetPasswordRotationStatus reports the current credentials of an instance.
+// There is no dedicated status endpoint, so the rotation is complete once the
+// instance is ready again; callers compare Password with the password from
+// before the rotation to tell the new credentials from the old ones.
+func (c *Client) GetPasswordRotationStatus(instanceID string) (*PasswordRotation, error) {
+	respBody, err := c.makeRequest("GET", "/instances/"+instanceID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var instance Instance
+	if err := json.Unmarshal(respBody, &instance); err != nil {
+		return nil, err
+	}
+
+	rotation := &PasswordRotation{
+		Status:   PasswordRotationPending,
+		Password: urlPassword(instance.URL),
+		URL:      instance.URL,
+	}
+	if instance.Ready {
+		rotation.Status = PasswordRotationComplete
+	}
+	return rotation, nil
+}
+
+// urlPassword returns the password in a connection URL, or "" if it has none.
+func urlPassword(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return ""
+	}
+	password, _ := u.User.Password()
+	return password
+}
+
+// RotateInstanceAPIKey replaces the API key of an instance and returns the
+// new key, or "" when the API answers without one.
+func (c *Client) RotateInstanceAPIKey(instanceID string) (string, error) {
 	endpoint := "/instances/" + instanceID + "/account/rotate-apikey"
-	_, err := c.makeRequest("POST", endpoint, nil)
-	return err
+	respBody, err := c.makeRequest("POST", endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return "", nil
+	}
+
+	var resp struct {
+		APIKey string `json:"apikey"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return "", err
+	}
+	return resp.APIKey, nil
 }
 
 // Action operations
@@ -239,10 +462,19 @@ type UpgradeRequest struct {
 	Version string `json:"version"`
 }
 
+// Message broker backends reported in VersionInfo.Backend.
+const (
+	BackendRabbitMQ = "rabbitmq"
+	BackendLavinMQ  = "lavinmq"
+)
+
 type VersionInfo struct {
 	RabbitMQVersions []string `json:"rabbitmq_versions"`
 	ErlangVersions   []string `json:"erlang_versions"`
 	LavinMQVersions  []string `json:"lavinmq_versions"`
+	// Backend is not part of the API response; GetAvailableVersions sets it
+	// from which version lists are present.
+	Backend string `json:"-"`
 }
 
 func (c *Client) ToggleHiPE(instanceID string, req *HiPERequest) error {
@@ -341,6 +573,11 @@ func (c *Client) GetAvailableVersions(instanceID string) (*VersionInfo, error) {
 		return nil, err
 	}
 
+	versions.Backend = BackendRabbitMQ
+	if len(versions.LavinMQVersions) > 0 {
+		versions.Backend = BackendLavinMQ
+	}
+
 	return &versions, nil
 }
 
@@ -22,7 +22,7 @@ func TestListInstancesVCR(t *testing.T) {
 	// Add sanitization filters
 	r.AddFilter(func(i *cassette.Interaction) error {
 		delete(i.Request.Headers, "Authorization")
-		i.Response.Body = sanitizeResponseBody(i.Response.Body)
+		i.Response.Body = string(SanitizeBody([]byte(i.Response.Body)))
 		delete(i.Response.Headers, "Set-Cookie")
 		return nil
 	})
@@ -43,6 +43,47 @@ func TestListInstancesVCR(t *testing.T) {
 	t.Logf("✓ Listed %d instances", len(instances))
 }
 
+// TestListInstancesPaginatedVCR tests that every page linked with rel="next"
+// is fetched. The cassette is hand-written, so it is always replayed.
+func TestListInstancesPaginatedVCR(t *testing.T) {
+	r, err := recorder.NewAsMode("fixtures/list_instances_paginated", recorder.ModeReplaying, nil)
+	require.NoError(t, err)
+	defer r.Stop()
+
+	httpClient := &http.Client{Transport: r}
+	client := NewWithHTTPClient("vcr-replay-mode", "https://customer.cloudamqp.com/api", "test", httpClient)
+
+	instances, err := client.ListInstancesWithOptions(ListOptions{PageSize: 2})
+
+	require.NoError(t, err)
+	require.Len(t, instances, 3)
+	assert.Equal(t, 359560, instances[0].ID)
+	assert.Equal(t, 359558, instances[2].ID)
+}
+
+// TestListInstancesPlanFieldsVCR tests that plan tier, node count and
+// backend are parsed from list results that include them, and left zero for
+// those that don't. The cassette is hand-written, so it is always replayed.
+func TestListInstancesPlanFieldsVCR(t *testing.T) {
+	r, err := recorder.NewAsMode("fixtures/list_instances_plan_fields", recorder.ModeReplaying, nil)
+	require.NoError(t, err)
+	defer r.Stop()
+
+	httpClient := &http.Client{Transport: r}
+	client := NewWithHTTPClient("vcr-replay-mode", "https://customer.cloudamqp.com/api", "test", httpClient)
+
+	instances, err := client.ListInstances()
+
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	assert.Equal(t, "dedicated", instances[0].PlanTier)
+	assert.Equal(t, 3, instances[0].Nodes)
+	assert.Equal(t, "rabbitmq", instances[0].Backend)
+	assert.Empty(t, instances[1].PlanTier)
+	assert.Zero(t, instances[1].Nodes)
+	assert.Empty(t, instances[1].Backend)
+}
+
 // TestGetInstanceVCR tests getting a specific instance
 func TestGetInstanceVCR(t *testing.T) {
 	r, err := recorder.New("fixtures/get_instance")
@@ -53,7 +94,7 @@ func TestGetInstanceVCR(t *testing.T) {
 
 	r.AddFilter(func(i *cassette.Interaction) error {
 		delete(i.Request.Headers, "Authorization")
-		i.Response.Body = sanitizeResponseBody(i.Response.Body)
+		i.Response.Body = string(SanitizeBody([]byte(i.Response.Body)))
 		delete(i.Response.Headers, "Set-Cookie")
 		return nil
 	})
@@ -87,7 +128,7 @@ func TestListRegionsVCR(t *testing.T) {
 
 	r.AddFilter(func(i *cassette.Interaction) error {
 		delete(i.Request.Headers, "Authorization")
-		i.Response.Body = sanitizeResponseBody(i.Response.Body)
+		i.Response.Body = string(SanitizeBody([]byte(i.Response.Body)))
 		delete(i.Response.Headers, "Set-Cookie")
 		return nil
 	})
@@ -124,7 +165,7 @@ func TestListPlansVCR(t *testing.T) {
 
 	r.AddFilter(func(i *cassette.Interaction) error {
 		delete(i.Request.Headers, "Authorization")
-		i.Response.Body = sanitizeResponseBody(i.Response.Body)
+		i.Response.Body = string(SanitizeBody([]byte(i.Response.Body)))
 		delete(i.Response.Headers, "Set-Cookie")
 		return nil
 	})
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InstanceEvent is an entry in the audit log that concerns one instance,
+// such as an upgrade, restart or config change.
+type InstanceEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Message string    `json:"message"`
+}
+
+// The audit log columns ListInstanceEvents reads, by the names they may
+// appear under in the CSV header.
+var (
+	auditTimeColumns     = []string{"time", "timestamp", "created_at", "date"}
+	auditTypeColumns     = []string{"type", "event", "action"}
+	auditMessageColumns  = []string{"message", "description", "details"}
+	auditInstanceColumns = []string{"instance_id", "instance", "resource_id"}
+)
+
+var auditTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+}
+
+// ListInstanceEvents returns the events of the instance at or after since,
+// oldest first. The API has no per-instance events endpoint, so this reads
+// the account audit log, one month at a time, and keeps the rows for the
+// instance. A zero since reads the current month.
+func (c *Client) ListInstanceEvents(id int, since time.Time) ([]InstanceEvent, error) {
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if !since.IsZero() {
+		since = since.UTC()
+		month = time.Date(since.Year(), since.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	var events []InstanceEvent
+	for ; !month.After(now); month = month.AddDate(0, 1, 0) {
+		data, err := c.GetAuditLogCSV(month.Format("2006-01"))
+		if err != nil {
+			return nil, err
+		}
+		monthEvents, err := parseInstanceEvents(data, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range monthEvents {
+			if !e.Time.Before(since) {
+				events = append(events, e)
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events, nil
+}
+
+// parseInstanceEvents returns the rows of an audit log CSV that belong to
+// the instance.
+func parseInstanceEvents(data string, id int) ([]InstanceEvent, error) {
+	if strings.TrimSpace(data) == "" {
+		return nil, nil
+	}
+
+	r := csv.NewReader(strings.NewReader(data))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse audit log: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	timeCol := auditColumn(header, auditTimeColumns)
+	instanceCol := auditColumn(header, auditInstanceColumns)
+	if timeCol < 0 || instanceCol < 0 {
+		return nil, fmt.Errorf("unexpected audit log format: no time or instance column in %q", strings.Join(header, ","))
+	}
+	typeCol := auditColumn(header, auditTypeColumns)
+	messageCol := auditColumn(header, auditMessageColumns)
+
+	field := func(record []string, col int) string {
+		if col < 0 || col >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[col])
+	}
+
+	instanceID := strconv.Itoa(id)
+	var events []InstanceEvent
+	for _, record := range records[1:] {
+		if field(record, instanceCol) != instanceID {
+			continue
+		}
+		t, err := parseAuditTime(field(record, timeCol))
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, InstanceEvent{
+			Time:    t,
+			Type:    field(record, typeCol),
+			Message: field(record, messageCol),
+		})
+	}
+	return events, nil
+}
+
+// auditColumn returns the index of the first header matching one of names,
+// or -1. "Instance ID" and "instance-id" both match instance_id.
+func auditColumn(header []string, names []string) int {
+	normalize := strings.NewReplacer(" ", "_", "-", "_")
+	for _, name := range names {
+		for i, h := range header {
+			if strings.EqualFold(normalize.Replace(strings.TrimSpace(h)), name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseAuditTime(s string) (time.Time, error) {
+	for _, layout := range auditTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unexpected time %q in audit log", s)
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListInstanceEvents(t *testing.T) {
+	now := time.Now().UTC()
+	recent := now.Add(-time.Hour).Format(time.RFC3339)
+	old := now.Add(-48 * time.Hour).Format(time.RFC3339)
+
+	var months []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auditlog/csv", r.URL.Path)
+		months = append(months, r.URL.Query().Get("timestamp"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Time,Instance ID,Type,Message\n" +
+			recent + ",1234,restart,RabbitMQ restarted\n" +
+			old + ",1234,upgrade,Upgraded to 3.13.7\n" +
+			recent + ",5678,restart,Other instance\n"))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	events, err := client.ListInstanceEvents(1234, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "restart", events[0].Type)
+	assert.Equal(t, "RabbitMQ restarted", events[0].Message)
+	assert.Contains(t, months, now.Format("2006-01"))
+}
+
+func TestParseInstanceEvents(t *testing.T) {
+	events, err := parseInstanceEvents("created_at,instance,action,description\n"+
+		"2024-05-02 10:00:00,1234,config,Changed heartbeat\n"+
+		"2024-05-01 09:00:00 UTC,1234,restart,Restarted\n", 1234)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "config", events[0].Type)
+	assert.Equal(t, time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), events[1].Time)
+
+	_, err = parseInstanceEvents("user,email\nx,y\n", 1234)
+	assert.ErrorContains(t, err, "unexpected audit log format")
+
+	_, err = parseInstanceEvents("time,instance_id\nyesterday,1234\n", 1234)
+	assert.ErrorContains(t, err, `unexpected time "yesterday"`)
+}
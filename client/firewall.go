@@ -0,0 +1,37 @@
+package client
+
+import (
+	"encoding/json"
+)
+
+type FirewallRule struct {
+	IP          string   `json:"ip"`
+	Services    []string `json:"services"`
+	Ports       []int    `json:"ports"`
+	Description string   `json:"description,omitempty"`
+}
+
+func (c *Client) GetFirewall(instanceID string) ([]FirewallRule, error) {
+	endpoint := "/instances/" + instanceID + "/security/firewall"
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []FirewallRule
+	if err := json.Unmarshal(respBody, &rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// UpdateFirewall replaces the full set of firewall rules for the instance.
+func (c *Client) UpdateFirewall(instanceID string, rules []FirewallRule) error {
+	endpoint := "/instances/" + instanceID + "/security/firewall"
+	if rules == nil {
+		rules = []FirewallRule{}
+	}
+	_, err := c.makeRequest("PUT", endpoint, rules)
+	return err
+}
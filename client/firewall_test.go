@@ -0,0 +1,72 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFirewall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/instances/1234/security/firewall", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"ip":"10.0.0.0/24","services":["AMQPS","HTTPS"],"ports":[4567],"description":"office"}]`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	rules, err := client.GetFirewall("1234")
+
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "10.0.0.0/24", rules[0].IP)
+	assert.Equal(t, []string{"AMQPS", "HTTPS"}, rules[0].Services)
+	assert.Equal(t, []int{4567}, rules[0].Ports)
+	assert.Equal(t, "office", rules[0].Description)
+}
+
+func TestUpdateFirewall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/instances/1234/security/firewall", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body, _ := io.ReadAll(r.Body)
+		var rules []FirewallRule
+		assert.NoError(t, json.Unmarshal(body, &rules))
+		assert.Len(t, rules, 2)
+		assert.Equal(t, "1.2.3.4/32", rules[1].IP)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	err := client.UpdateFirewall("1234", []FirewallRule{
+		{IP: "10.0.0.0/24", Services: []string{"AMQPS"}},
+		{IP: "1.2.3.4/32", Services: []string{"HTTPS"}, Description: "office"},
+	})
+	assert.NoError(t, err)
+}
+
+func TestUpdateFirewall_Empty(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "[]", string(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	err := client.UpdateFirewall("1234", nil)
+	assert.NoError(t, err)
+}
@@ -1,9 +1,15 @@
 package client
 
 import (
+	"bytes"
+	"crypto/rand"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 type Instance struct {
@@ -20,6 +26,14 @@ type Instance struct {
 	RMQVersion       string   `json:"rmq_version"`
 	HostnameExternal string   `json:"hostname_external"`
 	HostnameInternal string   `json:"hostname_internal"`
+	// CreatedAt is zero when the API doesn't report it.
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	// PlanTier, Nodes and Backend save a plan lookup per instance. The list
+	// endpoint doesn't return them for every account, and they are zero
+	// when missing.
+	PlanTier string `json:"plan_tier,omitempty"`
+	Nodes    int    `json:"nodes,omitempty"`
+	Backend  string `json:"backend,omitempty"`
 }
 
 type CopySettings struct {
@@ -36,6 +50,11 @@ type InstanceCreateRequest struct {
 	VPCSubnet    string        `json:"vpc_subnet,omitempty"`
 	VPCID        *int          `json:"vpc_id,omitempty"`
 	CopySettings *CopySettings `json:"copy_settings,omitempty"`
+	// IdempotencyKey is sent as the Idempotency-Key header, so a retried
+	// create doesn't make a second instance. CreateInstance generates one
+	// when empty and stores it here, so retries with the same request
+	// reuse it.
+	IdempotencyKey string `json:"-"`
 }
 
 type InstanceCreateResponse struct {
@@ -51,19 +70,18 @@ type InstanceUpdateRequest struct {
 }
 
 func (c *Client) ListInstances() ([]Instance, error) {
-	respBody, err := c.makeRequest("GET", "/instances", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var instances []Instance
-	if err := json.Unmarshal(respBody, &instances); err != nil {
-		return nil, err
-	}
+	return c.ListInstancesWithOptions(ListOptions{})
+}
 
-	return instances, nil
+// ListInstancesWithOptions lists instances, following every page unless
+// opts.Limit is reached first.
+func (c *Client) ListInstancesWithOptions(opts ListOptions) ([]Instance, error) {
+	return listAll[Instance](c, "/instances", opts)
 }
 
+// GetInstance returns the instance with the given ID. An empty response,
+// which the API sometimes sends while an instance is being set up, gives a
+// nil instance and no error.
 func (c *Client) GetInstance(id int) (*Instance, error) {
 	endpoint := "/instances/" + strconv.Itoa(id)
 	respBody, err := c.makeRequest("GET", endpoint, nil)
@@ -71,6 +89,10 @@ func (c *Client) GetInstance(id int) (*Instance, error) {
 		return nil, err
 	}
 
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return nil, nil
+	}
+
 	var instance Instance
 	if err := json.Unmarshal(respBody, &instance); err != nil {
 		return nil, err
@@ -79,41 +101,59 @@ func (c *Client) GetInstance(id int) (*Instance, error) {
 	return &instance, nil
 }
 
-func (c *Client) CreateInstance(req *InstanceCreateRequest) (*InstanceCreateResponse, error) {
-	var body any
+// NewIdempotencyKey returns a random version 4 UUID for the Idempotency-Key
+// header.
+func NewIdempotencyKey() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
 
-	// Use JSON format when copy_settings is present (required by API)
+// Body returns the request body CreateInstance sends: the request as JSON
+// when copy settings are given, which the API requires, and form fields
+// otherwise.
+func (req *InstanceCreateRequest) Body() any {
 	if req.CopySettings != nil {
-		body = req
-	} else {
-		// Use form encoding for backward compatibility
-		formData := url.Values{}
-		formData.Set("name", req.Name)
-		formData.Set("plan", req.Plan)
-		formData.Set("region", req.Region)
+		return req
+	}
 
-		if len(req.Tags) > 0 {
-			for _, tag := range req.Tags {
-				formData.Add("tags[]", tag)
-			}
-		}
+	// Use form encoding for backward compatibility
+	formData := url.Values{}
+	formData.Set("name", req.Name)
+	formData.Set("plan", req.Plan)
+	formData.Set("region", req.Region)
 
-		if req.RMQVersion != "" {
-			formData.Set("rmq_version", req.RMQVersion)
+	if len(req.Tags) > 0 {
+		for _, tag := range req.Tags {
+			formData.Add("tags[]", tag)
 		}
+	}
 
-		if req.VPCSubnet != "" {
-			formData.Set("vpc_subnet", req.VPCSubnet)
-		}
+	if req.RMQVersion != "" {
+		formData.Set("rmq_version", req.RMQVersion)
+	}
 
-		if req.VPCID != nil {
-			formData.Set("vpc_id", strconv.Itoa(*req.VPCID))
-		}
+	if req.VPCSubnet != "" {
+		formData.Set("vpc_subnet", req.VPCSubnet)
+	}
 
-		body = formData
+	if req.VPCID != nil {
+		formData.Set("vpc_id", strconv.Itoa(*req.VPCID))
 	}
 
-	respBody, err := c.makeRequest("POST", "/instances", body)
+	return formData
+}
+
+func (c *Client) CreateInstance(req *InstanceCreateRequest) (*InstanceCreateResponse, error) {
+	body := req.Body()
+
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = NewIdempotencyKey()
+	}
+	header := http.Header{"Idempotency-Key": {req.IdempotencyKey}}
+	respBody, _, err := c.doRequest("POST", c.baseURL+"/instances", body, header)
 	if err != nil {
 		return nil, err
 	}
@@ -126,9 +166,8 @@ func (c *Client) CreateInstance(req *InstanceCreateRequest) (*InstanceCreateResp
 	return &createResp, nil
 }
 
-func (c *Client) UpdateInstance(id int, req *InstanceUpdateRequest) error {
-	endpoint := "/instances/" + strconv.Itoa(id)
-
+// Form returns the form fields UpdateInstance sends.
+func (req *InstanceUpdateRequest) Form() url.Values {
 	formData := url.Values{}
 	if req.Name != "" {
 		formData.Set("name", req.Name)
@@ -141,8 +180,12 @@ func (c *Client) UpdateInstance(id int, req *InstanceUpdateRequest) error {
 			formData.Add("tags[]", tag)
 		}
 	}
+	return formData
+}
 
-	_, err := c.makeRequest("PUT", endpoint, formData)
+func (c *Client) UpdateInstance(id int, req *InstanceUpdateRequest) error {
+	endpoint := "/instances/" + strconv.Itoa(id)
+	_, err := c.makeRequest("PUT", endpoint, req.Form())
 	return err
 }
 
@@ -157,15 +200,124 @@ type DiskResizeRequest struct {
 	AllowDowntime bool `json:"allow_downtime,omitempty"`
 }
 
-func (c *Client) ResizeInstanceDisk(id int, req *DiskResizeRequest) error {
-	endpoint := "/instances/" + strconv.Itoa(id) + "/disk"
-
+// Form returns the form fields ResizeInstanceDisk sends.
+func (req *DiskResizeRequest) Form() url.Values {
 	formData := url.Values{}
 	formData.Set("extra_disk_size", strconv.Itoa(req.ExtraDiskSize))
 	if req.AllowDowntime {
 		formData.Set("allow_downtime", "true")
 	}
+	return formData
+}
 
-	_, err := c.makeRequest("PUT", endpoint, formData)
+func (c *Client) ResizeInstanceDisk(id int, req *DiskResizeRequest) error {
+	endpoint := "/instances/" + strconv.Itoa(id) + "/disk"
+	_, err := c.makeRequest("PUT", endpoint, req.Form())
 	return err
 }
+
+// ResizeDisk expands the extra disk on every node of the instance to sizeGB
+// without allowing downtime. It never changes the instance plan.
+func (c *Client) ResizeDisk(id int, sizeGB int) error {
+	return c.ResizeInstanceDisk(id, &DiskResizeRequest{ExtraDiskSize: sizeGB})
+}
+
+// InstanceHealthy reports whether the management API of the instance
+// answers, which means the broker is configured and accepts changes. An
+// instance that is not ready yet, or whose management API does not respond
+// successfully, is reported as not healthy without an error.
+func (c *Client) InstanceHealthy(id int) (bool, error) {
+	instance, err := c.GetInstance(id)
+	if err != nil {
+		return false, err
+	}
+	if instance == nil || !instance.Ready {
+		return false, nil
+	}
+
+	overviewURL, user, password, err := managementOverviewURL(instance)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequestWithContext(RequestContext, "GET", overviewURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(user, password)
+	req.Header.Set("User-Agent", fmt.Sprintf("cloudamqp-cli/%s", c.version))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// The management API is not reachable yet while the broker starts
+		return false, nil
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// managementOverviewURL returns the management API overview endpoint of the
+// instance and the credentials from its connection URL.
+func managementOverviewURL(instance *Instance) (string, string, string, error) {
+	connURL, err := url.Parse(instance.URL)
+	if err != nil || connURL.User == nil {
+		return "", "", "", fmt.Errorf("instance %d has no usable connection URL", instance.ID)
+	}
+	host := instance.HostnameExternal
+	if host == "" {
+		host = connURL.Hostname()
+	}
+	password, _ := connURL.User.Password()
+	return "https://" + host + "/api/overview", connURL.User.Username(), password, nil
+}
+
+// Protocols of the connection URLs returned by InstanceURLs.
+const (
+	ProtocolAMQP       = "amqp"
+	ProtocolAMQPS      = "amqps"
+	ProtocolHTTPS      = "https"
+	ProtocolManagement = "management"
+)
+
+// InstanceURLs derives the connection URLs of an instance from its AMQP URL,
+// keyed by protocol: amqp and amqps for clients, https for the HTTP API and
+// management for the management interface, which asks for credentials itself.
+func InstanceURLs(instance *Instance) (map[string]string, error) {
+	connURL, err := url.Parse(instance.URL)
+	if err != nil || connURL.User == nil || connURL.Host == "" {
+		return nil, fmt.Errorf("instance %d has no usable connection URL", instance.ID)
+	}
+
+	// Drop any port so each scheme uses its default port
+	withScheme := func(scheme string) string {
+		u := *connURL
+		u.Scheme = scheme
+		u.Host = connURL.Hostname()
+		return u.String()
+	}
+
+	host := instance.HostnameExternal
+	if host == "" {
+		host = connURL.Hostname()
+	}
+	api := url.URL{Scheme: "https", User: connURL.User, Host: host, Path: "/api/"}
+
+	return map[string]string{
+		ProtocolAMQP:       withScheme("amqp"),
+		ProtocolAMQPS:      withScheme("amqps"),
+		ProtocolHTTPS:      api.String(),
+		ProtocolManagement: "https://" + host + "/",
+	}, nil
+}
+
+// GetInstanceURLs returns the connection URLs of an instance keyed by
+// protocol, see InstanceURLs.
+func (c *Client) GetInstanceURLs(id int) (map[string]string, error) {
+	instance, err := c.GetInstance(id)
+	if err != nil {
+		return nil, err
+	}
+	return InstanceURLs(instance)
+}
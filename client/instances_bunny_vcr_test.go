@@ -23,7 +23,7 @@ func TestCreateInstanceBunny1(t *testing.T) {
 	// Add filter to sanitize sensitive data
 	r.AddFilter(func(i *cassette.Interaction) error {
 		delete(i.Request.Headers, "Authorization")
-		i.Response.Body = sanitizeResponseBody(i.Response.Body)
+		i.Response.Body = string(SanitizeBody([]byte(i.Response.Body)))
 		delete(i.Response.Headers, "Set-Cookie")
 		return nil
 	})
@@ -70,7 +70,7 @@ func TestUpdateInstanceBunny1ToHare1(t *testing.T) {
 	// Add filter to sanitize sensitive data
 	r.AddFilter(func(i *cassette.Interaction) error {
 		delete(i.Request.Headers, "Authorization")
-		i.Response.Body = sanitizeResponseBody(i.Response.Body)
+		i.Response.Body = string(SanitizeBody([]byte(i.Response.Body)))
 		delete(i.Response.Headers, "Set-Cookie")
 		return nil
 	})
@@ -124,7 +124,7 @@ func TestDeleteInstanceBunny1(t *testing.T) {
 	// Add filter to sanitize sensitive data
 	r.AddFilter(func(i *cassette.Interaction) error {
 		delete(i.Request.Headers, "Authorization")
-		i.Response.Body = sanitizeResponseBody(i.Response.Body)
+		i.Response.Body = string(SanitizeBody([]byte(i.Response.Body)))
 		delete(i.Response.Headers, "Set-Cookie")
 		return nil
 	})
@@ -1,10 +1,8 @@
 package client
 
 import (
-	"encoding/json"
 	"net/http"
 	"os"
-	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,43 +10,6 @@ import (
 	"gopkg.in/dnaeon/go-vcr.v2/recorder"
 )
 
-// sanitizeResponseBody removes sensitive data from API responses
-func sanitizeResponseBody(body string) string {
-	var data map[string]interface{}
-	if err := json.Unmarshal([]byte(body), &data); err != nil {
-		return body
-	}
-
-	// Sanitize sensitive fields
-	if _, ok := data["apikey"]; ok {
-		data["apikey"] = "REDACTED"
-	}
-
-	// Replace credentials in AMQP/AMQPS URLs
-	credRegex := regexp.MustCompile(`://([^:]+):([^@]+)@`)
-
-	// Sanitize single URL field
-	if urlStr, ok := data["url"].(string); ok {
-		data["url"] = credRegex.ReplaceAllString(urlStr, "://REDACTED:REDACTED@")
-	}
-
-	// Sanitize urls object (contains external/internal URLs)
-	if urls, ok := data["urls"].(map[string]interface{}); ok {
-		for key, val := range urls {
-			if urlStr, ok := val.(string); ok {
-				urls[key] = credRegex.ReplaceAllString(urlStr, "://REDACTED:REDACTED@")
-			}
-		}
-	}
-
-	sanitized, err := json.Marshal(data)
-	if err != nil {
-		return body
-	}
-
-	return string(sanitized)
-}
-
 // TestCreateInstanceVCR tests the CreateInstance method using VCR to record/replay HTTP interactions
 func TestCreateInstanceVCR(t *testing.T) {
 	// Create a VCR recorder
@@ -64,7 +25,7 @@ func TestCreateInstanceVCR(t *testing.T) {
 		delete(i.Request.Headers, "Authorization")
 
 		// Sanitize sensitive data in response body (API keys, passwords)
-		i.Response.Body = sanitizeResponseBody(i.Response.Body)
+		i.Response.Body = string(SanitizeBody([]byte(i.Response.Body)))
 
 		// Remove session cookies
 		delete(i.Response.Headers, "Set-Cookie")
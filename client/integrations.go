@@ -0,0 +1,109 @@
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Integration is a third-party metrics or log integration. Each integration
+// type has its own set of parameters, which are kept in Params.
+type Integration struct {
+	ID     int
+	Type   string
+	Params map[string]interface{}
+}
+
+func (i *Integration) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if id, ok := raw["id"].(float64); ok {
+		i.ID = int(id)
+	}
+	if t, ok := raw["type"].(string); ok {
+		i.Type = t
+	}
+	delete(raw, "id")
+	delete(raw, "type")
+	i.Params = raw
+
+	return nil
+}
+
+func (i Integration) MarshalJSON() ([]byte, error) {
+	data := make(map[string]interface{}, len(i.Params)+2)
+	for k, v := range i.Params {
+		data[k] = v
+	}
+	data["id"] = i.ID
+	data["type"] = i.Type
+	return json.Marshal(data)
+}
+
+type IntegrationCreateResponse struct {
+	ID int `json:"id"`
+}
+
+func (c *Client) listIntegrations(instanceID, kind string) ([]Integration, error) {
+	endpoint := "/instances/" + instanceID + "/integrations/" + kind
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var integrations []Integration
+	if err := json.Unmarshal(respBody, &integrations); err != nil {
+		return nil, err
+	}
+
+	return integrations, nil
+}
+
+func (c *Client) createIntegration(instanceID, kind, integrationType string, params map[string]string) (*IntegrationCreateResponse, error) {
+	endpoint := "/instances/" + instanceID + "/integrations/" + kind + "/" + integrationType
+	respBody, err := c.makeRequest("POST", endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var response IntegrationCreateResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (c *Client) deleteIntegration(instanceID, kind string, integrationID int) error {
+	endpoint := "/instances/" + instanceID + "/integrations/" + kind + "/" + strconv.Itoa(integrationID)
+	_, err := c.makeRequest("DELETE", endpoint, nil)
+	return err
+}
+
+// Metrics integrations
+func (c *Client) ListIntegrations(instanceID string) ([]Integration, error) {
+	return c.listIntegrations(instanceID, "metrics")
+}
+
+func (c *Client) CreateIntegration(instanceID, integrationType string, params map[string]string) (*IntegrationCreateResponse, error) {
+	return c.createIntegration(instanceID, "metrics", integrationType, params)
+}
+
+func (c *Client) DeleteIntegration(instanceID string, integrationID int) error {
+	return c.deleteIntegration(instanceID, "metrics", integrationID)
+}
+
+// Log integrations
+func (c *Client) ListLogIntegrations(instanceID string) ([]Integration, error) {
+	return c.listIntegrations(instanceID, "logs")
+}
+
+func (c *Client) CreateLogIntegration(instanceID, integrationType string, params map[string]string) (*IntegrationCreateResponse, error) {
+	return c.createIntegration(instanceID, "logs", integrationType, params)
+}
+
+func (c *Client) DeleteLogIntegration(instanceID string, integrationID int) error {
+	return c.deleteIntegration(instanceID, "logs", integrationID)
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListIntegrations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/instances/1234/integrations/metrics", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id":3,"type":"datadog","region":"us","api_key":"secret"}]`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	integrations, err := client.ListIntegrations("1234")
+
+	assert.NoError(t, err)
+	assert.Len(t, integrations, 1)
+	assert.Equal(t, 3, integrations[0].ID)
+	assert.Equal(t, "datadog", integrations[0].Type)
+	assert.Equal(t, "us", integrations[0].Params["region"])
+	assert.NotContains(t, integrations[0].Params, "id")
+}
+
+func TestCreateIntegration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/instances/1234/integrations/metrics/datadog", r.URL.Path)
+
+		body, _ := io.ReadAll(r.Body)
+		var params map[string]string
+		assert.NoError(t, json.Unmarshal(body, &params))
+		assert.Equal(t, "XXX", params["api_key"])
+		assert.Equal(t, "us", params["region"])
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 3}`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	resp, err := client.CreateIntegration("1234", "datadog", map[string]string{"api_key": "XXX", "region": "us"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, resp.ID)
+}
+
+func TestDeleteIntegration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/instances/1234/integrations/metrics/3", r.URL.Path)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	err := client.DeleteIntegration("1234", 3)
+	assert.NoError(t, err)
+}
+
+func TestLogIntegrations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			assert.Equal(t, "/instances/1234/integrations/logs", r.URL.Path)
+			w.Write([]byte(`[{"id":5,"type":"papertrail","url":"logs.papertrailapp.com:12345"}]`))
+		case "POST":
+			assert.Equal(t, "/instances/1234/integrations/logs/papertrail", r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 5}`))
+		case "DELETE":
+			assert.Equal(t, "/instances/1234/integrations/logs/5", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	integrations, err := client.ListLogIntegrations("1234")
+	assert.NoError(t, err)
+	assert.Len(t, integrations, 1)
+	assert.Equal(t, "papertrail", integrations[0].Type)
+
+	resp, err := client.CreateLogIntegration("1234", "papertrail", map[string]string{"url": "logs.papertrailapp.com:12345"})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, resp.ID)
+
+	assert.NoError(t, client.DeleteLogIntegration("1234", 5))
+}
@@ -2,12 +2,73 @@ package client
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
 )
 
+// ReleasesURL is the GitHub API endpoint describing the latest CLI release.
+var ReleasesURL = "https://api.github.com/repos/cloudamqp/cli/releases/latest"
+
 type APIKeyRotateResponse struct {
 	APIKey string `json:"apikey"`
 }
 
+// Account describes the account the API key belongs to.
+type Account struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Team  string `json:"team"`
+}
+
+func (c *Client) GetAccount() (*Account, error) {
+	respBody, err := c.makeRequest("GET", "/account", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var account Account
+	if err := json.Unmarshal(respBody, &account); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// ServerTime returns the time of the API server, from the Date header of an
+// authenticated request for one page of a single instance, so clock skew
+// can be detected.
+func (c *Client) ServerTime() (time.Time, error) {
+	_, header, err := c.doRequest("GET", c.baseURL+"/instances?per_page=1", nil, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	date := header.Get("Date")
+	if date == "" {
+		return time.Time{}, fmt.Errorf("the API response has no Date header")
+	}
+	return http.ParseTime(date)
+}
+
+// LatestRelease returns the tag of the latest CLI release, such as v1.4.0.
+func (c *Client) LatestRelease() (string, error) {
+	respBody, err := c.makeExternalRequest("GET", ReleasesURL)
+	if err != nil {
+		return "", err
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.Unmarshal(respBody, &release); err != nil {
+		return "", err
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("the latest release has no tag")
+	}
+	return release.TagName, nil
+}
+
 func (c *Client) GetAuditLogCSV(timestamp string) (string, error) {
 	endpoint := "/auditlog/csv"
 	if timestamp != "" {
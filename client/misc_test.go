@@ -0,0 +1,108 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/account", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"name":"Acme","email":"ops@acme.example","team":"Platform"}`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	account, err := client.GetAccount()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Acme", account.Name)
+	assert.Equal(t, "ops@acme.example", account.Email)
+	assert.Equal(t, "Platform", account.Team)
+}
+
+func TestGetAccount_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("bad-key", server.URL, "test")
+
+	_, err := client.GetAccount()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "API error (401)")
+}
+
+func TestGetAvailableVersions_Backend(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		backend string
+	}{
+		{"rabbitmq", `{"rabbitmq_versions":["4.0.5"],"erlang_versions":["27.2"],"lavinmq_versions":[]}`, BackendRabbitMQ},
+		{"lavinmq", `{"rabbitmq_versions":[],"erlang_versions":[],"lavinmq_versions":["2.1.0"]}`, BackendLavinMQ},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/instances/1234/nodes/available-versions", r.URL.Path)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+			versions, err := client.GetAvailableVersions("1234")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.backend, versions.Backend)
+		})
+	}
+}
+
+func TestServerTime(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/instances", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("per_page"))
+		w.Header().Set("Date", "Fri, 16 Oct 2026 10:00:00 GMT")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	serverTime, err := client.ServerTime()
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC), serverTime)
+}
+
+func TestLatestRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"v1.4.0","name":"v1.4.0"}`))
+	}))
+	defer server.Close()
+
+	origURL := ReleasesURL
+	ReleasesURL = server.URL
+	defer func() { ReleasesURL = origURL }()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	tag, err := client.LatestRelease()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "v1.4.0", tag)
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListOptions controls how list endpoints are paged.
+type ListOptions struct {
+	// Limit caps the number of items returned. Zero means no limit.
+	Limit int
+	// PageSize is sent as the per_page query parameter. Zero leaves the
+	// page size to the API.
+	PageSize int
+}
+
+// listAll fetches endpoint and every following page linked through the Link
+// header with rel="next", until all items or opts.Limit items are collected.
+func listAll[T any](c *Client, endpoint string, opts ListOptions) ([]T, error) {
+	requestURL := c.baseURL + endpoint
+	if opts.PageSize > 0 {
+		requestURL += "?per_page=" + strconv.Itoa(opts.PageSize)
+	}
+
+	items := []T{}
+	seen := map[string]bool{}
+	for requestURL != "" {
+		if seen[requestURL] {
+			return nil, fmt.Errorf("pagination loop detected at %s", requestURL)
+		}
+		seen[requestURL] = true
+
+		respBody, header, err := c.doRequest("GET", requestURL, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []T
+		if err := json.Unmarshal(respBody, &page); err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		if opts.Limit > 0 && len(items) >= opts.Limit {
+			return items[:opts.Limit], nil
+		}
+
+		requestURL, err = nextPageURL(requestURL, header)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// nextPageURL returns the rel="next" target of the Link header, resolved
+// against the URL of the current page, or "" on the last page. A target on
+// another scheme or host is an error, so the API key is never sent to a
+// server other than the API's.
+func nextPageURL(current string, header http.Header) (string, error) {
+	for _, link := range header.Values("Link") {
+		for _, part := range strings.Split(link, ",") {
+			target, params, found := strings.Cut(strings.TrimSpace(part), ";")
+			if !found || !isNextRel(params) {
+				continue
+			}
+			target = strings.Trim(strings.TrimSpace(target), "<>")
+
+			base, err := url.Parse(current)
+			if err != nil {
+				return "", err
+			}
+			next, err := base.Parse(target)
+			if err != nil {
+				return "", fmt.Errorf("invalid next page link %q: %w", target, err)
+			}
+			if next.Scheme != base.Scheme || next.Host != base.Host {
+				return "", fmt.Errorf("next page link %q is not on %s://%s", target, base.Scheme, base.Host)
+			}
+			return next.String(), nil
+		}
+	}
+	return "", nil
+}
+
+func isNextRel(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if key == "rel" {
+			for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+				if rel == "next" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
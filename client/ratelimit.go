@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// maxRetries is how many times a request that failed in a way RetryOn
+// covers is retried before the error is returned.
+const maxRetries = 3
+
+// maxRetryAfter caps how long a Retry-After header can make us wait.
+const maxRetryAfter = 30 * time.Second
+
+var (
+	limiterMu sync.Mutex
+	limiter   *rate.Limiter
+)
+
+// sleep waits d between retries, or returns ctx's error once ctx is done.
+// Tests replace it.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("retry not sent: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// SetRateLimit limits outgoing requests from all clients to perSecond
+// requests per second, so concurrent commands stay below the API rate
+// limit. Zero or less removes the limit. Requests still waiting for their
+// turn give up once RequestContext is done, such as on Ctrl-C.
+func SetRateLimit(perSecond float64) {
+	limiterMu.Lock()
+	defer limiterMu.Unlock()
+	if perSecond <= 0 {
+		limiter = nil
+		return
+	}
+	limiter = rate.NewLimiter(rate.Limit(perSecond), 1)
+}
+
+// waitForRateLimit blocks until the rate limiter allows another request,
+// or returns an error when ctx is done first.
+func waitForRateLimit(ctx context.Context) error {
+	limiterMu.Lock()
+	l := limiter
+	limiterMu.Unlock()
+	if l == nil {
+		return nil
+	}
+	if err := l.Wait(ctx); err != nil {
+		return fmt.Errorf("request not sent: %w", err)
+	}
+	return nil
+}
+
+// retryAfter returns how long to wait before retrying a request, from the
+// Retry-After header in seconds or as an HTTP date. It defaults to a second
+// per attempt when the header is missing, as it is for network errors.
+func retryAfter(header http.Header, attempt int) time.Duration {
+	wait := time.Duration(attempt+1) * time.Second
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			wait = time.Duration(seconds) * time.Second
+		} else if t, err := http.ParseTime(value); err == nil {
+			wait = time.Until(t)
+		}
+	}
+	return max(0, min(wait, maxRetryAfter))
+}
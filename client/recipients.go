@@ -0,0 +1,57 @@
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+type Recipient struct {
+	ID      int               `json:"id"`
+	Type    string            `json:"type"`
+	Value   string            `json:"value"`
+	Name    string            `json:"name"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+type RecipientCreateRequest struct {
+	Type    string            `json:"type"`
+	Value   string            `json:"value"`
+	Name    string            `json:"name,omitempty"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+func (c *Client) ListRecipients(instanceID string) ([]Recipient, error) {
+	endpoint := "/instances/" + instanceID + "/alarms/recipients"
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var recipients []Recipient
+	if err := json.Unmarshal(respBody, &recipients); err != nil {
+		return nil, err
+	}
+
+	return recipients, nil
+}
+
+func (c *Client) CreateRecipient(instanceID string, req *RecipientCreateRequest) (*Recipient, error) {
+	endpoint := "/instances/" + instanceID + "/alarms/recipients"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var recipient Recipient
+	if err := json.Unmarshal(respBody, &recipient); err != nil {
+		return nil, err
+	}
+
+	return &recipient, nil
+}
+
+func (c *Client) DeleteRecipient(instanceID string, recipientID int) error {
+	endpoint := "/instances/" + instanceID + "/alarms/recipients/" + strconv.Itoa(recipientID)
+	_, err := c.makeRequest("DELETE", endpoint, nil)
+	return err
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListRecipients(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/instances/1234/alarms/recipients", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id":7,"type":"email","value":"ops@example.com","name":"Ops team"}]`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	recipients, err := client.ListRecipients("1234")
+
+	assert.NoError(t, err)
+	assert.Len(t, recipients, 1)
+	assert.Equal(t, 7, recipients[0].ID)
+	assert.Equal(t, "email", recipients[0].Type)
+	assert.Equal(t, "ops@example.com", recipients[0].Value)
+	assert.Equal(t, "Ops team", recipients[0].Name)
+}
+
+func TestCreateRecipient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/instances/1234/alarms/recipients", r.URL.Path)
+
+		body, _ := io.ReadAll(r.Body)
+		var req RecipientCreateRequest
+		assert.NoError(t, json.Unmarshal(body, &req))
+		assert.Equal(t, "webhook", req.Type)
+		assert.Equal(t, "https://example.com/hook", req.Value)
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":8,"type":"webhook","value":"https://example.com/hook","name":"Hook"}`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	recipient, err := client.CreateRecipient("1234", &RecipientCreateRequest{
+		Type:  "webhook",
+		Value: "https://example.com/hook",
+		Name:  "Hook",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8, recipient.ID)
+}
+
+func TestDeleteRecipient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/instances/1234/alarms/recipients/8", r.URL.Path)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	err := client.DeleteRecipient("1234", 8)
+	assert.NoError(t, err)
+}
@@ -2,6 +2,8 @@ package client
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/http"
 )
 
 type Region struct {
@@ -16,6 +18,11 @@ type Plan struct {
 	Price   float64 `json:"price"`
 	Backend string  `json:"backend"`
 	Shared  bool    `json:"shared"`
+	// The plan specs are zero when the API doesn't return them.
+	Nodes          int `json:"nodes,omitempty"`
+	MemoryGB       int `json:"memory_gb,omitempty"`
+	DiskGB         int `json:"disk_gb,omitempty"`
+	MaxConnections int `json:"max_connections,omitempty"`
 }
 
 func (c *Client) ListRegions(provider string) ([]Region, error) {
@@ -69,3 +76,19 @@ func (c *Client) ListPlans(backend string) ([]Plan, error) {
 
 	return plans, nil
 }
+
+// GetPlan returns the plan with the given name. There is no endpoint for a
+// single plan, so it is looked up in the plan list; an unknown name is
+// reported as a 404 APIError.
+func (c *Client) GetPlan(name string) (*Plan, error) {
+	plans, err := c.ListPlans("")
+	if err != nil {
+		return nil, err
+	}
+	for _, plan := range plans {
+		if plan.Name == name {
+			return &plan, nil
+		}
+	}
+	return nil, &APIError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf("plan %s not found", name)}
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestListRegions(t *testing.T) {
@@ -158,3 +159,24 @@ func TestListVersions(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedVersions, versions)
 }
+
+func TestGetPlan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/plans", r.URL.Path)
+		w.Write([]byte(`[{"name":"bunny-1","price":99,"backend":"rabbitmq"},{"name":"rabbit-3","price":897,"backend":"rabbitmq","nodes":3,"memory_gb":8,"disk_gb":100,"max_connections":10000}]`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	plan, err := client.GetPlan("rabbit-3")
+	require.NoError(t, err)
+	assert.Equal(t, Plan{Name: "rabbit-3", Price: 897, Backend: "rabbitmq", Nodes: 3, MemoryGB: 8, DiskGB: 100, MaxConnections: 10000}, *plan)
+
+	plan, err = client.GetPlan("bunny-1")
+	require.NoError(t, err)
+	assert.Zero(t, plan.MemoryGB, "specs the API leaves out stay zero")
+
+	_, err = client.GetPlan("hippo-9")
+	assert.True(t, IsNotFound(err))
+}
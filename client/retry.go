@@ -0,0 +1,118 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+)
+
+// Retry conditions accepted by ParseRetryOn.
+const (
+	RetryTooManyRequests = "429"
+	RetryServerError     = "5xx"
+	// RetryIdempotentServerError retries 5xx responses only for requests
+	// that are safe to send twice: GET, HEAD, PUT, DELETE and OPTIONS. POST
+	// requests are not retried, even with an Idempotency-Key header, as the
+	// API is not known to deduplicate on it.
+	RetryIdempotentServerError = "5xx-on-idempotent"
+	// RetryTimeout and RetryConnReset apply to idempotent requests only, as
+	// the server may have acted on a request it didn't finish answering.
+	RetryTimeout   = "timeout"
+	RetryConnReset = "connreset"
+	// RetryNone disables retries.
+	RetryNone = "none"
+)
+
+// DefaultRetryOn is the retry policy used unless RetryOn is changed.
+const DefaultRetryOn = RetryTooManyRequests + "," + RetryIdempotentServerError + "," + RetryConnReset
+
+// RetryPolicy is the set of failures a request is retried on, up to
+// maxRetries times.
+type RetryPolicy struct {
+	TooManyRequests       bool
+	ServerErrors          bool
+	IdempotentServerError bool
+	Timeouts              bool
+	ConnResets            bool
+}
+
+// RetryOn is the retry policy of every client.
+var RetryOn = mustParseRetryOn(DefaultRetryOn)
+
+// ParseRetryOn parses a comma-separated list of retry conditions, such as
+// "429,5xx,timeout". "none" disables retries.
+func ParseRetryOn(conditions string) (RetryPolicy, error) {
+	var policy RetryPolicy
+	for _, condition := range strings.Split(conditions, ",") {
+		switch strings.ToLower(strings.TrimSpace(condition)) {
+		case RetryTooManyRequests:
+			policy.TooManyRequests = true
+		case RetryServerError:
+			policy.ServerErrors = true
+		case RetryIdempotentServerError:
+			policy.IdempotentServerError = true
+		case RetryTimeout:
+			policy.Timeouts = true
+		case RetryConnReset:
+			policy.ConnResets = true
+		case RetryNone, "":
+		default:
+			return RetryPolicy{}, fmt.Errorf("unknown retry condition %q: must be %s, %s, %s, %s, %s or %s",
+				strings.TrimSpace(condition), RetryTooManyRequests, RetryServerError, RetryIdempotentServerError, RetryTimeout, RetryConnReset, RetryNone)
+		}
+	}
+	return policy, nil
+}
+
+func mustParseRetryOn(conditions string) RetryPolicy {
+	policy, err := ParseRetryOn(conditions)
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}
+
+// retryResponse reports whether a request should be sent again after
+// getting resp.
+func (p RetryPolicy) retryResponse(req *http.Request, resp *http.Response) bool {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return p.TooManyRequests
+	case resp.StatusCode >= 500:
+		return p.ServerErrors || (p.IdempotentServerError && idempotent(req))
+	}
+	return false
+}
+
+// retryError reports whether req should be sent again after failing with
+// err, before or while reading the response. Either way the server may
+// have acted on it, so only idempotent requests are retried.
+func (p RetryPolicy) retryError(req *http.Request, err error) bool {
+	if !idempotent(req) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return p.Timeouts
+	}
+	// A connection closed before the response is complete shows up as EOF
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return p.ConnResets
+	}
+	return false
+}
+
+// idempotent reports whether req is safe to send twice. An Idempotency-Key
+// header doesn't count: the API is not known to honour it, and a resent
+// POST /instances could create a second, billed instance.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetryOn(t *testing.T) {
+	policy, err := ParseRetryOn(DefaultRetryOn)
+	require.NoError(t, err)
+	assert.Equal(t, RetryPolicy{TooManyRequests: true, IdempotentServerError: true, ConnResets: true}, policy)
+
+	policy, err = ParseRetryOn("5xx, TIMEOUT")
+	require.NoError(t, err)
+	assert.Equal(t, RetryPolicy{ServerErrors: true, Timeouts: true}, policy)
+
+	policy, err = ParseRetryOn("none")
+	require.NoError(t, err)
+	assert.Equal(t, RetryPolicy{}, policy)
+
+	_, err = ParseRetryOn("429,4xx")
+	assert.ErrorContains(t, err, `unknown retry condition "4xx"`)
+}
+
+// failFirst returns a handler that fails the first request with fail and
+// answers the rest with an empty list, counting the attempts.
+func failFirst(attempts *int, fail func(w http.ResponseWriter)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*attempts++
+		if *attempts == 1 {
+			fail(w)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}
+}
+
+func TestDoRequest_RetryOn(t *testing.T) {
+	origSleep := sleep
+	sleep = func(context.Context, time.Duration) error { return nil }
+	defer func() { sleep = origSleep }()
+	defer func() {
+		RetryOn = mustParseRetryOn(DefaultRetryOn)
+		RequestTimeout = 0
+	}()
+
+	status := func(code int) func(w http.ResponseWriter) {
+		return func(w http.ResponseWriter) { w.WriteHeader(code) }
+	}
+	connReset := func(w http.ResponseWriter) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}
+	// truncated starts the response and closes the connection mid-body
+	truncated := func(w http.ResponseWriter) {
+		w.Header().Set("Content-Length", "100")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("["))
+		w.(http.Flusher).Flush()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}
+	slow := func(w http.ResponseWriter) { time.Sleep(200 * time.Millisecond) }
+
+	tests := []struct {
+		name    string
+		retryOn string
+		method  string
+		header  http.Header
+		timeout time.Duration
+		fail    func(w http.ResponseWriter)
+		retried bool
+	}{
+		{"429", DefaultRetryOn, "POST", nil, 0, status(http.StatusTooManyRequests), true},
+		{"429 not listed", "5xx", "GET", nil, 0, status(http.StatusTooManyRequests), false},
+		{"5xx on GET", DefaultRetryOn, "GET", nil, 0, status(http.StatusBadGateway), true},
+		{"5xx on POST", DefaultRetryOn, "POST", nil, 0, status(http.StatusBadGateway), false},
+		{"5xx on POST with idempotency key", DefaultRetryOn, "POST", http.Header{"Idempotency-Key": {"key"}}, 0, status(http.StatusBadGateway), false},
+		{"5xx on any method", "5xx", "POST", nil, 0, status(http.StatusServiceUnavailable), true},
+		{"4xx", "429,5xx,timeout,connreset", "GET", nil, 0, status(http.StatusBadRequest), false},
+		{"timeout", "timeout", "GET", nil, 50 * time.Millisecond, slow, true},
+		{"timeout not listed", DefaultRetryOn, "GET", nil, 50 * time.Millisecond, slow, false},
+		{"timeout on POST", "timeout", "POST", nil, 50 * time.Millisecond, slow, false},
+		{"connreset", DefaultRetryOn, "GET", nil, 0, connReset, true},
+		{"connreset on POST", DefaultRetryOn, "POST", nil, 0, connReset, false},
+		{"connreset on POST with idempotency key", DefaultRetryOn, "POST", http.Header{"Idempotency-Key": {"key"}}, 0, connReset, false},
+		{"truncated response", DefaultRetryOn, "GET", nil, 0, truncated, true},
+		{"truncated response on POST", DefaultRetryOn, "POST", nil, 0, truncated, false},
+		{"connreset not listed", "429", "GET", nil, 0, connReset, false},
+		{"none", "none", "GET", nil, 0, status(http.StatusTooManyRequests), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(failFirst(&attempts, tt.fail))
+			defer server.Close()
+
+			RetryOn = mustParseRetryOn(tt.retryOn)
+			RequestTimeout = tt.timeout
+			client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+			_, _, err := client.doRequest(tt.method, server.URL+"/instances", nil, tt.header)
+			if tt.retried {
+				assert.NoError(t, err)
+				assert.Equal(t, 2, attempts)
+			} else {
+				assert.Error(t, err)
+				assert.Equal(t, 1, attempts)
+			}
+		})
+	}
+}
@@ -3,6 +3,14 @@ package client
 import (
 	"encoding/json"
 	"net/url"
+	"strings"
+)
+
+// Statuses of a team member. Pending invitations are listed with the
+// members as invited.
+const (
+	TeamMemberActive  = "active"
+	TeamMemberInvited = "invited"
 )
 
 type TeamMember struct {
@@ -10,6 +18,8 @@ type TeamMember struct {
 	Email          string   `json:"email"`
 	TFAAuthEnabled bool     `json:"tfa_auth_enabled"`
 	Roles          []string `json:"roles"`
+	Role           string   `json:"role"`
+	Status         string   `json:"status"`
 }
 
 type TeamInviteRequest struct {
@@ -42,6 +52,15 @@ func (c *Client) ListTeamMembers() ([]TeamMember, error) {
 		return nil, err
 	}
 
+	for i := range members {
+		if members[i].Role == "" {
+			members[i].Role = strings.Join(members[i].Roles, ", ")
+		}
+		if members[i].Status == "" {
+			members[i].Status = TeamMemberActive
+		}
+	}
+
 	return members, nil
 }
 
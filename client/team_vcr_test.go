@@ -0,0 +1,52 @@
+package client
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/dnaeon/go-vcr.v2/cassette"
+	"gopkg.in/dnaeon/go-vcr.v2/recorder"
+)
+
+// TestListTeamMembersVCR tests listing team members with a pending invitation.
+// The fixture is synthetic: it is not a recording of the live API.
+func TestListTeamMembersVCR(t *testing.T) {
+	r, err := recorder.New("fixtures/list_team_members")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	r.AddFilter(func(i *cassette.Interaction) error {
+		delete(i.Request.Headers, "Authorization")
+		delete(i.Response.Headers, "Set-Cookie")
+		return nil
+	})
+
+	apiKey := os.Getenv("CLOUDAMQP_APIKEY")
+	if apiKey == "" {
+		apiKey = "vcr-replay-mode"
+	}
+
+	httpClient := &http.Client{Transport: r}
+	client := NewWithHTTPClient(apiKey, "https://customer.cloudamqp.com/api", "test", httpClient)
+
+	members, err := client.ListTeamMembers()
+	require.NoError(t, err)
+	require.Len(t, members, 3)
+
+	assert.Equal(t, "owner@example.com", members[0].Email)
+	assert.Equal(t, "admin", members[0].Role)
+	assert.Equal(t, TeamMemberActive, members[0].Status)
+
+	// Older responses without role and status fall back to roles and active
+	assert.Equal(t, "devops", members[1].Role)
+	assert.Equal(t, TeamMemberActive, members[1].Status)
+
+	assert.Equal(t, "new-hire@example.com", members[2].Email)
+	assert.Equal(t, "member", members[2].Role)
+	assert.Equal(t, TeamMemberInvited, members[2].Status)
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceWriter, when set, receives a full transcript of every request and
+// response made by clients created with New and NewWithBaseURL. Credentials
+// are redacted as in the recorded test fixtures.
+var TraceWriter io.Writer
+
+// redactedHeaders are written to the trace as REDACTED.
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// traceTransport writes each request and response passing through base to
+// w. Requests may run concurrently, so entries are written whole under mu.
+type traceTransport struct {
+	base http.RoundTripper
+	w    io.Writer
+	mu   sync.Mutex
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	var entry strings.Builder
+	start := time.Now()
+	fmt.Fprintf(&entry, "--- %s\n%s %s %s\n", start.Format(time.RFC3339), req.Method, req.URL, req.Proto)
+	writeTraceHeaders(&entry, req.Header)
+	writeTraceBody(&entry, req.Header, reqBody)
+
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		fmt.Fprintf(&entry, "\nerror after %s: %v\n\n", elapsed, err)
+		t.write(entry.String())
+		return nil, err
+	}
+
+	fmt.Fprintf(&entry, "\n%s %s (%s)\n", resp.Proto, resp.Status, elapsed)
+	writeTraceHeaders(&entry, resp.Header)
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		fmt.Fprintf(&entry, "\nerror reading body: %v\n\n", err)
+		t.write(entry.String())
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	writeTraceBody(&entry, resp.Header, respBody)
+	entry.WriteString("\n")
+	t.write(entry.String())
+	return resp, nil
+}
+
+func (t *traceTransport) write(entry string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	io.WriteString(t.w, entry)
+}
+
+func writeTraceHeaders(w io.Writer, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			if redactedHeaders[http.CanonicalHeaderKey(name)] {
+				value = "REDACTED"
+			}
+			fmt.Fprintf(w, "%s: %s\n", name, value)
+		}
+	}
+}
+
+// writeTraceBody writes body with credentials redacted, as a form when the
+// Content-Type in header says so.
+func writeTraceBody(w io.Writer, header http.Header, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	if mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type")); mediaType == "application/x-www-form-urlencoded" {
+		fmt.Fprintf(w, "\n%s\n", SanitizeForm(body))
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n", SanitizeBody(body))
+}
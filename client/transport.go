@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// ProxyURL, when set, is used for every request instead of the proxy
+// configured through HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+var ProxyURL *url.URL
+
+// RootCAs, when set, replaces the system certificate pool used to verify
+// the API server. See LoadCACert.
+var RootCAs *x509.CertPool
+
+// InsecureSkipVerify disables TLS certificate verification. It is only meant
+// for test and staging endpoints with self-signed certificates.
+var InsecureSkipVerify bool
+
+// ConnectTimeout, when set, bounds establishing a connection to the API:
+// the TCP connect and the TLS handshake each. It doesn't limit waiting for
+// a response on an established connection.
+var ConnectTimeout time.Duration
+
+// RequestTimeout, when set, bounds each request as a whole, from connecting
+// to reading the end of the response body. Retries of throttled requests
+// get a new RequestTimeout each.
+var RequestTimeout time.Duration
+
+// RequestContext is the context every request is sent with. Once it is
+// done, requests in flight are cancelled, and requests waiting for the rate
+// limiter or for a retry give up, with its error.
+var RequestContext = context.Background()
+
+// newHTTPClient returns the HTTP client used by clients created with New
+// and NewWithBaseURL.
+func newHTTPClient() *http.Client {
+	return &http.Client{Transport: newTransport(), Timeout: RequestTimeout}
+}
+
+// newTransport returns the transport used by clients created with New and
+// NewWithBaseURL, writing a trace to TraceWriter when it is set.
+func newTransport() http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if ConnectTimeout > 0 {
+		dialer := &net.Dialer{Timeout: ConnectTimeout, KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+		transport.TLSHandshakeTimeout = ConnectTimeout
+	}
+	if ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(ProxyURL)
+	}
+	if RootCAs != nil || InsecureSkipVerify {
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:            RootCAs,
+			InsecureSkipVerify: InsecureSkipVerify,
+		}
+	}
+	if TraceWriter != nil {
+		return &traceTransport{base: transport, w: TraceWriter}
+	}
+	return transport
+}
+
+// LoadCACert returns the system certificate pool with the PEM encoded
+// certificates in path appended.
+func LoadCACert(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
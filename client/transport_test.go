@@ -0,0 +1,66 @@
+package client
+
+import (
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectTimeout_StalledHandshake(t *testing.T) {
+	// Accept connections but never answer the TLS handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ConnectTimeout = 50 * time.Millisecond
+	defer func() { ConnectTimeout = 0 }()
+
+	client := NewWithBaseURL("test-api-key", "https://"+listener.Addr().String(), "test")
+	start := time.Now()
+	_, err = client.makeRequest("GET", "/instances", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TLS handshake timeout")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestConnectTimeout_SlowResponse(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	RootCAs = pool
+	defer func() { RootCAs = nil }()
+
+	// A slow response is not a slow connection
+	ConnectTimeout = 50 * time.Millisecond
+	defer func() { ConnectTimeout = 0 }()
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+	_, err := client.makeRequest("GET", "/instances", nil)
+	require.NoError(t, err)
+
+	RequestTimeout = 50 * time.Millisecond
+	defer func() { RequestTimeout = 0 }()
+	client = NewWithBaseURL("test-api-key", server.URL, "test")
+	_, err = client.makeRequest("GET", "/instances", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Client.Timeout exceeded")
+}
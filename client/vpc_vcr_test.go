@@ -21,7 +21,7 @@ func TestListVPCsVCR(t *testing.T) {
 
 	r.AddFilter(func(i *cassette.Interaction) error {
 		delete(i.Request.Headers, "Authorization")
-		i.Response.Body = sanitizeResponseBody(i.Response.Body)
+		i.Response.Body = string(SanitizeBody([]byte(i.Response.Body)))
 		delete(i.Response.Headers, "Set-Cookie")
 		return nil
 	})
@@ -51,7 +51,7 @@ func TestVPCLifecycleVCR(t *testing.T) {
 
 	r.AddFilter(func(i *cassette.Interaction) error {
 		delete(i.Request.Headers, "Authorization")
-		i.Response.Body = sanitizeResponseBody(i.Response.Body)
+		i.Response.Body = string(SanitizeBody([]byte(i.Response.Body)))
 		delete(i.Response.Headers, "Set-Cookie")
 		return nil
 	})
@@ -35,17 +35,13 @@ type VPCUpdateRequest struct {
 }
 
 func (c *Client) ListVPCs() ([]VPC, error) {
-	respBody, err := c.makeRequest("GET", "/vpcs", nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var vpcs []VPC
-	if err := json.Unmarshal(respBody, &vpcs); err != nil {
-		return nil, err
-	}
+	return c.ListVPCsWithOptions(ListOptions{})
+}
 
-	return vpcs, nil
+// ListVPCsWithOptions lists VPCs, following every page unless opts.Limit is
+// reached first.
+func (c *Client) ListVPCsWithOptions(opts ListOptions) ([]VPC, error) {
+	return listAll[VPC](c, "/vpcs", opts)
 }
 
 func (c *Client) GetVPC(id int) (*VPC, error) {
@@ -110,3 +106,73 @@ func (c *Client) DeleteVPC(id int) error {
 	_, err := c.makeRequest("DELETE", endpoint, nil)
 	return err
 }
+
+// VPCInfo describes the cloud provider VPC an instance runs in.
+type VPCInfo struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Subnet          string `json:"subnet"`
+	OwnerID         string `json:"owner_id"`
+	SecurityGroupID string `json:"security_group_id"`
+}
+
+type VPCPeering struct {
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	PeerVPCID     string `json:"peer_vpc_id"`
+	PeerAccountID string `json:"peer_account_id"`
+	PeerRegion    string `json:"peer_region"`
+	PeerSubnet    string `json:"peer_subnet"`
+}
+
+type VPCPeeringRequest struct {
+	PeerVPCID     string `json:"peer_vpc_id"`
+	PeerAccountID string `json:"peer_account_id"`
+	PeerRegion    string `json:"peer_region,omitempty"`
+	PeerSubnet    string `json:"peer_subnet,omitempty"`
+}
+
+func (c *Client) GetInstanceVPCInfo(instanceID string) (*VPCInfo, error) {
+	endpoint := "/instances/" + instanceID + "/vpc"
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var info VPCInfo
+	if err := json.Unmarshal(respBody, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+func (c *Client) ListVPCPeerings(vpcID int) ([]VPCPeering, error) {
+	endpoint := "/vpcs/" + strconv.Itoa(vpcID) + "/vpc-peering"
+	respBody, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var peerings []VPCPeering
+	if err := json.Unmarshal(respBody, &peerings); err != nil {
+		return nil, err
+	}
+
+	return peerings, nil
+}
+
+func (c *Client) RequestVPCPeering(vpcID int, req *VPCPeeringRequest) (*VPCPeering, error) {
+	endpoint := "/vpcs/" + strconv.Itoa(vpcID) + "/vpc-peering/request"
+	respBody, err := c.makeRequest("POST", endpoint, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var peering VPCPeering
+	if err := json.Unmarshal(respBody, &peering); err != nil {
+		return nil, err
+	}
+
+	return &peering, nil
+}
@@ -202,3 +202,69 @@ func TestVPCError_NotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "API error (404): VPC not found")
 }
+
+func TestGetInstanceVPCInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/instances/1234/vpc", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"vpc-0abc","name":"my-vpc","subnet":"10.56.72.0/24","owner_id":"123456789012","security_group_id":"sg-0def"}`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	info, err := client.GetInstanceVPCInfo("1234")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "vpc-0abc", info.ID)
+	assert.Equal(t, "10.56.72.0/24", info.Subnet)
+	assert.Equal(t, "sg-0def", info.SecurityGroupID)
+}
+
+func TestListVPCPeerings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/vpcs/5678/vpc-peering", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[{"id":"pcx-1","status":"active","peer_vpc_id":"vpc-9","peer_account_id":"210987654321","peer_region":"us-east-1","peer_subnet":"10.0.0.0/16"}]`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	peerings, err := client.ListVPCPeerings(5678)
+
+	assert.NoError(t, err)
+	assert.Len(t, peerings, 1)
+	assert.Equal(t, "pcx-1", peerings[0].ID)
+	assert.Equal(t, "active", peerings[0].Status)
+}
+
+func TestRequestVPCPeering(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/vpcs/5678/vpc-peering/request", r.URL.Path)
+
+		var req VPCPeeringRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "vpc-9", req.PeerVPCID)
+		assert.Equal(t, "210987654321", req.PeerAccountID)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"pcx-1","status":"pending-acceptance","peer_vpc_id":"vpc-9"}`))
+	}))
+	defer server.Close()
+
+	client := NewWithBaseURL("test-api-key", server.URL, "test")
+
+	peering, err := client.RequestVPCPeering(5678, &VPCPeeringRequest{
+		PeerVPCID:     "vpc-9",
+		PeerAccountID: "210987654321",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pending-acceptance", peering.Status)
+}
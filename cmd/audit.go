@@ -26,11 +26,10 @@ var auditCmd = &cobra.Command{
 
 		csv, err := c.GetAuditLogCSV(auditTimestamp)
 		if err != nil {
-			fmt.Printf("Error getting audit log: %v\n", err)
-			return err
+			return fmt.Errorf("failed to get audit log: %w", err)
 		}
 
-		fmt.Print(csv)
+		fmt.Fprint(cmd.OutOrStdout(), csv)
 		return nil
 	},
 }
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// bulkResult is the outcome of a bulk operation on one instance. Skipped
+// is set for instances that were not attempted because of --fail-fast or
+// an interrupt.
+type bulkResult struct {
+	Instance client.Instance
+	Err      error
+	Skipped  bool
+}
+
+// addFailFastFlag registers --fail-fast on a bulk command.
+func addFailFastFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("fail-fast", false, "Stop at the first failed instance instead of continuing with the rest")
+}
+
+// isFailFast reports whether --fail-fast was passed to the command.
+func isFailFast(cmd *cobra.Command) bool {
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	return failFast
+}
+
+// runBulk calls apply for every instance, at most concurrencyLimit at a time,
+// and returns the results in the order of instances. By default every
+// instance is attempted; with failFast, instances not yet started when one
+// fails are skipped. Once ctx is cancelled by Ctrl-C, instances not yet
+// started are skipped too. Operations already running are allowed to finish.
+func runBulk(ctx context.Context, instances []client.Instance, apply func(instance client.Instance) error, failFast bool) []bulkResult {
+	results := make([]bulkResult, len(instances))
+	sem := make(chan struct{}, concurrencyLimit())
+	var failed atomic.Bool
+
+	var wg sync.WaitGroup
+	for i, instance := range instances {
+		wg.Add(1)
+		go func(i int, instance client.Instance) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil || failFast && failed.Load() {
+				results[i] = bulkResult{Instance: instance, Skipped: true}
+				return
+			}
+			err := apply(instance)
+			if err != nil {
+				failed.Store(true)
+			}
+			results[i] = bulkResult{Instance: instance, Err: err}
+		}(i, instance)
+	}
+	wg.Wait()
+	return results
+}
+
+// printBulkResults prints one row per instance and returns the failures,
+// joined with errors.Join, if any of them failed.
+func printBulkResults(cmd *cobra.Command, results []bulkResult) error {
+	p, err := getListPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	skipped := 0
+	rows := make([][]string, len(results))
+	for i, r := range results {
+		result := "ok"
+		switch {
+		case r.Skipped:
+			skipped++
+			result = "skipped"
+		case r.Err != nil:
+			errs = append(errs, fmt.Errorf("instance %d (%s): %w", r.Instance.ID, r.Instance.Name, r.Err))
+			result = "error: " + r.Err.Error()
+		}
+		rows[i] = []string{strconv.Itoa(r.Instance.ID), r.Instance.Name, result}
+	}
+	p.PrintRecords([]string{"ID", "NAME", "RESULT"}, rows)
+
+	interrupted := commandContext(cmd).Err() != nil && skipped > 0
+	if len(errs) == 0 && !interrupted {
+		return nil
+	}
+	cmd.SilenceUsage = true
+	summary := fmt.Sprintf("%d of %d instances failed", len(errs), len(results))
+	if skipped > 0 {
+		summary += fmt.Sprintf(", %d skipped", skipped)
+	}
+	if interrupted {
+		if len(errs) == 0 {
+			return fmt.Errorf("%w: %d of %d instances skipped", errInterrupted, skipped, len(results))
+		}
+		return fmt.Errorf("%w: %s:\n%w", errInterrupted, summary, errors.Join(errs...))
+	}
+	return fmt.Errorf("%s:\n%w", summary, errors.Join(errs...))
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bulkInstances(n int) []client.Instance {
+	instances := make([]client.Instance, n)
+	for i := range instances {
+		instances[i] = client.Instance{ID: i + 1, Name: "instance"}
+	}
+	return instances
+}
+
+func TestRunBulk_ContinuesOnError(t *testing.T) {
+	errBoom := errors.New("boom")
+	results := runBulk(context.Background(), bulkInstances(6), func(instance client.Instance) error {
+		if instance.ID%2 == 0 {
+			return errBoom
+		}
+		return nil
+	}, false)
+
+	require.Len(t, results, 6)
+	for i, r := range results {
+		assert.Equal(t, i+1, r.Instance.ID)
+		assert.False(t, r.Skipped)
+		if r.Instance.ID%2 == 0 {
+			assert.ErrorIs(t, r.Err, errBoom)
+		} else {
+			assert.NoError(t, r.Err)
+		}
+	}
+}
+
+func TestRunBulk_Concurrency(t *testing.T) {
+	concurrency = 2
+	defer func() { concurrency = 0 }()
+
+	var running, peak atomic.Int32
+	runBulk(context.Background(), bulkInstances(10), func(instance client.Instance) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return nil
+	}, false)
+
+	assert.LessOrEqual(t, int(peak.Load()), 2)
+}
+
+func TestConcurrencyLimit(t *testing.T) {
+	defer func() { concurrency = 0 }()
+
+	t.Setenv("CLOUDAMQP_CONCURRENCY", "")
+	assert.Equal(t, min(runtime.NumCPU(), maxDefaultConcurrency), concurrencyLimit())
+	require.NoError(t, checkConcurrency())
+
+	t.Setenv("CLOUDAMQP_CONCURRENCY", "3")
+	assert.Equal(t, 3, concurrencyLimit())
+
+	concurrency = 5
+	assert.Equal(t, 5, concurrencyLimit())
+
+	concurrency = -1
+	assert.ErrorContains(t, checkConcurrency(), "invalid --concurrency -1")
+
+	concurrency = 0
+	t.Setenv("CLOUDAMQP_CONCURRENCY", "many")
+	assert.ErrorContains(t, checkConcurrency(), `invalid CLOUDAMQP_CONCURRENCY "many"`)
+	assert.Equal(t, min(runtime.NumCPU(), maxDefaultConcurrency), concurrencyLimit())
+}
+
+func TestRunBulk_FailFast(t *testing.T) {
+	var calls atomic.Int32
+	results := runBulk(context.Background(), bulkInstances(20), func(instance client.Instance) error {
+		calls.Add(1)
+		return errors.New("boom")
+	}, true)
+
+	// Only operations already running when the first one failed are attempted
+	assert.LessOrEqual(t, int(calls.Load()), concurrencyLimit())
+	skipped := 0
+	for _, r := range results {
+		if r.Skipped {
+			skipped++
+			assert.NoError(t, r.Err)
+		}
+	}
+	assert.Equal(t, 20-int(calls.Load()), skipped)
+}
+
+func TestPrintBulkResults(t *testing.T) {
+	errBoom := errors.New("boom")
+	cmd := &cobra.Command{}
+	cmd.Flags().String("output", "table", "")
+
+	var err error
+	out := captureStdout(t, func() {
+		err = printBulkResults(cmd, []bulkResult{
+			{Instance: client.Instance{ID: 1, Name: "orders"}},
+			{Instance: client.Instance{ID: 2, Name: "billing"}, Err: errBoom},
+			{Instance: client.Instance{ID: 3, Name: "staging"}, Skipped: true},
+		})
+	})
+
+	assert.Contains(t, out, "ok")
+	assert.Contains(t, out, "error: boom")
+	assert.Contains(t, out, "skipped")
+	assert.ErrorIs(t, err, errBoom)
+	assert.Equal(t, "1 of 3 instances failed, 1 skipped:\ninstance 2 (billing): boom", err.Error())
+	assert.True(t, cmd.SilenceUsage)
+}
+
+func TestRunBulk_Interrupted(t *testing.T) {
+	concurrency = 1
+	defer func() { concurrency = 0 }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+	results := runBulk(ctx, bulkInstances(4), func(instance client.Instance) error {
+		calls.Add(1)
+		cancel() // Ctrl-C while the first instance is in flight
+		return nil
+	}, false)
+
+	done := 0
+	for _, r := range results {
+		if !r.Skipped {
+			done++
+		}
+	}
+	assert.Equal(t, 1, done, "in-flight work finishes, nothing new starts")
+	assert.Equal(t, int32(1), calls.Load())
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("output", "table", "")
+	cmd.SetContext(ctx)
+	var err error
+	out := captureStdout(t, func() {
+		err = printBulkResults(cmd, results)
+	})
+	assert.Contains(t, out, "skipped")
+	assert.ErrorIs(t, err, errInterrupted)
+	assert.Equal(t, "interrupted: 3 of 4 instances skipped", err.Error())
+	assert.Equal(t, exitInterrupted, ExitCode(err))
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"cloudamqp-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// Values of --color.
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// colorEnabled reports whether table output written to w is styled with
+// ANSI colors. --no-color and --color never turn color off, --color always
+// turns it on; with the default --color auto, only a terminal gets color and
+// only when NO_COLOR is unset.
+func colorEnabled(cmd *cobra.Command, w io.Writer) (bool, error) {
+	mode, _ := cmd.Flags().GetString("color")
+	if noColor, _ := cmd.Flags().GetBool("no-color"); noColor {
+		mode = colorNever
+	}
+
+	switch mode {
+	case colorNever:
+		return false, nil
+	case colorAlways:
+		return true, nil
+	case colorAuto, "":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		f, ok := w.(*os.File)
+		return ok && ui.IsTerminal(f), nil
+	}
+	return false, fmt.Errorf("invalid --color %q: must be auto, always or never", mode)
+}
+
+// readyStyle colors a Yes/No ready or enabled value green or yellow.
+func readyStyle(value string) string {
+	if value == "Yes" {
+		return ui.Green(value)
+	}
+	return ui.Yellow(value)
+}
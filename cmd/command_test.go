@@ -2,10 +2,15 @@ package cmd
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"cloudamqp-cli/client"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRootCommand(t *testing.T) {
@@ -17,6 +22,122 @@ func TestRootCommand(t *testing.T) {
 	assert.Contains(t, cmd.Long, "~/.cloudamqprc file")
 }
 
+func TestConfigureTransport_Proxy(t *testing.T) {
+	defer func() {
+		rootCmd.PersistentFlags().Set("proxy", "")
+		client.ProxyURL = nil
+	}()
+	rootCmd.InheritedFlags() // merge persistent flags, as Execute would
+
+	rootCmd.PersistentFlags().Set("proxy", "proxy.example.com")
+	assert.ErrorContains(t, configureTransport(rootCmd, nil), "invalid --proxy")
+
+	rootCmd.PersistentFlags().Set("proxy", "http://proxy.example.com:3128")
+	assert.NoError(t, configureTransport(rootCmd, nil))
+	assert.Equal(t, "proxy.example.com:3128", client.ProxyURL.Host)
+}
+
+func TestConfigureTransport_Trace(t *testing.T) {
+	defer func() {
+		rootCmd.PersistentFlags().Set("trace", "")
+		client.TraceWriter = nil
+	}()
+	rootCmd.InheritedFlags()
+
+	rootCmd.PersistentFlags().Set("trace", filepath.Join(t.TempDir(), "missing", "trace.log"))
+	assert.ErrorContains(t, configureTransport(rootCmd, nil), "failed to open trace file")
+
+	path := filepath.Join(t.TempDir(), "trace.log")
+	rootCmd.PersistentFlags().Set("trace", path)
+	require.NoError(t, configureTransport(rootCmd, nil))
+	require.NotNil(t, client.TraceWriter)
+	client.TraceWriter.(*os.File).Close()
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "traces hold API traffic and must not be world-readable")
+}
+
+func TestConfigureTransport_Timeouts(t *testing.T) {
+	defer func() {
+		rootCmd.PersistentFlags().Set("connect-timeout", "")
+		rootCmd.PersistentFlags().Set("request-timeout", "")
+		client.ConnectTimeout = 0
+		client.RequestTimeout = 0
+	}()
+	rootCmd.InheritedFlags()
+
+	rootCmd.PersistentFlags().Set("connect-timeout", "5")
+	assert.ErrorContains(t, configureTransport(rootCmd, nil), `invalid --connect-timeout "5"`)
+
+	rootCmd.PersistentFlags().Set("connect-timeout", "5s")
+	rootCmd.PersistentFlags().Set("request-timeout", "-1m")
+	assert.ErrorContains(t, configureTransport(rootCmd, nil), `invalid --request-timeout "-1m"`)
+
+	rootCmd.PersistentFlags().Set("request-timeout", "2m")
+	require.NoError(t, configureTransport(rootCmd, nil))
+	assert.Equal(t, 5*time.Second, client.ConnectTimeout)
+	assert.Equal(t, 2*time.Minute, client.RequestTimeout)
+}
+
+func TestConfigureTransport_RetryOn(t *testing.T) {
+	defer func() {
+		rootCmd.PersistentFlags().Set("retry-on", client.DefaultRetryOn)
+		client.RetryOn, _ = client.ParseRetryOn(client.DefaultRetryOn)
+	}()
+	rootCmd.InheritedFlags()
+
+	rootCmd.PersistentFlags().Set("retry-on", "429,5xx-on-get")
+	assert.ErrorContains(t, configureTransport(rootCmd, nil), `invalid --retry-on: unknown retry condition "5xx-on-get"`)
+
+	rootCmd.PersistentFlags().Set("retry-on", "5xx,timeout")
+	require.NoError(t, configureTransport(rootCmd, nil))
+	assert.Equal(t, client.RetryPolicy{ServerErrors: true, Timeouts: true}, client.RetryOn)
+}
+
+func TestCommandAliases(t *testing.T) {
+	tests := map[string]*cobra.Command{
+		"i ls":           instanceListCmd,
+		"inst list":      instanceListCmd,
+		"instance new":   instanceCreateCmd,
+		"i rm":           instanceDeleteCmd,
+		"vpc ls":         vpcListCmd,
+		"i nodes ls":     instanceNodesListCmd,
+		"i alarms new":   instanceAlarmsCreateCmd,
+		"i tags ls":      instanceTagsListCmd,
+		"vpc peering ls": vpcPeeringListCmd,
+		"team ls":        teamListCmd,
+		"i firewall ls":  instanceFirewallListCmd,
+		"i config ls":    instanceConfigListCmd,
+		"vpc rm":         vpcDeleteCmd,
+		"i plugins ls":   instancePluginsListCmd,
+		"i alarms rm":    instanceAlarmsDeleteCmd,
+		"vpc new":        vpcCreateCmd,
+	}
+	for args, want := range tests {
+		found, _, err := rootCmd.Find(strings.Fields(args))
+		require.NoError(t, err, args)
+		assert.Same(t, want, found, args)
+	}
+}
+
+func TestCommandAliasesUnique(t *testing.T) {
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		seen := map[string]string{}
+		for _, sub := range cmd.Commands() {
+			for _, name := range append([]string{sub.Name()}, sub.Aliases...) {
+				if other, ok := seen[name]; ok {
+					t.Errorf("%q under %q is used by both %s and %s", name, cmd.CommandPath(), other, sub.Name())
+				}
+				seen[name] = sub.Name()
+			}
+			walk(sub)
+		}
+	}
+	walk(rootCmd)
+}
+
 func TestInstanceCommand(t *testing.T) {
 	cmd := instanceCmd
 
@@ -34,6 +155,7 @@ func TestInstanceCommand(t *testing.T) {
 	assert.Contains(t, commandNames, "list")
 	assert.Contains(t, commandNames, "get --id <id>")
 	assert.Contains(t, commandNames, "update --id <id>")
+	assert.Contains(t, commandNames, "rename --id <id> --name <new_name>")
 	assert.Contains(t, commandNames, "delete --id <id>")
 	assert.Contains(t, commandNames, "resize-disk --id <id>")
 }
@@ -56,6 +178,7 @@ func TestVPCCommand(t *testing.T) {
 	assert.Contains(t, commandNames, "get --id <id>")
 	assert.Contains(t, commandNames, "update --id <id>")
 	assert.Contains(t, commandNames, "delete --id <id>")
+	assert.Contains(t, commandNames, "peering")
 }
 
 func TestInstanceCreateCommand_Validation(t *testing.T) {
@@ -268,3 +391,174 @@ func TestCommandExamples(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAlarmType(t *testing.T) {
+	for _, alarmType := range []string{"cpu", "memory", "queue", "connection"} {
+		assert.NoError(t, validateAlarmType(alarmType))
+	}
+
+	err := validateAlarmType("temperature")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Valid types are")
+}
+
+func TestValidateRecipient(t *testing.T) {
+	assert.NoError(t, validateRecipient("email", "ops@example.com"))
+	assert.NoError(t, validateRecipient("slack", "https://hooks.slack.com/services/T000/B000/XXX"))
+	assert.NoError(t, validateRecipient("webhook", "http://example.com/hook"))
+	assert.NoError(t, validateRecipient("pagerduty", "abc123"))
+	assert.NoError(t, validateRecipient("opsgenie", "0f1e2d3c"))
+
+	assert.Error(t, validateRecipient("email", "https://example.com"))
+	assert.Error(t, validateRecipient("email", "Ops <ops@example.com>"))
+	assert.Error(t, validateRecipient("webhook", "ops@example.com"))
+	assert.Error(t, validateRecipient("slack", "ftp://example.com"))
+	assert.Error(t, validateRecipient("pagerduty", "https://events.pagerduty.com"))
+	assert.Error(t, validateRecipient("sms", "+4612345678"))
+	assert.Error(t, validateRecipient("email", ""))
+}
+
+func TestInstanceRenameCmd_RejectsEmptyName(t *testing.T) {
+	cmd := instanceRenameCmd
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("name", "   ")
+	defer func() {
+		cmd.Flags().Set("id", "")
+		cmd.Flags().Set("name", "")
+	}()
+
+	err := cmd.RunE(cmd, []string{})
+	assert.EqualError(t, err, "new name must not be empty")
+}
+
+func TestPlanRank(t *testing.T) {
+	assert.Less(t, planRank("lemur"), planRank("tiger"))
+	assert.Less(t, planRank("tiger"), planRank("bunny-1"))
+	assert.Less(t, planRank("bunny-1"), planRank("bunny-3"))
+	assert.Less(t, planRank("bunny-3"), planRank("hare-1"))
+	assert.Less(t, planRank("puffin-5"), planRank("penguin-1"))
+	assert.Equal(t, -1, planRank("unknown-1"))
+	assert.Equal(t, -1, planRank("bunny-x"))
+}
+
+func TestIsPlanDowngrade(t *testing.T) {
+	assert.False(t, isPlanDowngrade("bunny-1", "hare-1"), "upgrade")
+	assert.False(t, isPlanDowngrade("bunny-1", "bunny-3"), "more nodes")
+	assert.False(t, isPlanDowngrade("bunny-1", "bunny-1"), "same plan")
+	assert.True(t, isPlanDowngrade("hare-1", "bunny-1"), "smaller nodes")
+	assert.True(t, isPlanDowngrade("hare-1", "lemur"), "dedicated to shared")
+	assert.False(t, isPlanDowngrade("custom-1", "lemur"), "unknown plan")
+}
+
+func TestCheckPlanDowngrade(t *testing.T) {
+	assert.NoError(t, checkPlanDowngrade("bunny-1", "hare-1", false))
+
+	err := checkPlanDowngrade("hare-1", "lemur", false)
+	assert.ErrorContains(t, err, "dedicated to a shared plan")
+	assert.ErrorContains(t, err, "--force")
+
+	assert.NoError(t, checkPlanDowngrade("hare-1", "lemur", true))
+}
+
+func TestGetPrinter_JSONLOnlyForLists(t *testing.T) {
+	rootCmd.PersistentFlags().Set("output", "jsonl")
+	defer rootCmd.PersistentFlags().Set("output", "table")
+
+	instanceListCmd.InheritedFlags()
+	_, err := getListPrinter(instanceListCmd)
+	assert.NoError(t, err)
+
+	instanceGetCmd.InheritedFlags()
+	_, err = getPrinter(instanceGetCmd)
+	assert.ErrorContains(t, err, "only supported by list commands")
+}
+
+func TestGetPrinter_Template(t *testing.T) {
+	flags := rootCmd.PersistentFlags()
+	defer func() {
+		flags.Set("output", "table")
+		flags.Set("template", "")
+		flags.Lookup("output").Changed = false
+		flags.Lookup("template").Changed = false
+	}()
+
+	instanceGetCmd.InheritedFlags()
+	flags.Set("template", "{{.Name}}")
+	flags.Lookup("output").Changed = false
+	_, err := getPrinter(instanceGetCmd)
+	assert.NoError(t, err, "--template alone implies -o template")
+
+	flags.Set("output", "json")
+	_, err = getPrinter(instanceGetCmd)
+	assert.ErrorContains(t, err, "--template cannot be combined with -o json")
+
+	flags.Set("output", "template")
+	flags.Set("template", "")
+	_, err = getPrinter(instanceGetCmd)
+	assert.ErrorContains(t, err, "requires a template")
+}
+
+func TestValidatePlan(t *testing.T) {
+	plans := []client.Plan{
+		{Name: "lemur", Backend: "rabbitmq", Shared: true},
+		{Name: "bunny-1", Backend: "rabbitmq"},
+		{Name: "bunny-3", Backend: "rabbitmq"},
+		{Name: "penguin-1", Backend: "lavinmq"},
+		{Name: "vpc", Backend: "vpc"},
+	}
+
+	assert.NoError(t, validatePlan("bunny-1", plans))
+	assert.NoError(t, validatePlan("penguin-1", plans))
+
+	assert.EqualError(t, validatePlan("buny-1", plans), "unknown plan 'buny-1'; did you mean 'bunny-1'?")
+	assert.EqualError(t, validatePlan("pengiun-1", plans), "unknown plan 'pengiun-1'; did you mean 'penguin-1'?")
+	assert.ErrorContains(t, validatePlan("vpc", plans), "unknown plan 'vpc'")
+	assert.ErrorContains(t, validatePlan("elephant-9", plans), "cloudamqp plans")
+}
+
+func TestSuggestClosest(t *testing.T) {
+	candidates := []string{"bunny-1", "rabbit-1", "lemur"}
+	assert.Equal(t, "lemur", suggestClosest("lemru", candidates))
+	assert.Equal(t, "rabbit-1", suggestClosest("RABIT-1", candidates))
+	assert.Equal(t, "", suggestClosest("zzzzzzzz", candidates))
+	assert.Equal(t, "", suggestClosest("x", nil))
+}
+
+func TestVersionsOutput(t *testing.T) {
+	lavin := versionsOutput(&client.VersionInfo{Backend: client.BackendLavinMQ, LavinMQVersions: []string{"2.1.0"}})
+	assert.Equal(t, map[string]any{"backend": "lavinmq", "lavinmq_versions": []string{"2.1.0"}}, lavin)
+
+	rabbit := versionsOutput(&client.VersionInfo{Backend: client.BackendRabbitMQ, RabbitMQVersions: []string{"4.0.5"}})
+	assert.Equal(t, map[string]any{
+		"backend":           "rabbitmq",
+		"rabbitmq_versions": []string{"4.0.5"},
+		"erlang_versions":   []string{},
+	}, rabbit)
+}
+
+func TestNodesVersionsCmd_AliasesAndBackend(t *testing.T) {
+	for _, name := range []string{"versions", "rabbitmq-versions", "lavinmq-versions"} {
+		found, _, err := rootCmd.Find([]string{"instance", "nodes", name})
+		require.NoError(t, err)
+		assert.Same(t, instanceNodesVersionsCmd, found, name)
+	}
+
+	cmd := instanceNodesVersionsCmd
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("backend", "kafka")
+	defer resetFlags(cmd)
+
+	err := cmd.RunE(cmd, []string{})
+	assert.ErrorContains(t, err, `invalid --backend "kafka"`)
+}
+
+func TestInstanceDeleteCmd_RefusesWithoutTerminal(t *testing.T) {
+	cmd := instanceDeleteCmd
+	cmd.Flags().Set("id", "1234")
+	defer resetFlags(cmd)
+	cmd.SetIn(strings.NewReader("y\n"))
+	defer cmd.SetIn(nil)
+
+	err := cmd.RunE(cmd, []string{})
+	assert.ErrorContains(t, err, "Use --force to proceed non-interactively")
+}
@@ -1,17 +1,33 @@
 package cmd
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
 var completionCmd = &cobra.Command{
-	Use:   "completion [zsh|fish]",
+	Use:   "completion [bash|zsh|fish|powershell]",
 	Short: "Generate shell completion script",
 	Long: `Generate shell completion script for cloudamqp CLI.
 
-To load completions:
+The easiest way to set up completion is:
+
+  cloudamqp completion install
+
+which writes the script to the usual location for your shell and explains
+how to activate it. To load completions yourself:
+
+Bash:
+
+  source <(cloudamqp completion bash)
 
 Zsh:
 
@@ -22,20 +38,158 @@ Zsh:
   cloudamqp completion zsh > "${fpath[1]}/_cloudamqp"
 
 Fish:
-  cloudamqp completion zsh > "$XDG_CONFIG_HOME/fish/completions/cloudamqp.fish"
+  cloudamqp completion fish > "$XDG_CONFIG_HOME/fish/completions/cloudamqp.fish"
+
+PowerShell:
+  cloudamqp completion powershell | Out-String | Invoke-Expression
 
 # You may need to restart your shell for completions to take effect.
 `,
 	DisableFlagsInUseLine: true,
-	ValidArgs:             []string{"zsh", "fish"},
+	ValidArgs:             completionShells,
 	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		switch args[0] {
-		case "zsh":
-			return cmd.Root().GenZshCompletion(os.Stdout)
-		case "fish":
-			return cmd.Root().GenFishCompletion(os.Stdout, false)
+		return generateCompletion(cmd.Root(), args[0], cmd.OutOrStdout())
+	},
+}
+
+var completionInstallCmd = &cobra.Command{
+	Use:   "install [bash|zsh|fish|powershell]",
+	Short: "Install the completion script for your shell",
+	Long: `Write the completion script to the conventional location for the shell and
+print how to activate it. Without an argument the shell is detected from
+$SHELL.
+
+  bash:       $XDG_DATA_HOME/bash-completion/completions/cloudamqp
+              (loaded automatically by bash-completion)
+  zsh:        ~/.zsh/completions/_cloudamqp (add the directory to fpath)
+  fish:       $XDG_CONFIG_HOME/fish/completions/cloudamqp.fish
+              (loaded automatically)
+  powershell: $XDG_CONFIG_HOME/powershell/cloudamqp-completion.ps1
+              (dot-source it from $PROFILE)
+
+Run it again after upgrading the CLI to pick up new commands and flags.`,
+	Example: `  cloudamqp completion install
+  cloudamqp completion install zsh`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             completionShells,
+	Args:                  cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shell := ""
+		if len(args) > 0 {
+			shell = args[0]
+		} else {
+			var err error
+			if shell, err = detectShell(os.Getenv("SHELL")); err != nil {
+				return err
+			}
+		}
+
+		path, activate, err := completionInstallPath(shell)
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := generateCompletion(cmd.Root(), shell, &buf); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create completion directory: %w", err)
 		}
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write completion script: %w", err)
+		}
+
+		printStatus(cmd, "Installed %s completion to %s", shell, path)
+		printStatus(cmd, "%s", activate)
 		return nil
 	},
 }
+
+// generateCompletion writes the completion script for shell to w.
+func generateCompletion(root *cobra.Command, shell string, w io.Writer) error {
+	switch shell {
+	case "bash":
+		return root.GenBashCompletionV2(w, true)
+	case "zsh":
+		return root.GenZshCompletion(w)
+	case "fish":
+		return root.GenFishCompletion(w, true)
+	case "powershell":
+		return root.GenPowerShellCompletionWithDesc(w)
+	}
+	return fmt.Errorf("unsupported shell %q: must be one of %s", shell, strings.Join(completionShells, ", "))
+}
+
+// detectShell maps the path in $SHELL to a shell completion install knows.
+func detectShell(shellPath string) (string, error) {
+	if shellPath == "" {
+		return "", fmt.Errorf("cannot detect your shell: $SHELL is not set. Pass one of %s", strings.Join(completionShells, ", "))
+	}
+	switch name := filepath.Base(shellPath); name {
+	case "bash", "zsh", "fish":
+		return name, nil
+	case "pwsh", "powershell", "pwsh.exe", "powershell.exe":
+		return "powershell", nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q from $SHELL. Pass one of %s", name, strings.Join(completionShells, ", "))
+	}
+}
+
+// completionInstallPath returns where completion install writes the script
+// for shell, and what the user has to do to activate it.
+func completionInstallPath(shell string) (string, string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	xdg := func(env, fallback string) string {
+		if dir := os.Getenv(env); dir != "" {
+			return dir
+		}
+		return filepath.Join(home, fallback)
+	}
+
+	switch shell {
+	case "bash":
+		path := filepath.Join(xdg("XDG_DATA_HOME", ".local/share"), "bash-completion", "completions", "cloudamqp")
+		return path, "Start a new shell to use it (requires the bash-completion package).", nil
+	case "zsh":
+		dir := filepath.Join(home, ".zsh", "completions")
+		return filepath.Join(dir, "_cloudamqp"), fmt.Sprintf("Add these lines to ~/.zshrc if they are not there yet, then start a new shell:\n  fpath=(%s $fpath)\n  autoload -U compinit && compinit", dir), nil
+	case "fish":
+		path := filepath.Join(xdg("XDG_CONFIG_HOME", ".config"), "fish", "completions", "cloudamqp.fish")
+		return path, "Start a new shell to use it.", nil
+	case "powershell":
+		path := filepath.Join(xdg("XDG_CONFIG_HOME", ".config"), "powershell", "cloudamqp-completion.ps1")
+		return path, fmt.Sprintf("Add this line to your PowerShell profile ($PROFILE), then start a new shell:\n  . %s", path), nil
+	}
+	return "", "", fmt.Errorf("unsupported shell %q: must be one of %s", shell, strings.Join(completionShells, ", "))
+}
+
+var completionRefreshCacheCmd = &cobra.Command{
+	Use:   "refresh-cache",
+	Short: "Refresh the cached instances and VPCs used by completion",
+	Long: `Completion caches instance and VPC lists for a minute per API key. When the
+cache is stale, completion serves it and refreshes it in the background with
+this command. Run it yourself after creating or deleting resources, or pass
+--no-cache to bypass the cache.
+
+Plans and regions are cached for an hour and are not refreshed by this
+command; once stale they are fetched again during completion.`,
+	Example: `  cloudamqp completion refresh-cache`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiKey, err := completionAPIKey()
+		if err != nil {
+			return err
+		}
+		return refreshCompletionCache(apiKey)
+	},
+}
+
+func init() {
+	completionCmd.AddCommand(completionInstallCmd)
+	completionCmd.AddCommand(completionRefreshCacheCmd)
+}
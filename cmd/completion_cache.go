@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"time"
 )
@@ -45,39 +48,97 @@ func formatTTL(ttl time.Duration) string {
 	return fmt.Sprintf("%dm", minutes)
 }
 
-// getCacheFilename returns the cache filename with TTL information
-func getCacheFilename(key string, ttl time.Duration) string {
-	return fmt.Sprintf("cache_%s_ttl_%s.json", formatTTL(ttl), key)
+// cacheScope identifies the account an API key belongs to without storing
+// the key itself, so switching keys never shows another account's data.
+func cacheScope(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:6])
 }
 
+// getCacheFilename returns the cache filename with TTL and account information
+func getCacheFilename(apiKey, key string, ttl time.Duration) string {
+	return fmt.Sprintf("cache_%s_ttl_%s_%s.json", formatTTL(ttl), cacheScope(apiKey), key)
+}
+
+// noCache makes completion always fetch from the API, set by --no-cache.
+var noCache bool
+
 // getCachedData retrieves cached data if it exists and is not expired
-func getCachedData(key string, ttl time.Duration) (json.RawMessage, bool) {
+func getCachedData(apiKey, key string, ttl time.Duration) (json.RawMessage, bool) {
+	data, fresh, ok := getStaleCachedData(apiKey, key, ttl)
+	if !ok || !fresh {
+		return nil, false
+	}
+	return data, true
+}
+
+// getStaleCachedData retrieves cached data even if it has expired, and
+// reports whether it is still fresh.
+func getStaleCachedData(apiKey, key string, ttl time.Duration) (data json.RawMessage, fresh, ok bool) {
+	if noCache {
+		return nil, false, false
+	}
+
 	cacheDir, err := getCacheDir()
 	if err != nil {
-		return nil, false
+		return nil, false, false
 	}
 
-	cachePath := filepath.Join(cacheDir, getCacheFilename(key, ttl))
-	data, err := os.ReadFile(cachePath)
+	cachePath := filepath.Join(cacheDir, getCacheFilename(apiKey, key, ttl))
+	raw, err := os.ReadFile(cachePath)
 	if err != nil {
-		return nil, false
+		return nil, false, false
 	}
 
 	var entry cacheEntry
-	if err := json.Unmarshal(data, &entry); err != nil {
-		return nil, false
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, false, false
 	}
 
-	// Check if cache is expired
-	if time.Now().Unix()-entry.Timestamp > int64(ttl.Seconds()) {
-		return nil, false
+	fresh = time.Now().Unix()-entry.Timestamp <= int64(ttl.Seconds())
+	return entry.Data, fresh, true
+}
+
+// refreshCacheInBackground starts 'completion refresh-cache' as a detached
+// process, so a stale cache can be served now and be fresh on the next TAB.
+// Tests replace it.
+var refreshCacheInBackground = func() {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	refresh := exec.Command(exe, "completion", "refresh-cache")
+	if err := refresh.Start(); err != nil {
+		return
 	}
+	refresh.Process.Release()
+}
+
+// refreshCompletionCache fetches the data that changes often and stores it
+// in the completion cache.
+func refreshCompletionCache(apiKey string) error {
+	c := newAPIClient(apiKey)
 
-	return entry.Data, true
+	instances, err := c.ListInstances()
+	if err != nil {
+		return fmt.Errorf("failed to list instances: %w", err)
+	}
+	if err := setCachedData(apiKey, "instances", instancesCacheTTL, instances); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	vpcs, err := c.ListVPCs()
+	if err != nil {
+		return fmt.Errorf("failed to list VPCs: %w", err)
+	}
+	if err := setCachedData(apiKey, "vpcs", vpcsCacheTTL, vpcs); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
 }
 
 // setCachedData stores data in the cache with current timestamp
-func setCachedData(key string, ttl time.Duration, data interface{}) error {
+func setCachedData(apiKey, key string, ttl time.Duration, data interface{}) error {
 	cacheDir, err := getCacheDir()
 	if err != nil {
 		return err
@@ -98,7 +159,7 @@ func setCachedData(key string, ttl time.Duration, data interface{}) error {
 		return err
 	}
 
-	cachePath := filepath.Join(cacheDir, getCacheFilename(key, ttl))
+	cachePath := filepath.Join(cacheDir, getCacheFilename(apiKey, key, ttl))
 	return os.WriteFile(cachePath, entryData, 0600)
 }
 
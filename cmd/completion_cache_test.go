@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionCache_ScopedPerAPIKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	require.NoError(t, setCachedData("key-a", "instances", instancesCacheTTL, []int{1}))
+
+	data, ok := getCachedData("key-a", "instances", instancesCacheTTL)
+	assert.True(t, ok)
+	assert.JSONEq(t, "[1]", string(data))
+
+	_, ok = getCachedData("key-b", "instances", instancesCacheTTL)
+	assert.False(t, ok, "another API key must not see the cached data")
+}
+
+func TestCompletionCache_NoCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	require.NoError(t, setCachedData("key", "instances", instancesCacheTTL, []int{1}))
+
+	noCache = true
+	defer func() { noCache = false }()
+
+	_, ok := getCachedData("key", "instances", instancesCacheTTL)
+	assert.False(t, ok)
+}
+
+func TestCompleteInstances_ServesStaleCacheAndRefreshes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CLOUDAMQP_APIKEY", "key")
+
+	refreshed := false
+	orig := refreshCacheInBackground
+	refreshCacheInBackground = func() { refreshed = true }
+	defer func() { refreshCacheInBackground = orig }()
+
+	// Write an entry that expired a minute ago
+	instances, err := json.Marshal([]client.Instance{{ID: 1234, Name: "production"}})
+	require.NoError(t, err)
+	entry, err := json.Marshal(cacheEntry{Data: instances, Timestamp: time.Now().Add(-2 * instancesCacheTTL).Unix()})
+	require.NoError(t, err)
+	cacheDir, err := getCacheDir()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, getCacheFilename("key", "instances", instancesCacheTTL)), entry, 0600))
+
+	suggestions, _ := completeInstances(instanceGetCmd, nil, "")
+
+	assert.Equal(t, []string{"1234\tproduction"}, suggestions)
+	assert.True(t, refreshed, "a stale cache should be refreshed in the background")
+}
+
+func TestCompleteVPCs_ServesStaleCacheAndRefreshes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CLOUDAMQP_APIKEY", "key")
+
+	refreshed := false
+	orig := refreshCacheInBackground
+	refreshCacheInBackground = func() { refreshed = true }
+	defer func() { refreshCacheInBackground = orig }()
+
+	vpcs, err := json.Marshal([]client.VPC{{ID: 5678, Name: "prod", Region: "amazon-web-services::us-east-1"}})
+	require.NoError(t, err)
+	entry, err := json.Marshal(cacheEntry{Data: vpcs, Timestamp: time.Now().Add(-2 * vpcsCacheTTL).Unix()})
+	require.NoError(t, err)
+	cacheDir, err := getCacheDir()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, getCacheFilename("key", "vpcs", vpcsCacheTTL)), entry, 0600))
+
+	suggestions, _ := completeVPCs(vpcGetCmd, nil, "")
+
+	assert.Equal(t, []string{"5678\tprod (amazon-web-services::us-east-1)"}, suggestions)
+	assert.True(t, refreshed, "a stale cache should be refreshed in the background")
+}
+
+// vpcListClient lists a fixed set of VPCs.
+type vpcListClient struct {
+	fakeClient
+	vpcs []client.VPC
+}
+
+func (f *vpcListClient) ListVPCs() ([]client.VPC, error) {
+	return f.vpcs, nil
+}
+
+func TestRefreshCompletionCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	useFakeClient(t, &vpcListClient{
+		fakeClient: fakeClient{instances: map[int]*client.Instance{1234: {ID: 1234, Name: "orders"}}},
+		vpcs:       []client.VPC{{ID: 5678, Name: "prod"}},
+	})
+
+	require.NoError(t, refreshCompletionCache("key"))
+
+	data, fresh, ok := getStaleCachedData("key", "instances", instancesCacheTTL)
+	require.True(t, ok)
+	assert.True(t, fresh)
+	assert.Contains(t, string(data), `"name":"orders"`)
+
+	data, _, ok = getStaleCachedData("key", "vpcs", vpcsCacheTTL)
+	require.True(t, ok)
+	assert.Contains(t, string(data), `"name":"prod"`)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"cloudamqp-cli/client"
 	"github.com/spf13/cobra"
@@ -25,6 +26,13 @@ func completionAPIKey() (string, error) {
 	return "", fmt.Errorf("API key not configured: %w", err)
 }
 
+// completionDescription makes s safe to use as the description part of a
+// "value\tdescription" completion: tabs and newlines would otherwise split it
+// into bogus completions.
+func completionDescription(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
 // completeInstances returns a list of instance IDs and names for completion
 func completeInstances(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 	apiKey, err := completionAPIKey()
@@ -34,10 +42,13 @@ func completeInstances(cmd *cobra.Command, args []string, toComplete string) ([]
 
 	c := client.New(apiKey, Version)
 
-	// Try to get from cache
+	// Try to get from cache, serving stale data while it refreshes
 	var instances []client.Instance
-	if cachedData, ok := getCachedData("instances", instancesCacheTTL); ok {
+	if cachedData, fresh, ok := getStaleCachedData(apiKey, "instances", instancesCacheTTL); ok {
 		if err := json.Unmarshal(cachedData, &instances); err == nil {
+			if !fresh {
+				refreshCacheInBackground()
+			}
 			goto formatOutput
 		}
 	}
@@ -49,12 +60,12 @@ func completeInstances(cmd *cobra.Command, args []string, toComplete string) ([]
 	}
 
 	// Store in cache
-	setCachedData("instances", instancesCacheTTL, instances)
+	setCachedData(apiKey, "instances", instancesCacheTTL, instances)
 
 formatOutput:
 	var suggestions []string
 	for _, instance := range instances {
-		suggestions = append(suggestions, fmt.Sprintf("%d\t%s", instance.ID, instance.Name))
+		suggestions = append(suggestions, fmt.Sprintf("%d\t%s", instance.ID, completionDescription(instance.Name)))
 	}
 
 	return suggestions, cobra.ShellCompDirectiveNoFileComp
@@ -71,7 +82,7 @@ func completePlans(cmd *cobra.Command, args []string, toComplete string) ([]stri
 
 	// Try to get from cache
 	var plans []client.Plan
-	if cachedData, ok := getCachedData("plans", plansCacheTTL); ok {
+	if cachedData, ok := getCachedData(apiKey, "plans", plansCacheTTL); ok {
 		if err := json.Unmarshal(cachedData, &plans); err == nil {
 			goto formatOutput
 		}
@@ -84,7 +95,7 @@ func completePlans(cmd *cobra.Command, args []string, toComplete string) ([]stri
 	}
 
 	// Store in cache
-	setCachedData("plans", plansCacheTTL, plans)
+	setCachedData(apiKey, "plans", plansCacheTTL, plans)
 
 formatOutput:
 	var suggestions []string
@@ -106,7 +117,7 @@ func completeRegions(cmd *cobra.Command, args []string, toComplete string) ([]st
 
 	// Try to get from cache
 	var regions []client.Region
-	if cachedData, ok := getCachedData("regions", regionsCacheTTL); ok {
+	if cachedData, ok := getCachedData(apiKey, "regions", regionsCacheTTL); ok {
 		if err := json.Unmarshal(cachedData, &regions); err == nil {
 			goto formatOutput
 		}
@@ -119,7 +130,7 @@ func completeRegions(cmd *cobra.Command, args []string, toComplete string) ([]st
 	}
 
 	// Store in cache
-	setCachedData("regions", regionsCacheTTL, regions)
+	setCachedData(apiKey, "regions", regionsCacheTTL, regions)
 
 formatOutput:
 	var suggestions []string
@@ -140,10 +151,13 @@ func completeVPCs(cmd *cobra.Command, args []string, toComplete string) ([]strin
 
 	c := client.New(apiKey, Version)
 
-	// Try to get from cache
+	// Try to get from cache, serving stale data while it refreshes
 	var vpcs []client.VPC
-	if cachedData, ok := getCachedData("vpcs", vpcsCacheTTL); ok {
+	if cachedData, fresh, ok := getStaleCachedData(apiKey, "vpcs", vpcsCacheTTL); ok {
 		if err := json.Unmarshal(cachedData, &vpcs); err == nil {
+			if !fresh {
+				refreshCacheInBackground()
+			}
 			goto formatOutput
 		}
 	}
@@ -155,12 +169,12 @@ func completeVPCs(cmd *cobra.Command, args []string, toComplete string) ([]strin
 	}
 
 	// Store in cache
-	setCachedData("vpcs", vpcsCacheTTL, vpcs)
+	setCachedData(apiKey, "vpcs", vpcsCacheTTL, vpcs)
 
 formatOutput:
 	var suggestions []string
 	for _, vpc := range vpcs {
-		suggestions = append(suggestions, fmt.Sprintf("%d\t%s (%s)", vpc.ID, vpc.Name, vpc.Region))
+		suggestions = append(suggestions, fmt.Sprintf("%d\t%s (%s)", vpc.ID, completionDescription(vpc.Name), vpc.Region))
 	}
 
 	return suggestions, cobra.ShellCompDirectiveNoFileComp
@@ -182,7 +196,7 @@ func completeVersions(cmd *cobra.Command, args []string, toComplete string) ([]s
 	}
 
 	var plans []client.Plan
-	if cachedData, ok := getCachedData("plans", plansCacheTTL); ok {
+	if cachedData, ok := getCachedData(apiKey, "plans", plansCacheTTL); ok {
 		_ = json.Unmarshal(cachedData, &plans)
 	}
 	if len(plans) == 0 {
@@ -191,7 +205,7 @@ func completeVersions(cmd *cobra.Command, args []string, toComplete string) ([]s
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
 		if len(plans) > 0 {
-			setCachedData("plans", plansCacheTTL, plans)
+			setCachedData(apiKey, "plans", plansCacheTTL, plans)
 		}
 	}
 
@@ -207,7 +221,7 @@ func completeVersions(cmd *cobra.Command, args []string, toComplete string) ([]s
 	}
 
 	var versions []string
-	if cachedData, ok := getCachedData("versions", versionsCacheTTL); ok {
+	if cachedData, ok := getCachedData(apiKey, "versions", versionsCacheTTL); ok {
 		if err := json.Unmarshal(cachedData, &versions); err == nil {
 			return versions, cobra.ShellCompDirectiveNoFileComp
 		}
@@ -218,7 +232,7 @@ func completeVersions(cmd *cobra.Command, args []string, toComplete string) ([]s
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
 
-	setCachedData("versions", versionsCacheTTL, versions)
+	setCachedData(apiKey, "versions", versionsCacheTTL, versions)
 
 	return versions, cobra.ShellCompDirectiveNoFileComp
 }
@@ -249,10 +263,13 @@ func completeVPCIDFlag(cmd *cobra.Command, args []string, toComplete string) ([]
 
 	c := client.New(apiKey, Version)
 
-	// Try to get from cache
+	// Try to get from cache, serving stale data while it refreshes
 	var vpcs []client.VPC
-	if cachedData, ok := getCachedData("vpcs", vpcsCacheTTL); ok {
+	if cachedData, fresh, ok := getStaleCachedData(apiKey, "vpcs", vpcsCacheTTL); ok {
 		if err := json.Unmarshal(cachedData, &vpcs); err == nil {
+			if !fresh {
+				refreshCacheInBackground()
+			}
 			goto formatOutput
 		}
 	}
@@ -264,7 +281,7 @@ func completeVPCIDFlag(cmd *cobra.Command, args []string, toComplete string) ([]
 	}
 
 	// Store in cache
-	setCachedData("vpcs", vpcsCacheTTL, vpcs)
+	setCachedData(apiKey, "vpcs", vpcsCacheTTL, vpcs)
 
 formatOutput:
 	var suggestions []string
@@ -286,7 +303,7 @@ func completeCopyFromIDFlag(cmd *cobra.Command, args []string, toComplete string
 
 	// Try to get from cache
 	var instances []client.Instance
-	if cachedData, ok := getCachedData("instances", instancesCacheTTL); ok {
+	if cachedData, ok := getCachedData(apiKey, "instances", instancesCacheTTL); ok {
 		if err := json.Unmarshal(cachedData, &instances); err == nil {
 			goto formatOutput
 		}
@@ -299,12 +316,12 @@ func completeCopyFromIDFlag(cmd *cobra.Command, args []string, toComplete string
 	}
 
 	// Store in cache
-	setCachedData("instances", instancesCacheTTL, instances)
+	setCachedData(apiKey, "instances", instancesCacheTTL, instances)
 
 formatOutput:
 	var suggestions []string
 	for _, instance := range instances {
-		suggestions = append(suggestions, fmt.Sprintf("%s\t%s", strconv.Itoa(instance.ID), instance.Name))
+		suggestions = append(suggestions, fmt.Sprintf("%s\t%s", strconv.Itoa(instance.ID), completionDescription(instance.Name)))
 	}
 
 	return suggestions, cobra.ShellCompDirectiveNoFileComp
@@ -319,10 +336,13 @@ func completeVPCArgs(cmd *cobra.Command, args []string, toComplete string) ([]st
 
 	c := client.New(apiKey, Version)
 
-	// Try to get from cache
+	// Try to get from cache, serving stale data while it refreshes
 	var vpcs []client.VPC
-	if cachedData, ok := getCachedData("vpcs", vpcsCacheTTL); ok {
+	if cachedData, fresh, ok := getStaleCachedData(apiKey, "vpcs", vpcsCacheTTL); ok {
 		if err := json.Unmarshal(cachedData, &vpcs); err == nil {
+			if !fresh {
+				refreshCacheInBackground()
+			}
 			goto formatOutput
 		}
 	}
@@ -334,12 +354,12 @@ func completeVPCArgs(cmd *cobra.Command, args []string, toComplete string) ([]st
 	}
 
 	// Store in cache
-	setCachedData("vpcs", vpcsCacheTTL, vpcs)
+	setCachedData(apiKey, "vpcs", vpcsCacheTTL, vpcs)
 
 formatOutput:
 	var suggestions []string
 	for _, vpc := range vpcs {
-		suggestions = append(suggestions, fmt.Sprintf("%d\t%s (%s)", vpc.ID, vpc.Name, vpc.Region))
+		suggestions = append(suggestions, fmt.Sprintf("%d\t%s (%s)", vpc.ID, completionDescription(vpc.Name), vpc.Region))
 	}
 
 	return suggestions, cobra.ShellCompDirectiveNoFileComp
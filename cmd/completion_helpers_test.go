@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteInstances_NameAsDescription(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("CLOUDAMQP_APIKEY", "key")
+
+	require.NoError(t, setCachedData("key", "instances", instancesCacheTTL, []client.Instance{
+		{ID: 1234, Name: "production-broker"},
+		{ID: 5678, Name: "staging\tbroker\nold"},
+	}))
+
+	suggestions, _ := completeInstanceIDFlag(instanceGetCmd, nil, "")
+
+	assert.Equal(t, []string{"1234\tproduction-broker", "5678\tstaging broker old"}, suggestions)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectShell(t *testing.T) {
+	tests := map[string]string{
+		"/bin/bash":          "bash",
+		"/usr/local/bin/zsh": "zsh",
+		"/opt/homebrew/fish": "fish",
+		"/usr/bin/pwsh":      "powershell",
+	}
+	for path, want := range tests {
+		shell, err := detectShell(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, want, shell, path)
+	}
+
+	_, err := detectShell("")
+	assert.ErrorContains(t, err, "$SHELL is not set")
+	_, err = detectShell("/bin/tcsh")
+	assert.ErrorContains(t, err, `unsupported shell "tcsh"`)
+}
+
+func TestCompletionInstallCmd(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("SHELL", "/usr/bin/fish")
+
+	var stderr bytes.Buffer
+	cmd := completionInstallCmd
+	cmd.SetErr(&stderr)
+	defer cmd.SetErr(nil)
+
+	require.NoError(t, cmd.RunE(cmd, []string{}))
+
+	path := filepath.Join(home, ".config", "fish", "completions", "cloudamqp.fish")
+	script, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "complete -c cloudamqp")
+	assert.Contains(t, stderr.String(), "Installed fish completion to "+path)
+}
+
+func TestCompletionInstallPath_Zsh(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	path, activate, err := completionInstallPath("zsh")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".zsh", "completions", "_cloudamqp"), path)
+	assert.Contains(t, activate, "fpath=("+filepath.Join(home, ".zsh", "completions")+" $fpath)")
+}
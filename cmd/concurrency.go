@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+)
+
+// maxDefaultConcurrency caps the default of --concurrency on machines with
+// many CPUs; the API, not the CPU, is what parallel commands wait on.
+const maxDefaultConcurrency = 8
+
+// concurrency is the global --concurrency flag, 0 when not given.
+var concurrency int
+
+// concurrencyLimit returns how many API operations commands that work on
+// several instances, such as bulk actions and instance list --details, run
+// at once: --concurrency, else CLOUDAMQP_CONCURRENCY, else the number of
+// CPUs capped at maxDefaultConcurrency.
+func concurrencyLimit() int {
+	if concurrency > 0 {
+		return concurrency
+	}
+	if n, err := strconv.Atoi(os.Getenv("CLOUDAMQP_CONCURRENCY")); err == nil && n > 0 {
+		return n
+	}
+	return min(runtime.NumCPU(), maxDefaultConcurrency)
+}
+
+// checkConcurrency rejects an invalid --concurrency or CLOUDAMQP_CONCURRENCY
+// before any command runs, rather than silently using the default.
+func checkConcurrency() error {
+	if concurrency < 0 {
+		return fmt.Errorf("invalid --concurrency %d: must be at least 1", concurrency)
+	}
+	if env := os.Getenv("CLOUDAMQP_CONCURRENCY"); env != "" && concurrency == 0 {
+		if n, err := strconv.Atoi(env); err != nil || n < 1 {
+			return fmt.Errorf("invalid CLOUDAMQP_CONCURRENCY %q: must be a whole number of at least 1", env)
+		}
+	}
+	return nil
+}
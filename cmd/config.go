@@ -24,17 +24,17 @@ func getAPIKey() (string, error) {
 	}
 
 	// If neither exists, prompt user and save to file
-	fmt.Print("CloudAMQP API key not found. Please enter your API key: ")
+	fmt.Fprint(os.Stderr, "CloudAMQP API key not found. Please enter your API key: ")
 	apiKey, err = readPassword()
 	if err != nil {
 		return "", fmt.Errorf("failed to read API key: %w", err)
 	}
 
 	if err := saveAPIKey(apiKey); err != nil {
-		fmt.Printf("Warning: failed to save API key to config file: %v\n", err)
+		logWarn("failed to save API key to config file: %v", err)
 	} else {
 		configPath, _ := getConfigPath()
-		fmt.Printf("API key saved to %s\n", configPath)
+		logInfo("API key saved to %s", configPath)
 	}
 
 	return apiKey, nil
@@ -46,7 +46,36 @@ func saveAPIKey(apiKey string) error {
 		return err
 	}
 
-	return os.WriteFile(configPath, []byte(strings.TrimSpace(apiKey)), 0600)
+	return writeFileAtomic(configPath, []byte(strings.TrimSpace(apiKey)), 0600)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
 }
 
 func getConfigPath() (string, error) {
@@ -78,7 +107,7 @@ func readPassword() (string, error) {
 		if err != nil {
 			return "", err
 		}
-		fmt.Println() // Add newline after hidden input
+		fmt.Fprintln(os.Stderr) // Add newline after hidden input
 		return string(bytePassword), nil
 	} else {
 		// Non-terminal input - read normally
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"strings"
+)
+
+// getByPath looks up a dotted setting name in config. Setting names contain
+// dots themselves, so at every level the whole remaining path is tried as a
+// key first, then each dotted prefix that holds a nested map, longest first:
+// rabbit.tcp_listen_options.backlog is found both in a flat key and in
+// {"rabbit.tcp_listen_options": {"backlog": ...}}. A null value, which is
+// how the API reports an unset setting, counts as not found.
+func getByPath(m map[string]interface{}, path string) (interface{}, bool) {
+	if value, ok := m[path]; ok && value != nil {
+		return value, true
+	}
+	for i := strings.LastIndexByte(path, '.'); i > 0; i = strings.LastIndexByte(path[:i], '.') {
+		nested, ok := m[path[:i]].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if value, ok := getByPath(nested, path[i+1:]); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// setByPath sets a dotted setting name in config, the counterpart of
+// getByPath. An existing key or nested map along the path is followed;
+// otherwise the remaining path is created as nested maps, one per segment.
+func setByPath(m map[string]interface{}, path string, value interface{}) {
+	if _, ok := m[path]; ok {
+		m[path] = value
+		return
+	}
+	for i := strings.LastIndexByte(path, '.'); i > 0; i = strings.LastIndexByte(path[:i], '.') {
+		if nested, ok := m[path[:i]].(map[string]interface{}); ok {
+			setByPath(nested, path[i+1:], value)
+			return
+		}
+	}
+
+	segments := strings.Split(path, ".")
+	for _, segment := range segments[:len(segments)-1] {
+		nested := map[string]interface{}{}
+		m[segment] = nested
+		m = nested
+	}
+	m[segments[len(segments)-1]] = value
+}
+
+// configUpdate builds the body that sets key to value. A key that reaches
+// into a nested setting of the current config, such as
+// rabbit.tcp_listen_options.backlog, updates a copy of that top-level setting
+// so the values next to it are kept. Other keys are sent as they are.
+func configUpdate(current map[string]interface{}, key string, value interface{}) map[string]interface{} {
+	top := ""
+	for name, v := range current {
+		if _, ok := v.(map[string]interface{}); ok && strings.HasPrefix(key, name+".") && len(name) > len(top) {
+			top = name
+		}
+	}
+	if top == "" {
+		return map[string]interface{}{key: value}
+	}
+
+	nested := copyConfigMap(current[top].(map[string]interface{}))
+	setByPath(nested, strings.TrimPrefix(key, top+"."), value)
+	return map[string]interface{}{top: nested}
+}
+
+// copyConfigMap deep-copies the nested maps of a decoded config value.
+func copyConfigMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if nested, ok := v.(map[string]interface{}); ok {
+			v = copyConfigMap(nested)
+		}
+		out[k] = v
+	}
+	return out
+}
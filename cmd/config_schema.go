@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cloudamqp-cli/client"
+)
+
+// configType is the JSON type a broker configuration value must have.
+type configType string
+
+const (
+	configInt    configType = "int"
+	configFloat  configType = "float"
+	configBool   configType = "bool"
+	configString configType = "string"
+)
+
+// configSetting describes a broker setting that can be changed through the
+// API, with the default CloudAMQP applies when it is not configured.
+type configSetting struct {
+	Key     string
+	Type    configType
+	Default any
+	// Values lists the accepted values of string settings, if restricted.
+	Values []string
+}
+
+// rabbitMQConfigSchema is the set of settings accepted by the RabbitMQ config
+// endpoint.
+// The API has no schema endpoint, so it is bundled here.
+var rabbitMQConfigSchema = []configSetting{
+	{Key: "rabbit.heartbeat", Type: configInt, Default: 120},
+	{Key: "rabbit.connection_max", Type: configInt, Default: -1},
+	{Key: "rabbit.channel_max", Type: configInt, Default: 0},
+	{Key: "rabbit.consumer_timeout", Type: configInt, Default: 7200000},
+	{Key: "rabbit.vm_memory_high_watermark", Type: configFloat, Default: 0.81},
+	{Key: "rabbit.queue_index_embed_msgs_below", Type: configInt, Default: 4096},
+	{Key: "rabbit.max_message_size", Type: configInt, Default: 134217728},
+	{Key: "rabbit.log.exchange.level", Type: configString, Default: "error",
+		Values: []string{"debug", "info", "warning", "error", "critical", "none"}},
+	{Key: "rabbit.cluster_partition_handling", Type: configString, Default: "autoheal",
+		Values: []string{"autoheal", "pause_minority", "ignore"}},
+	{Key: "mqtt.exchange", Type: configString, Default: "amq.topic"},
+	{Key: "ssl_options.fail_if_no_peer_cert", Type: configBool, Default: false},
+	{Key: "ssl_options.verify", Type: configString, Default: "verify_none",
+		Values: []string{"verify_none", "verify_peer"}},
+}
+
+// lavinMQConfigSchema is the set of settings accepted by the LavinMQ config
+// endpoint, named after the section and key in lavinmq.ini.
+var lavinMQConfigSchema = []configSetting{
+	{Key: "main.log_level", Type: configString, Default: "info",
+		Values: []string{"debug", "info", "warn", "error", "fatal", "none"}},
+	{Key: "main.default_consumer_prefetch", Type: configInt, Default: 65535},
+	{Key: "main.max_deleted_definitions", Type: configInt, Default: 8192},
+	{Key: "main.segment_size", Type: configInt, Default: 8388608},
+	{Key: "main.set_timestamp", Type: configBool, Default: false},
+	{Key: "main.free_disk_min", Type: configInt, Default: 0},
+	{Key: "main.stats_interval", Type: configInt, Default: 5000},
+	{Key: "main.stats_log_size", Type: configInt, Default: 120},
+	{Key: "amqp.heartbeat", Type: configInt, Default: 300},
+	{Key: "amqp.channel_max", Type: configInt, Default: 2048},
+	{Key: "amqp.frame_max", Type: configInt, Default: 131072},
+	{Key: "amqp.max_message_size", Type: configInt, Default: 134217728},
+}
+
+// configSafeRange is the range of values a setting can take without risking
+// the stability of the broker.
+type configSafeRange struct {
+	Min, Max float64
+	// Risk explains what goes wrong outside the range.
+	Risk string
+}
+
+// configSafeRanges are the settings that can destabilize a broker when set
+// wrong. Values outside the range need --force in config set and import,
+// update --config-file and apply. Other settings are not range checked.
+var configSafeRanges = map[string]configSafeRange{
+	"rabbit.vm_memory_high_watermark": {Min: 0.1, Max: 0.9,
+		Risk: "a high watermark lets RabbitMQ use more memory than the server can spare and get killed; a low one blocks publishers almost at once"},
+	"rabbit.disk_free_limit": {Min: 50000000, Max: math.Inf(1),
+		Risk: "with less than 50 MB free the disk can fill up before publishers are blocked"},
+	"rabbit.consumer_timeout": {Min: 60000, Max: math.Inf(1),
+		Risk: "consumers that take longer than a minute to ack have their channel closed"},
+	"rabbit.max_message_size": {Min: 1, Max: 536870912,
+		Risk: "RabbitMQ refuses to start with a limit above 512 MiB"},
+	"amqp.frame_max": {Min: 4096, Max: math.Inf(1),
+		Risk: "AMQP clients can't connect with a frame size below 4096 bytes"},
+}
+
+// checkConfigSafety returns an error if value is a number outside the safe
+// range of key. Settings without a range, and values that aren't numbers,
+// such as "2GB" for rabbit.disk_free_limit, are not checked.
+func checkConfigSafety(key string, value any) error {
+	r, ok := configSafeRanges[key]
+	if !ok {
+		return nil
+	}
+	var v float64
+	switch n := value.(type) {
+	case int:
+		v = float64(n)
+	case int64:
+		v = float64(n)
+	case float64:
+		v = n
+	default:
+		return nil
+	}
+	if v >= r.Min && v <= r.Max {
+		return nil
+	}
+	minimum := strconv.FormatFloat(r.Min, 'f', -1, 64)
+	if math.IsInf(r.Max, 1) {
+		return fmt.Errorf("%s: %v is below the safe minimum of %s: %s", key, value, minimum, r.Risk)
+	}
+	return fmt.Errorf("%s: %v is outside the safe range %s to %s: %s", key, value, minimum, strconv.FormatFloat(r.Max, 'f', -1, 64), r.Risk)
+}
+
+// checkConfigValuesSafety runs checkConfigSafety on every setting in
+// config, in key order. Without force the first unsafe value is an error
+// suggesting --force to verb it anyway; with force each is logged as a
+// warning.
+func checkConfigValuesSafety(config map[string]any, force bool, verb string) error {
+	for _, key := range sortedKeys(config) {
+		if err := checkConfigSafety(key, config[key]); err != nil {
+			if !force {
+				return fmt.Errorf("%w. Use --force to %s it anyway", err, verb)
+			}
+			logWarn("%v", err)
+		}
+	}
+	return nil
+}
+
+// configSchema returns the settings of the given broker backend.
+func configSchema(backend string) []configSetting {
+	if backend == client.BackendLavinMQ {
+		return lavinMQConfigSchema
+	}
+	return rabbitMQConfigSchema
+}
+
+// brokerName is the display name of a backend.
+func brokerName(backend string) string {
+	if backend == client.BackendLavinMQ {
+		return "LavinMQ"
+	}
+	return "RabbitMQ"
+}
+
+// lookupConfigSetting returns the schema entry for key.
+func lookupConfigSetting(schema []configSetting, key string) (configSetting, bool) {
+	for _, s := range schema {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return configSetting{}, false
+}
+
+// configSettingKeys returns the keys of all settings in schema, sorted.
+func configSettingKeys(schema []configSetting) []string {
+	keys := make([]string, len(schema))
+	for i, s := range schema {
+		keys[i] = s.Key
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validateConfigSetting checks that key is a setting of the backend's broker
+// and that value has the type, and for restricted strings one of the values,
+// it expects. A key that belongs to the other broker is reported as such.
+func validateConfigSetting(backend, key string, value any) error {
+	schema := configSchema(backend)
+	setting, ok := lookupConfigSetting(schema, key)
+	if !ok {
+		if err := otherBrokerSetting(backend, key); err != nil {
+			return err
+		}
+		if suggestion := suggestClosest(key, configSettingKeys(schema)); suggestion != "" {
+			return fmt.Errorf("unknown setting '%s'; did you mean '%s'?", key, suggestion)
+		}
+		return fmt.Errorf("unknown setting '%s'", key)
+	}
+
+	valid := false
+	switch setting.Type {
+	case configInt:
+		switch v := value.(type) {
+		case int, int64:
+			valid = true
+		case float64:
+			valid = v == math.Trunc(v)
+		}
+	case configFloat:
+		switch value.(type) {
+		case int, int64, float64:
+			valid = true
+		}
+	case configBool:
+		_, valid = value.(bool)
+	case configString:
+		var s string
+		if s, valid = value.(string); valid && len(setting.Values) > 0 && !slices.Contains(setting.Values, s) {
+			return fmt.Errorf("%s: invalid value '%s', expected one of: %s", key, s, strings.Join(setting.Values, ", "))
+		}
+	}
+	if !valid {
+		return fmt.Errorf("%s: expected %s, got %v", key, setting.Type, formatConfigValue(value))
+	}
+	return nil
+}
+
+// otherBrokerSetting returns an error if key is not a setting of the
+// backend's broker but of the other one.
+func otherBrokerSetting(backend, key string) error {
+	other := client.BackendLavinMQ
+	if backend == client.BackendLavinMQ {
+		other = client.BackendRabbitMQ
+	}
+	if _, found := lookupConfigSetting(configSchema(backend), key); found {
+		return nil
+	}
+	if _, found := lookupConfigSetting(configSchema(other), key); found {
+		return fmt.Errorf("'%s' is a %s setting, but the instance runs %s", key, brokerName(other), brokerName(backend))
+	}
+	return nil
+}
+
+// validateConfigValues validates every setting in config for the backend and
+// returns all problems, ordered by setting.
+func validateConfigValues(backend string, config map[string]any) []error {
+	var problems []error
+	for _, key := range sortedKeys(config) {
+		if err := validateConfigSetting(backend, key, config[key]); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return problems
+}
+
+// plainConfigValue turns a whole-number float, which every number the API
+// returns decodes to, into an integer, so 134217728 prints as such rather
+// than as 1.34217728e+08. Other values are returned unchanged.
+func plainConfigValue(value any) any {
+	if f, ok := value.(float64); ok && f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f)
+	}
+	return value
+}
+
+// portableConfig returns the set settings of config with plain numbers, for
+// files meant to be read and edited. Unset settings are null in the API's
+// response and are left out.
+func portableConfig(config map[string]any) map[string]any {
+	out := make(map[string]any, len(config))
+	for key, value := range config {
+		if value != nil {
+			out[key] = plainConfigValue(value)
+		}
+	}
+	return out
+}
+
+// formatConfigValue quotes strings so a mistyped "120" is told apart from 120.
+func formatConfigValue(value any) string {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"fmt"
+
+	"cloudamqp-cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+// confirm asks question on stderr and reports whether the user agreed. When
+// stdin is not a terminal nobody can answer, so it fails and points at
+// bypassFlag, the command's flag for skipping the question.
+func confirm(cmd *cobra.Command, question, bypassFlag string) (bool, error) {
+	if !prompt.IsInteractive(cmd.InOrStdin()) {
+		return false, fmt.Errorf("refusing to continue without confirmation. Use %s to proceed non-interactively", bypassFlag)
+	}
+	return prompt.Confirm(cmd.InOrStdin(), cmd.ErrOrStderr(), question)
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"cloudamqp-cli/client"
+	"cloudamqp-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// Results of a doctor check. Only FAIL makes the command exit non-zero.
+const (
+	checkPass = "PASS"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+	checkSkip = "SKIP"
+)
+
+// maxClockSkew is how far the local clock may be off the API server's
+// before doctor warns about it.
+const maxClockSkew = 30 * time.Second
+
+// doctorCheck is the result of one doctor check, with a hint on how to fix
+// anything but a pass.
+type doctorCheck struct {
+	name, status, detail, hint string
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the CLI setup and connection to the API",
+	Long: `Runs the checks support usually asks for and prints each with PASS, WARN,
+FAIL or SKIP and, when something is wrong, a hint on how to fix it:
+
+  config file   ~/.cloudamqprc is readable and only by you
+  API key       a key is set (CLOUDAMQP_APIKEY or the config file) and
+                the API accepts it
+  API           the API at CLOUDAMQP_URL can be reached
+  clock         the local clock is within 30s of the API server's
+  version       this is the latest CLI release
+
+Unlike other commands, doctor never prompts for a missing API key. It exits
+non-zero when any check fails; warnings don't affect the exit code.`,
+	Example: `  cloudamqp doctor
+  cloudamqp doctor -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, source := findAPIKey()
+		checks := []doctorCheck{checkConfigFile(source)}
+
+		c := newAPIClient(key)
+		keyCheck, apiCheck := checkAPI(c, key, source)
+		checks = append(checks, keyCheck, apiCheck)
+		if keyCheck.status == checkPass {
+			checks = append(checks, checkClock(c))
+		} else {
+			checks = append(checks, doctorCheck{name: "clock", status: checkSkip, detail: "needs a working API key"})
+		}
+		checks = append(checks, checkVersion(c))
+
+		p, err := getListPrinter(cmd)
+		if err != nil {
+			return err
+		}
+		p.StyleColumn("STATUS", checkStatusStyle)
+		rows := make([][]string, len(checks))
+		failed := 0
+		for i, check := range checks {
+			rows[i] = []string{check.name, check.status, check.detail, check.hint}
+			if check.status == checkFail {
+				failed++
+			}
+		}
+		p.PrintRecords([]string{"CHECK", "STATUS", "DETAIL", "HINT"}, rows)
+
+		if failed > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+		}
+		return nil
+	},
+}
+
+// findAPIKey returns the API key getAPIKey would use and where it comes
+// from, without prompting. Both are empty if there is none.
+func findAPIKey() (key, source string) {
+	if key := os.Getenv("CLOUDAMQP_APIKEY"); key != "" {
+		return key, "CLOUDAMQP_APIKEY"
+	}
+	if key, err := loadAPIKey(); err == nil && key != "" {
+		path, _ := getConfigPath()
+		return key, path
+	}
+	return "", ""
+}
+
+func checkConfigFile(keySource string) doctorCheck {
+	check := doctorCheck{name: "config file"}
+	path, err := getConfigPath()
+	if err != nil {
+		check.status, check.detail = checkWarn, fmt.Sprintf("no home directory: %v", err)
+		check.hint = "Set CLOUDAMQP_APIKEY instead of using a config file"
+		return check
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && keySource != "":
+		check.status, check.detail = checkPass, fmt.Sprintf("%s not present, the key comes from %s", path, keySource)
+		return check
+	case errors.Is(err, os.ErrNotExist):
+		check.status, check.detail = checkWarn, fmt.Sprintf("%s not present", path)
+		check.hint = "Run any command, such as cloudamqp ping, to be prompted for the key and save it"
+		return check
+	case err != nil:
+		check.status, check.detail = checkFail, fmt.Sprintf("cannot read %s: %v", path, err)
+		check.hint = "Check the permissions of the file and its directory"
+		return check
+	}
+
+	if _, err := os.ReadFile(path); err != nil {
+		check.status, check.detail = checkFail, fmt.Sprintf("cannot read %s: %v", path, err)
+		check.hint = fmt.Sprintf("Make the file readable: chmod 600 %s", path)
+		return check
+	}
+	if info.Mode().Perm()&0o077 != 0 {
+		check.status, check.detail = checkWarn, fmt.Sprintf("%s is readable by other users (mode %04o)", path, info.Mode().Perm())
+		check.hint = fmt.Sprintf("Restrict it to yourself: chmod 600 %s", path)
+		return check
+	}
+	check.status, check.detail = checkPass, path
+	return check
+}
+
+// checkAPI checks the API key and whether the API can be reached with one
+// request; a rejected key still shows the API is reachable.
+func checkAPI(c client.ClientAPI, key, source string) (keyCheck, apiCheck doctorCheck) {
+	keyCheck = doctorCheck{name: "API key"}
+	apiCheck = doctorCheck{name: "API", detail: client.APIURL()}
+
+	err := pingAPI(c)
+	var apiErr *client.APIError
+	switch {
+	case err == nil:
+		apiCheck.status = checkPass
+	case errors.As(err, &apiErr):
+		// The API answered, so it is reachable
+		apiCheck.status = checkPass
+		if apiErr.StatusCode != http.StatusUnauthorized && apiErr.StatusCode != http.StatusForbidden {
+			apiCheck.status = checkFail
+			apiCheck.detail = pingError(client.APIURL(), err).Error()
+			apiCheck.hint = "Check https://status.cloudamqp.com and try again later"
+		}
+	default:
+		apiCheck.status, apiCheck.detail = checkFail, pingError(client.APIURL(), err).Error()
+		apiCheck.hint = "Check your network, proxy (--proxy, HTTPS_PROXY) and CLOUDAMQP_URL"
+	}
+
+	switch {
+	case key == "":
+		keyCheck.status, keyCheck.detail = checkFail, "no API key found"
+		keyCheck.hint = "Set CLOUDAMQP_APIKEY or save the key in ~/.cloudamqprc"
+	case err == nil:
+		keyCheck.status = checkPass
+		keyCheck.detail = "from " + source
+	case apiCheck.status == checkPass:
+		keyCheck.status = checkFail
+		keyCheck.detail = fmt.Sprintf("the key from %s was rejected (HTTP %d)", source, apiErr.StatusCode)
+		keyCheck.hint = "Create a new key under API access in the CloudAMQP console"
+	default:
+		keyCheck.status, keyCheck.detail = checkSkip, "the API couldn't be asked"
+	}
+	return keyCheck, apiCheck
+}
+
+func checkClock(c client.ClientAPI) doctorCheck {
+	check := doctorCheck{name: "clock"}
+	serverTime, err := c.ServerTime()
+	if err != nil {
+		check.status, check.detail = checkWarn, fmt.Sprintf("couldn't get the server time: %v", err)
+		return check
+	}
+
+	skew := time.Since(serverTime).Round(time.Second)
+	if skew.Abs() > maxClockSkew {
+		check.status = checkWarn
+		check.detail = fmt.Sprintf("local clock is %s off the API server", skew.Abs())
+		check.hint = "Enable time synchronization (NTP); --since, --until and audit times depend on it"
+		return check
+	}
+	check.status, check.detail = checkPass, fmt.Sprintf("within %s of the API server", maxClockSkew)
+	return check
+}
+
+func checkVersion(c client.ClientAPI) doctorCheck {
+	check := doctorCheck{name: "version"}
+	if Version == "dev" {
+		check.status, check.detail = checkSkip, "development build"
+		return check
+	}
+
+	latest, err := c.LatestRelease()
+	if err != nil {
+		check.status, check.detail = checkWarn, fmt.Sprintf("couldn't look up the latest release: %v", err)
+		return check
+	}
+
+	current := strings.TrimPrefix(Version, "v")
+	if compareVersions(strings.TrimPrefix(latest, "v"), current) > 0 {
+		check.status = checkWarn
+		check.detail = fmt.Sprintf("%s is available (running %s)", latest, Version)
+		check.hint = "Download it from https://github.com/cloudamqp/cli/releases/latest"
+		return check
+	}
+	check.status, check.detail = checkPass, fmt.Sprintf("%s is the latest release", Version)
+	return check
+}
+
+// checkStatusStyle colors doctor results: PASS green, WARN yellow and FAIL
+// red.
+func checkStatusStyle(value string) string {
+	switch value {
+	case checkPass:
+		return ui.Green(value)
+	case checkWarn:
+		return ui.Yellow(value)
+	case checkFail:
+		return ui.Red(value)
+	}
+	return value
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type doctorClient struct {
+	fakeClient
+	apiErr     error
+	serverTime time.Time
+	latest     string
+}
+
+func (f *doctorClient) ListInstancesWithOptions(client.ListOptions) ([]client.Instance, error) {
+	return nil, f.apiErr
+}
+
+func (f *doctorClient) ServerTime() (time.Time, error) { return f.serverTime, nil }
+
+func (f *doctorClient) LatestRelease() (string, error) { return f.latest, nil }
+
+// runDoctor runs doctor with -o json and CLOUDAMQP_APIKEY set to apiKey,
+// and returns the status of each check and the command's error.
+func runDoctor(t *testing.T, fake *doctorClient, apiKey string) (map[string]string, error) {
+	t.Helper()
+	useFakeClient(t, fake)
+	t.Setenv("CLOUDAMQP_APIKEY", apiKey)
+
+	cmd := doctorCmd
+	cmd.InheritedFlags()
+	rootCmd.PersistentFlags().Set("output", "json")
+	defer rootCmd.PersistentFlags().Set("output", "table")
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	defer cmd.SetOut(nil)
+	err := cmd.RunE(cmd, []string{})
+
+	var records []map[string]string
+	require.NoError(t, json.Unmarshal(out.Bytes(), &records))
+	statuses := map[string]string{}
+	for _, record := range records {
+		statuses[record["check"]] = record["status"]
+	}
+	return statuses, err
+}
+
+func TestDoctorCmd_AllPass(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	origVersion := Version
+	Version = "v1.2.0"
+	defer func() { Version = origVersion }()
+
+	statuses, err := runDoctor(t, &doctorClient{serverTime: time.Now(), latest: "v1.2.0"}, "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"config file": checkPass,
+		"API key":     checkPass,
+		"API":         checkPass,
+		"clock":       checkPass,
+		"version":     checkPass,
+	}, statuses)
+}
+
+func TestDoctorCmd_Warnings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".cloudamqprc"), []byte("test-key"), 0o644))
+	origVersion := Version
+	Version = "v1.2.0"
+	defer func() { Version = origVersion }()
+
+	statuses, err := runDoctor(t, &doctorClient{serverTime: time.Now().Add(-5 * time.Minute), latest: "v1.10.0"}, "")
+	require.NoError(t, err, "warnings don't fail the command")
+	assert.Equal(t, checkWarn, statuses["config file"])
+	assert.Equal(t, checkPass, statuses["API key"], "the key is read from the config file")
+	assert.Equal(t, checkWarn, statuses["clock"])
+	assert.Equal(t, checkWarn, statuses["version"])
+}
+
+func TestDoctorCmd_RejectedKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	statuses, err := runDoctor(t, &doctorClient{apiErr: &client.APIError{StatusCode: 401, Message: "unauthorized"}}, "bad-key")
+	assert.EqualError(t, err, "1 of 5 checks failed")
+	assert.Equal(t, checkFail, statuses["API key"])
+	assert.Equal(t, checkPass, statuses["API"], "a rejected key shows the API is reachable")
+	assert.Equal(t, checkSkip, statuses["clock"])
+}
+
+func TestDoctorCmd_Unreachable(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	refused := &url.Error{Op: "Get", URL: "https://customer.cloudamqp.com/api/instances", Err: errors.New("connection refused")}
+	statuses, err := runDoctor(t, &doctorClient{apiErr: refused}, "test-key")
+	assert.EqualError(t, err, "1 of 5 checks failed")
+	assert.Equal(t, checkFail, statuses["API"])
+	assert.Equal(t, checkSkip, statuses["API key"])
+}
+
+func TestDoctorCmd_NoKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	statuses, err := runDoctor(t, &doctorClient{apiErr: &client.APIError{StatusCode: 401}}, "")
+	assert.EqualError(t, err, "1 of 5 checks failed")
+	assert.Equal(t, checkWarn, statuses["config file"])
+	assert.Equal(t, checkFail, statuses["API key"])
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// addDryRunFlag registers the --dry-run flag on a mutating command.
+func addDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("dry-run", false, "Print the request that would be sent without executing it")
+}
+
+// isDryRun reports whether --dry-run was passed to the command.
+func isDryRun(cmd *cobra.Command) bool {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	return dryRun
+}
+
+// printDryRun renders the method, path and body of a request that would
+// have been sent to the API. Form fields are shown URL-encoded, as they
+// are sent; other bodies as JSON. Credentials are redacted the same way
+// as in --trace files, since dry-run output is often pasted into logs.
+func printDryRun(cmd *cobra.Command, method, path string, body any) error {
+	p, err := getPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
+	bodyStr := ""
+	if form, ok := body.(url.Values); ok {
+		bodyStr = string(client.SanitizeForm([]byte(form.Encode())))
+	} else if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to format request body: %v", err)
+		}
+		bodyStr = string(client.SanitizeBody(data))
+	}
+
+	p.PrintRecord([]string{"METHOD", "PATH", "BODY"}, []string{method, path, bodyStr})
+	return nil
+}
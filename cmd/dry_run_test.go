@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureStdout returns everything written to os.Stdout while fn runs.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	fn()
+	w.Close()
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestInstanceUpdateCmd_DryRun(t *testing.T) {
+	// No API key or server is configured; a dry run must not need either
+	t.Setenv("CLOUDAMQP_APIKEY", "")
+	t.Setenv("HOME", t.TempDir())
+
+	cmd := instanceUpdateCmd
+	cmd.InheritedFlags() // merge --output from root, as Execute would
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("plan", "rabbit-1")
+	cmd.Flags().Set("dry-run", "true")
+	rootCmd.PersistentFlags().Set("output", "json")
+	defer func() {
+		resetFlags(cmd)
+		rootCmd.PersistentFlags().Set("output", "table")
+	}()
+
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+
+	var record map[string]string
+	require.NoError(t, json.Unmarshal([]byte(out), &record))
+	assert.Equal(t, "PUT", record["method"])
+	assert.Equal(t, "/instances/1234", record["path"])
+	assert.Equal(t, "plan=rabbit-1", record["body"], "the update is sent as form fields")
+}
+
+func TestPrintDryRun_Body(t *testing.T) {
+	cmd := instanceCreateCmd
+	cmd.InheritedFlags()
+	rootCmd.PersistentFlags().Set("output", "json")
+	defer rootCmd.PersistentFlags().Set("output", "table")
+
+	body := func(t *testing.T, req *client.InstanceCreateRequest) string {
+		t.Helper()
+		out := captureStdout(t, func() {
+			require.NoError(t, printDryRun(cmd, "POST", "/instances", req.Body()))
+		})
+		var record map[string]string
+		require.NoError(t, json.Unmarshal([]byte(out), &record))
+		return record["body"]
+	}
+
+	req := &client.InstanceCreateRequest{Name: "orders", Plan: "bunny-1", Region: "amazon-web-services::us-east-1"}
+	assert.Equal(t, "name=orders&plan=bunny-1&region=amazon-web-services%3A%3Aus-east-1", body(t, req), "form fields are shown as sent")
+
+	req.CopySettings = &client.CopySettings{}
+	assert.True(t, json.Valid([]byte(body(t, req))), "with copy settings the request is sent as JSON")
+}
+
+func TestInstanceUpdateCmd_OmitsUnsetFields(t *testing.T) {
+	cmd := instanceUpdateCmd
+	defer resetFlags(cmd)
+
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("name", "renamed")
+
+	req, err := buildInstanceUpdateRequest(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", req.Name)
+	assert.Empty(t, req.Plan, "an unset --plan must not be sent")
+	assert.Nil(t, req.Tags)
+
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"renamed"}`, string(body))
+}
+
+func TestInstanceUpdateCmd_RejectsEmptyValues(t *testing.T) {
+	cmd := instanceUpdateCmd
+	defer resetFlags(cmd)
+
+	cmd.Flags().Set("plan", " ")
+	_, err := buildInstanceUpdateRequest(cmd)
+	assert.EqualError(t, err, "--plan must not be empty")
+
+	resetFlags(cmd)
+	_, err = buildInstanceUpdateRequest(cmd)
+	assert.EqualError(t, err, "at least one field must be specified for update")
+
+	cmd.Flags().Set("config-file", "")
+	_, err = buildInstanceUpdateRequest(cmd)
+	assert.EqualError(t, err, "at least one field must be specified for update", "an empty --config-file is not a change")
+}
+
+// resetFlags restores every local flag of cmd to its default and unset state.
+func resetFlags(cmd *cobra.Command) {
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			sv.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
+
+func TestMutatingCommands_HaveDryRunFlag(t *testing.T) {
+	for _, cmd := range []*cobra.Command{
+		instanceCreateCmd, instanceUpdateCmd, instanceDeleteCmd,
+		instanceResizeCmd, instanceConfigSetCmd,
+	} {
+		assert.NotNil(t, cmd.Flag("dry-run"), "%s should have --dry-run", cmd.Name())
+	}
+}
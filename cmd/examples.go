@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"cloudamqp-cli/internal/examples"
+	"github.com/spf13/cobra"
+)
+
+var examplesCmd = &cobra.Command{
+	Use:   "examples [command]",
+	Short: "Show example invocations of a command",
+	Long: `Prints the examples of a command, or of all commands below a command
+group, such as "instance config". Without a command, the examples of every
+command are printed.
+
+The examples are the same as in each command's --help.`,
+	Example: `  cloudamqp examples instance create
+  cloudamqp examples instance config
+  cloudamqp examples`,
+	ValidArgsFunction: completeCommandPath,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registry, err := commandExamples(rootCmd)
+		if err != nil {
+			return err
+		}
+
+		path := ""
+		if len(args) > 0 {
+			target, rest, err := rootCmd.Find(args)
+			if err != nil || len(rest) > 0 || target == rootCmd {
+				return fmt.Errorf("unknown command %q", strings.Join(args, " "))
+			}
+			path = commandPath(target)
+		}
+
+		paths := registry.Under(path)
+		if len(paths) == 0 {
+			return fmt.Errorf("no examples for %q", path)
+		}
+
+		out := cmd.OutOrStdout()
+		for i, p := range paths {
+			if i > 0 {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintf(out, "%s:\n", p)
+			for _, spec := range registry.Lookup(p) {
+				if spec.Comment != "" {
+					fmt.Fprintf(out, "  # %s\n", spec.Comment)
+				}
+				fmt.Fprintf(out, "  %s\n", spec.Line)
+			}
+		}
+		return nil
+	},
+}
+
+// commandPath is the path of cmd below the root, e.g. "instance config set".
+func commandPath(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+// commandExamples parses the Example of every command below root into a
+// registry keyed by command path.
+func commandExamples(root *cobra.Command) (*examples.Registry, error) {
+	registry := examples.NewRegistry()
+	var walk func(cmd *cobra.Command) error
+	walk = func(cmd *cobra.Command) error {
+		if cmd.Example != "" {
+			specs, err := examples.Parse(root.Name(), cmd.Example)
+			if err != nil {
+				return fmt.Errorf("%s: %w", commandPath(cmd), err)
+			}
+			registry.Add(commandPath(cmd), specs...)
+		}
+		for _, sub := range cmd.Commands() {
+			if err := walk(sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return registry, walk(root)
+}
+
+// completeCommandPath completes the next word of a command path.
+func completeCommandPath(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	parent := rootCmd
+	if len(args) > 0 {
+		target, rest, err := rootCmd.Find(args)
+		if err != nil || len(rest) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		parent = target
+	}
+	var names []string
+	for _, sub := range parent.Commands() {
+		if sub.IsAvailableCommand() && strings.HasPrefix(sub.Name(), toComplete) {
+			names = append(names, sub.Name()+"\t"+sub.Short)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
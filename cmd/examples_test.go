@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommandExamples_AreValid checks that every runnable leaf command has
+// examples, and that each example runs that command with flags it has.
+func TestCommandExamples_AreValid(t *testing.T) {
+	registry, err := commandExamples(rootCmd)
+	require.NoError(t, err)
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+		if cmd.HasSubCommands() || !cmd.Runnable() || cmd.Hidden || cmd.Name() == "help" {
+			return
+		}
+
+		path := commandPath(cmd)
+		specs := registry.Lookup(path)
+		if !assert.NotEmpty(t, specs, "%s has no Example", path) {
+			return
+		}
+		cmd.InheritedFlags() // merge persistent flags, as Execute would
+		for _, spec := range specs {
+			target, rest, err := rootCmd.Find(spec.Args)
+			if !assert.NoError(t, err, "%s: %s", path, spec.Line) {
+				continue
+			}
+			assert.Equal(t, path, commandPath(target), "example of %s runs another command: %s", path, spec.Line)
+			for _, name := range exampleFlags(rest) {
+				assert.True(t, hasFlag(target, name), "%s: unknown flag %s in %s", path, name, spec.Line)
+			}
+		}
+	}
+	walk(rootCmd)
+}
+
+// exampleFlags returns the flag names used in args: "id" for --id 1234 or
+// --id=1234 and "o" for -o json.
+func exampleFlags(args []string) []string {
+	var names []string
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		names = append(names, name)
+	}
+	return names
+}
+
+func hasFlag(cmd *cobra.Command, name string) bool {
+	flags := []*pflag.FlagSet{cmd.Flags(), cmd.InheritedFlags()}
+	for _, fs := range flags {
+		if fs.Lookup(name) != nil || (len(name) == 1 && fs.ShorthandLookup(name) != nil) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestExamplesCmd(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		examplesCmd.SetOut(&out)
+		defer examplesCmd.SetOut(nil)
+		err := examplesCmd.RunE(examplesCmd, args)
+		return out.String(), err
+	}
+
+	out, err := run("instance", "config")
+	require.NoError(t, err)
+	assert.Contains(t, out, "instance config set:\n  cloudamqp instance config set --id 1234 rabbit.heartbeat 120\n")
+	assert.Contains(t, out, "instance config get:")
+	assert.NotContains(t, out, "instance create:")
+
+	out, err = run("whoami")
+	require.NoError(t, err)
+	assert.Equal(t, "whoami:\n  cloudamqp whoami\n  cloudamqp whoami -o json\n", out)
+
+	_, err = run("instance", "frobnicate")
+	assert.EqualError(t, err, `unknown command "instance frobnicate"`)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+)
+
+// Exit codes other than 0 (success) and 1 (any other error).
+const (
+	// exitNotFound means the instance or resource asked about doesn't exist.
+	exitNotFound = 4
+	// exitUpgradeAvailable means nodes versions --check found a newer
+	// broker version.
+	exitUpgradeAvailable = 10
+	// exitInterrupted means the command was stopped with Ctrl-C or SIGTERM,
+	// following the shell convention of 128 + SIGINT.
+	exitInterrupted = 130
+)
+
+// exitError makes the process exit with code instead of 1.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
+
+// ExitCode returns the process exit code for an error returned by Execute.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	// A request abandoned on Ctrl-C, such as one waiting for --rate-limit,
+	// fails with the cancelled context's error
+	if errors.Is(err, errInterrupted) || errors.Is(err, context.Canceled) {
+		return exitInterrupted
+	}
+	return 1
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// addIDFileFlag registers --id-file on a command that can work on several
+// instances at once.
+func addIDFileFlag(cmd *cobra.Command) {
+	cmd.Flags().String("id-file", "", "File with instance IDs, one per line (blank lines and # comments are ignored)")
+	cmd.MarkFlagFilename("id-file")
+}
+
+// idFilePath returns the --id-file of the command, or "" when not given.
+func idFilePath(cmd *cobra.Command) string {
+	path, _ := cmd.Flags().GetString("id-file")
+	return path
+}
+
+// readIDFile reads the instance IDs in the file at path.
+func readIDFile(path string) ([]int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --id-file: %w", err)
+	}
+	defer f.Close()
+	return parseIDs(f, path)
+}
+
+// parseIDs reads one instance ID per line. Blank lines and everything after
+// a # are ignored. Errors name the file and line of the bad entry.
+func parseIDs(r io.Reader, name string) ([]int, error) {
+	var ids []int
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		id, err := strconv.Atoi(text)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%s:%d: invalid instance ID %q", name, line, text)
+		}
+		ids = append(ids, id)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return ids, nil
+}
+
+// batchInstances returns the instances a batch command works on: the one
+// given to --id, those listed in --id-file and those that have all tags.
+// The sets are combined in that order and each instance appears once.
+func batchInstances(cmd *cobra.Command, c client.ClientAPI, idFlag string, tags []string) ([]client.Instance, error) {
+	var fileIDs []int
+	if path := idFilePath(cmd); path != "" {
+		var err error
+		if fileIDs, err = readIDFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	instances, err := c.ListInstances()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+	byID := make(map[int]client.Instance, len(instances))
+	for _, instance := range instances {
+		byID[instance.ID] = instance
+	}
+
+	var batch []client.Instance
+	seen := map[int]bool{}
+	add := func(instance client.Instance) {
+		if !seen[instance.ID] {
+			seen[instance.ID] = true
+			batch = append(batch, instance)
+		}
+	}
+
+	if idFlag = strings.TrimSpace(idFlag); idFlag != "" {
+		id, err := strconv.Atoi(idFlag)
+		if err != nil {
+			if id, err = matchInstanceName(instances, idFlag); err != nil {
+				return nil, err
+			}
+		}
+		instance, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("instance %d not found", id)
+		}
+		add(instance)
+	}
+	for _, id := range fileIDs {
+		instance, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("instance %d from %s not found", id, idFilePath(cmd))
+		}
+		add(instance)
+	}
+	if len(tags) > 0 {
+		matches := instancesWithTags(instances, tags)
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no instances tagged %s", strings.Join(tags, ", "))
+		}
+		for _, instance := range matches {
+			add(instance)
+		}
+	}
+
+	if len(batch) == 0 {
+		return nil, fmt.Errorf("no instance IDs in %s", idFilePath(cmd))
+	}
+	return batch, nil
+}
+
+// instanceNames lists instances as "name (id)" for confirmations.
+func instanceNames(instances []client.Instance) string {
+	names := make([]string, len(instances))
+	for i, instance := range instances {
+		names[i] = fmt.Sprintf("%s (%d)", instance.Name, instance.ID)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []int
+		wantErr string
+	}{
+		{name: "one per line", input: "1\n2\n3\n", want: []int{1, 2, 3}},
+		{name: "no trailing newline", input: "1\n2", want: []int{1, 2}},
+		{name: "blank lines and comments", input: "# prod\n\n1\n  \n# staging\n2\n", want: []int{1, 2}},
+		{name: "trailing comment", input: "1 # orders\n2\t# billing\n", want: []int{1, 2}},
+		{name: "surrounding whitespace and CRLF", input: "  1  \r\n2\r\n", want: []int{1, 2}},
+		{name: "byte order mark", input: "\ufeff1\n2\n", want: []int{1, 2}},
+		{name: "empty", input: "", want: nil},
+		{name: "only comments", input: "# nothing here\n", want: nil},
+		{name: "not a number", input: "1\n\norders\n", wantErr: "ids.txt:3: invalid instance ID \"orders\""},
+		{name: "several per line", input: "1 2\n", wantErr: "ids.txt:1: invalid instance ID \"1 2\""},
+		{name: "negative", input: "-5\n", wantErr: "ids.txt:1: invalid instance ID \"-5\""},
+		{name: "zero", input: "0\n", wantErr: "ids.txt:1: invalid instance ID \"0\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := parseIDs(strings.NewReader(tt.input), "ids.txt")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func writeIDFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ids.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestBatchInstances(t *testing.T) {
+	fake := &fakeClient{instances: map[int]*client.Instance{
+		1: {ID: 1, Name: "orders", Tags: []string{"prod"}},
+		2: {ID: 2, Name: "billing", Tags: []string{"prod"}},
+		3: {ID: 3, Name: "staging"},
+	}}
+	cmd := instanceConfigSetCmd
+	defer resetFlags(cmd)
+
+	t.Run("combines --id, --id-file and --tag without duplicates", func(t *testing.T) {
+		require.NoError(t, cmd.Flags().Set("id-file", writeIDFile(t, "3\n1\n")))
+
+		instances, err := batchInstances(cmd, fake, "billing", []string{"prod"})
+		require.NoError(t, err)
+		var ids []int
+		for _, instance := range instances {
+			ids = append(ids, instance.ID)
+		}
+		assert.Equal(t, []int{2, 3, 1}, ids)
+	})
+
+	t.Run("unknown ID", func(t *testing.T) {
+		path := writeIDFile(t, "1\n42\n")
+		require.NoError(t, cmd.Flags().Set("id-file", path))
+
+		_, err := batchInstances(cmd, fake, "", nil)
+		assert.EqualError(t, err, "instance 42 from "+path+" not found")
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		path := writeIDFile(t, "# none yet\n")
+		require.NoError(t, cmd.Flags().Set("id-file", path))
+
+		_, err := batchInstances(cmd, fake, "", nil)
+		assert.EqualError(t, err, "no instance IDs in "+path)
+	})
+}
@@ -29,6 +29,7 @@ Skills teach Claude how to use the cloudamqp CLI. After installation,
 Claude Code will automatically discover and use them.
 
 Skills are installed to: ~/.claude/skills/cloudamqp-cli/`,
+	Example: `  cloudamqp install skills`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		home, err := os.UserHomeDir()
 		if err != nil {
@@ -57,7 +58,7 @@ Skills are installed to: ~/.claude/skills/cloudamqp-cli/`,
 		if err != nil {
 			return fmt.Errorf("failed to install skills: %w", err)
 		}
-		fmt.Printf("Skills installed to %s\n", dest)
+		printStatus(cmd, "Skills installed to %s", dest)
 		return nil
 	},
 }
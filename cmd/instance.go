@@ -5,21 +5,36 @@ import (
 )
 
 var instanceCmd = &cobra.Command{
-	Use:   "instance",
-	Short: "Manage CloudAMQP instances",
-	Long:  `Create, list, update, and delete CloudAMQP instances.`,
+	Use:     "instance",
+	Aliases: []string{"i", "inst"},
+	Short:   "Manage CloudAMQP instances",
+	Long:    `Create, list, update, and delete CloudAMQP instances.`,
 }
 
 func init() {
 	instanceCmd.AddCommand(instanceCreateCmd)
 	instanceCmd.AddCommand(instanceListCmd)
+	instanceCmd.AddCommand(instanceSearchCmd)
 	instanceCmd.AddCommand(instanceGetCmd)
+	instanceCmd.AddCommand(instanceExistsCmd)
+	instanceCmd.AddCommand(instanceURLCmd)
 	instanceCmd.AddCommand(instanceUpdateCmd)
+	instanceCmd.AddCommand(instanceRenameCmd)
 	instanceCmd.AddCommand(instanceDeleteCmd)
 	instanceCmd.AddCommand(instanceResizeCmd)
 	instanceCmd.AddCommand(instanceConfigCmd)
+	instanceCmd.AddCommand(instanceEventsCmd)
 	instanceCmd.AddCommand(instanceNodesCmd)
 	instanceCmd.AddCommand(instancePluginsCmd)
+	instanceCmd.AddCommand(instanceFirewallCmd)
+	instanceCmd.AddCommand(instanceAlarmsCmd)
+	instanceCmd.AddCommand(instanceNotificationsCmd)
+	instanceCmd.AddCommand(instanceIntegrationsCmd)
+	instanceCmd.AddCommand(instanceLogIntegrationsCmd)
+	instanceCmd.AddCommand(instanceVPCCmd)
+	instanceCmd.AddCommand(instanceTagsCmd)
+	instanceCmd.AddCommand(instanceExportCmd)
+	instanceCmd.AddCommand(instanceApplyCmd)
 	// Action commands (flattened from actions subcommand)
 	instanceCmd.AddCommand(restartRabbitMQCmd)
 	instanceCmd.AddCommand(restartClusterCmd)
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"cloudamqp-cli/client"
 	"github.com/spf13/cobra"
@@ -18,75 +19,180 @@ var instanceAccountCmd = &cobra.Command{
 	},
 }
 
+var (
+	rotatePasswordWait        bool
+	rotatePasswordWaitTimeout string
+	rotatePasswordShow        bool
+)
+
 var rotatePasswordCmd = &cobra.Command{
-	Use:     "rotate-password --id <instance_id>",
-	Short:   "Rotate password",
-	Long:    `Initiate rotation of the user password on your instance.`,
-	Example: `  cloudamqp instance account rotate-password --id 1234`,
+	Use:   "rotate-password --id <instance_id>",
+	Short: "Rotate password",
+	Long: `Initiate rotation of the user password on your instance.
+
+With --wait the command blocks until the instance is ready with the new
+password and prints the new connection URL, so applications can be updated.
+The password in the URL is masked unless --show is given or an output format
+is requested explicitly with --output.`,
+	Example: `  cloudamqp instance account rotate-password --id 1234
+  cloudamqp instance account rotate-password --id 1234 --wait
+  cloudamqp instance account rotate-password --id 1234 --wait -o json | jq -r .url`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		var timeout time.Duration
+		if rotatePasswordWait {
+			var err error
+			timeout, err = time.ParseDuration(rotatePasswordWaitTimeout)
+			if err != nil {
+				return fmt.Errorf("invalid wait-timeout value: %v", err)
+			}
 		}
 
-		var err error
 		apiKey, err := getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
 		}
 
-		c := client.New(apiKey, Version)
+		c := newAPIClient(apiKey)
+
+		// Remember the current password so --wait can tell when it changed
+		var oldPassword string
+		if rotatePasswordWait {
+			current, err := c.GetPasswordRotationStatus(idFlag)
+			if err != nil {
+				return fmt.Errorf("failed to get instance: %w", err)
+			}
+			oldPassword = current.Password
+		}
 
-		err = c.RotatePassword(idFlag)
+		rotation, err := c.RotatePassword(idFlag)
 		if err != nil {
-			fmt.Printf("Error rotating password: %v\n", err)
-			return err
+			return fmt.Errorf("failed to rotate password: %w", err)
 		}
 
-		fmt.Println("Password rotation initiated successfully.")
+		if rotatePasswordWait && rotation.Status != client.PasswordRotationComplete {
+			rotation, err = waitForPasswordRotation(commandContext(cmd), c, idFlag, oldPassword, timeout)
+			if err != nil {
+				return fmt.Errorf("wait failed: %w", err)
+			}
+		}
+
+		if rotation.URL == "" {
+			printStatus(cmd, "Password rotation initiated successfully.")
+			printStatus(cmd, "Use --wait to wait for the new password and print the connection URL.")
+			return nil
+		}
+
+		printStatus(cmd, "Password rotated successfully. Update your applications with the new connection URL.")
+		connURL := rotation.URL
+		if !rotatePasswordShow && !cmd.Flags().Changed("output") {
+			connURL = maskPassword(connURL)
+		}
+
+		p, err := getPrinter(cmd)
+		if err != nil {
+			return err
+		}
+		p.PrintRecord([]string{"Status", "URL"}, []string{rotation.Status, connURL})
 		return nil
 	},
 }
 
+var (
+	rotateAPIKeyYes  bool
+	rotateAPIKeyShow bool
+)
+
 var rotateInstanceAPIKeyCmd = &cobra.Command{
-	Use:     "rotate-apikey --id <instance_id>",
-	Short:   "Rotate Instance API key",
-	Long:    `Rotate the Instance API key.`,
-	Example: `  cloudamqp instance account rotate-apikey --id 1234`,
+	Use:   "rotate-apikey --id <instance_id>",
+	Short: "Rotate Instance API key",
+	Long: `Rotate the Instance API key.
+
+The old key stops working as soon as it is rotated, so applications that use
+it fail until they are updated. The command asks for confirmation first;
+pass --yes to rotate without asking.
+
+The new key is printed so configs can be updated right away. It is masked
+unless --show is given or an output format is requested explicitly with
+--output.`,
+	Example: `  cloudamqp instance account rotate-apikey --id 1234
+  cloudamqp instance account rotate-apikey --id 1234 --yes --show
+  cloudamqp instance account rotate-apikey --id 1234 --yes -o json | jq -r .apikey`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		if !rotateAPIKeyYes {
+			logWarn("The current API key of instance %s stops working immediately; applications using it must be updated.", idFlag)
+			ok, err := confirm(cmd, "Rotate the instance API key?", "--yes")
+			if err != nil {
+				return err
+			}
+			if !ok {
+				printStatus(cmd, "Rotation cancelled.")
+				return nil
+			}
 		}
 
-		var err error
 		apiKey, err := getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
 		}
 
-		c := client.New(apiKey, Version)
+		c := newAPIClient(apiKey)
 
-		err = c.RotateInstanceAPIKey(idFlag)
+		newKey, err := c.RotateInstanceAPIKey(idFlag)
 		if err != nil {
-			fmt.Printf("Error rotating instance API key: %v\n", err)
-			return err
+			return fmt.Errorf("failed to rotate instance API key: %w", err)
 		}
 
-		fmt.Println("Instance API key rotation initiated successfully.")
-		fmt.Printf("Warning: The local config for instance %s will need to be updated.\n", idFlag)
-		fmt.Printf("Run 'cloudamqp instance get --id %s' to retrieve and save the new API key.\n", idFlag)
+		if newKey == "" {
+			printStatus(cmd, "Instance API key rotation initiated successfully.")
+			logWarn("The local config for instance %s will need to be updated.", idFlag)
+			printStatus(cmd, "Run 'cloudamqp instance get --id %s' to retrieve and save the new API key.", idFlag)
+			return nil
+		}
+
+		printStatus(cmd, "Instance API key rotated successfully. Update your applications with the new key.")
+		if !rotateAPIKeyShow && !cmd.Flags().Changed("output") {
+			newKey = maskAPIKey(newKey)
+		}
+
+		p, err := getPrinter(cmd)
+		if err != nil {
+			return err
+		}
+		p.PrintRecord([]string{"Apikey"}, []string{newKey})
 		return nil
 	},
 }
 
+// maskAPIKey hides all but the first four characters of an API key.
+func maskAPIKey(key string) string {
+	if len(key) <= 8 {
+		return "****"
+	}
+	return key[:4] + "****"
+}
+
 func init() {
 	// Add --id flag to both account commands
-	rotatePasswordCmd.Flags().StringP("id", "", "", "Instance ID (required)")
+	rotatePasswordCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
 	rotatePasswordCmd.MarkFlagRequired("id")
+	rotatePasswordCmd.Flags().BoolVar(&rotatePasswordWait, "wait", false, "Wait for the rotation to complete and print the new connection URL")
+	rotatePasswordCmd.Flags().StringVar(&rotatePasswordWaitTimeout, "wait-timeout", "15m", "Timeout for waiting (e.g., 15m, 30m)")
+	rotatePasswordCmd.Flags().BoolVar(&rotatePasswordShow, "show", false, "Show the new password instead of masking it")
 
-	rotateInstanceAPIKeyCmd.Flags().StringP("id", "", "", "Instance ID (required)")
+	rotateInstanceAPIKeyCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
 	rotateInstanceAPIKeyCmd.MarkFlagRequired("id")
+	rotateInstanceAPIKeyCmd.Flags().BoolVar(&rotateAPIKeyYes, "yes", false, "Rotate without asking for confirmation")
+	rotateInstanceAPIKeyCmd.Flags().BoolVar(&rotateAPIKeyShow, "show", false, "Show the new API key instead of masking it")
 
 	instanceAccountCmd.AddCommand(rotatePasswordCmd)
 	instanceAccountCmd.AddCommand(rotateInstanceAPIKeyCmd)
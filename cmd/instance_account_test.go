@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rotateAPIKeyClient struct {
+	client.ClientAPI
+	rotated []string
+}
+
+func (c *rotateAPIKeyClient) RotateInstanceAPIKey(instanceID string) (string, error) {
+	c.rotated = append(c.rotated, instanceID)
+	return "a1b2c3d4-e5f6-7890-abcd-ef1234567890", nil
+}
+
+func TestRotateInstanceAPIKeyCmd(t *testing.T) {
+	fake := &rotateAPIKeyClient{}
+	useFakeClient(t, fake)
+
+	cmd := rotateInstanceAPIKeyCmd
+	cmd.InheritedFlags()
+	defer resetFlags(cmd)
+	defer resetFlags(rootCmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--id", "1234"}))
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	cmd.SetIn(&bytes.Buffer{})
+	defer cmd.SetErr(nil)
+	defer cmd.SetIn(nil)
+
+	err := cmd.RunE(cmd, []string{})
+	assert.ErrorContains(t, err, "Use --yes to proceed non-interactively")
+	assert.Empty(t, fake.rotated, "the key may not be rotated without confirmation")
+
+	require.NoError(t, cmd.Flags().Set("yes", "true"))
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+	assert.Equal(t, []string{"1234"}, fake.rotated)
+	assert.Equal(t, "Apikey = a1b2****\n", out, "the new key is masked by default")
+
+	require.NoError(t, cmd.Flags().Set("show", "true"))
+	out = captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+	assert.Equal(t, "Apikey = a1b2c3d4-e5f6-7890-abcd-ef1234567890\n", out)
+}
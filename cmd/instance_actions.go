@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"cloudamqp-cli/client"
@@ -24,29 +25,41 @@ var instanceActionsCmd = &cobra.Command{
 var restartRabbitMQCmd = &cobra.Command{
 	Use:   "restart-rabbitmq --id <instance_id>",
 	Short: "Restart RabbitMQ",
-	Long:  `Restart RabbitMQ on specified nodes or all nodes.`,
+	Long: `Restart RabbitMQ on specified nodes or all nodes.
+
+With --id-file RabbitMQ is restarted on every instance listed in the file,
+one ID per line, and a summary with the result for each is printed.`,
 	Example: `  cloudamqp instance restart-rabbitmq --id 1234
-  cloudamqp instance restart-rabbitmq --id 1234 --nodes=node1,node2`,
+  cloudamqp instance restart-rabbitmq --id 1234 --nodes=node1,node2
+  cloudamqp instance restart-rabbitmq --id-file ids.txt`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return performNodeAction(cmd, "restart-rabbitmq")
 	},
 }
 
 var restartClusterCmd = &cobra.Command{
-	Use:     "restart-cluster --id <instance_id>",
-	Short:   "Restart cluster",
-	Long:    `Restart the entire cluster.`,
-	Example: `  cloudamqp instance restart-cluster --id 1234`,
+	Use:   "restart-cluster --id <instance_id>",
+	Short: "Restart cluster",
+	Long: `Restart the entire cluster.
+
+With --id-file every instance listed in the file, one ID per line, is
+restarted and a summary with the result for each is printed.`,
+	Example: `  cloudamqp instance restart-cluster --id 1234
+  cloudamqp instance restart-cluster --id-file ids.txt`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return performClusterAction(cmd, "restart-cluster")
 	},
 }
 
 var restartManagementCmd = &cobra.Command{
-	Use:     "restart-management --id <instance_id>",
-	Short:   "Restart management interface",
-	Long:    `Restart the RabbitMQ management interface.`,
-	Example: `  cloudamqp instance restart-management --id 1234`,
+	Use:   "restart-management --id <instance_id>",
+	Short: "Restart management interface",
+	Long: `Restart the RabbitMQ management interface.
+
+With --id-file the management interface is restarted on every instance
+listed in the file, one ID per line.`,
+	Example: `  cloudamqp instance restart-management --id 1234
+  cloudamqp instance restart-management --id-file ids.txt`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return performNodeAction(cmd, "restart-management")
 	},
@@ -172,12 +185,11 @@ var upgradeVersionsCmd = &cobra.Command{
 	Long:    `Returns what version of Erlang and RabbitMQ the cluster will update to.`,
 	Example: `  cloudamqp instance upgrade-versions --id 1234`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
 		}
 
-		var err error
 		apiKey, err := getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
@@ -187,8 +199,7 @@ var upgradeVersionsCmd = &cobra.Command{
 
 		versions, err := c.GetUpgradeVersions(idFlag)
 		if err != nil {
-			fmt.Printf("Error getting upgrade versions: %v\n", err)
-			return err
+			return fmt.Errorf("failed to get upgrade versions: %w", err)
 		}
 
 		output, err := json.MarshalIndent(versions, "", "  ")
@@ -196,7 +207,7 @@ var upgradeVersionsCmd = &cobra.Command{
 			return fmt.Errorf("failed to format response: %v", err)
 		}
 
-		fmt.Printf("Upgrade versions:\n%s\n", string(output))
+		fmt.Fprintf(cmd.OutOrStdout(), "Upgrade versions:\n%s\n", string(output))
 		return nil
 	},
 }
@@ -204,7 +215,7 @@ var upgradeVersionsCmd = &cobra.Command{
 // Helper functions
 func performNodeAction(cmd *cobra.Command, action string) error {
 	idFlag, _ := cmd.Flags().GetString("id")
-	if idFlag == "" {
+	if idFlag == "" && idFilePath(cmd) == "" {
 		return fmt.Errorf("instance ID is required. Use --id flag")
 	}
 
@@ -222,33 +233,42 @@ func performNodeAction(cmd *cobra.Command, action string) error {
 		nodes = strings.Split(nodesStr, ",")
 	}
 
-	switch action {
-	case "restart-rabbitmq":
-		err = c.RestartRabbitMQ(idFlag, nodes)
-	case "restart-management":
-		err = c.RestartManagement(idFlag, nodes)
-	case "stop":
-		err = c.StopInstance(idFlag, nodes)
-	case "start":
-		err = c.StartInstance(idFlag, nodes)
-	case "reboot":
-		err = c.RebootInstance(idFlag, nodes)
-	default:
+	run := func(instanceID string) error {
+		switch action {
+		case "restart-rabbitmq":
+			return c.RestartRabbitMQ(instanceID, nodes)
+		case "restart-management":
+			return c.RestartManagement(instanceID, nodes)
+		case "stop":
+			return c.StopInstance(instanceID, nodes)
+		case "start":
+			return c.StartInstance(instanceID, nodes)
+		case "reboot":
+			return c.RebootInstance(instanceID, nodes)
+		}
 		return fmt.Errorf("unknown action: %s", action)
 	}
 
+	if idFilePath(cmd) != "" {
+		return performBatchAction(cmd, c, idFlag, run)
+	}
+
+	instanceID, err := resolveInstance(c, idFlag)
 	if err != nil {
-		fmt.Printf("Error performing %s: %v\n", action, err)
 		return err
 	}
+	err = run(strconv.Itoa(instanceID))
+	if err != nil {
+		return fmt.Errorf("failed to perform %s: %w", action, err)
+	}
 
-	fmt.Printf("%s initiated successfully.\n", strings.Title(strings.ReplaceAll(action, "-", " ")))
+	printStatus(cmd, "%s initiated successfully.", strings.Title(strings.ReplaceAll(action, "-", " ")))
 	return nil
 }
 
 func performClusterAction(cmd *cobra.Command, action string) error {
 	idFlag, _ := cmd.Flags().GetString("id")
-	if idFlag == "" {
+	if idFlag == "" && idFilePath(cmd) == "" {
 		return fmt.Errorf("instance ID is required. Use --id flag")
 	}
 
@@ -260,33 +280,54 @@ func performClusterAction(cmd *cobra.Command, action string) error {
 
 	c := client.New(apiKey, Version)
 
-	switch action {
-	case "restart-cluster":
-		err = c.RestartCluster(idFlag)
-	case "stop-cluster":
-		err = c.StopCluster(idFlag)
-	case "start-cluster":
-		err = c.StartCluster(idFlag)
-	default:
+	run := func(instanceID string) error {
+		switch action {
+		case "restart-cluster":
+			return c.RestartCluster(instanceID)
+		case "stop-cluster":
+			return c.StopCluster(instanceID)
+		case "start-cluster":
+			return c.StartCluster(instanceID)
+		}
 		return fmt.Errorf("unknown action: %s", action)
 	}
 
+	if idFilePath(cmd) != "" {
+		return performBatchAction(cmd, c, idFlag, run)
+	}
+
+	instanceID, err := resolveInstance(c, idFlag)
 	if err != nil {
-		fmt.Printf("Error performing %s: %v\n", action, err)
 		return err
 	}
+	err = run(strconv.Itoa(instanceID))
+	if err != nil {
+		return fmt.Errorf("failed to perform %s: %w", action, err)
+	}
 
-	fmt.Printf("%s initiated successfully.\n", strings.Title(strings.ReplaceAll(action, "-", " ")))
+	printStatus(cmd, "%s initiated successfully.", strings.Title(strings.ReplaceAll(action, "-", " ")))
 	return nil
 }
 
+// performBatchAction runs an action on the instances selected with --id and
+// --id-file, a few at a time, and prints a per-instance summary.
+func performBatchAction(cmd *cobra.Command, c client.ClientAPI, idFlag string, run func(instanceID string) error) error {
+	instances, err := batchInstances(cmd, c, idFlag, nil)
+	if err != nil {
+		return err
+	}
+	results := runBulk(commandContext(cmd), instances, func(instance client.Instance) error {
+		return run(strconv.Itoa(instance.ID))
+	}, false)
+	return printBulkResults(cmd, results)
+}
+
 func performUpgradeAction(cmd *cobra.Command, action, version string) error {
-	idFlag, _ := cmd.Flags().GetString("id")
-	if idFlag == "" {
-		return fmt.Errorf("instance ID is required. Use --id flag")
+	idFlag, err := instanceIDFlag(cmd)
+	if err != nil {
+		return err
 	}
 
-	var err error
 	apiKey, err := getAPIKey()
 	if err != nil {
 		return fmt.Errorf("failed to get API key: %w", err)
@@ -306,21 +347,19 @@ func performUpgradeAction(cmd *cobra.Command, action, version string) error {
 	}
 
 	if err != nil {
-		fmt.Printf("Error performing %s: %v\n", action, err)
-		return err
+		return fmt.Errorf("failed to perform %s: %w", action, err)
 	}
 
-	fmt.Printf("%s initiated successfully.\n", strings.Title(strings.ReplaceAll(action, "-", " ")))
+	printStatus(cmd, "%s initiated successfully.", strings.Title(strings.ReplaceAll(action, "-", " ")))
 	return nil
 }
 
 func performToggleAction(cmd *cobra.Command, action string) error {
-	idFlag, _ := cmd.Flags().GetString("id")
-	if idFlag == "" {
-		return fmt.Errorf("instance ID is required. Use --id flag")
+	idFlag, err := instanceIDFlag(cmd)
+	if err != nil {
+		return err
 	}
 
-	var err error
 	apiKey, err := getAPIKey()
 	if err != nil {
 		return fmt.Errorf("failed to get API key: %w", err)
@@ -361,15 +400,14 @@ func performToggleAction(cmd *cobra.Command, action string) error {
 	}
 
 	if err != nil {
-		fmt.Printf("Error toggling %s: %v\n", action, err)
-		return err
+		return fmt.Errorf("failed to toggle %s: %w", action, err)
 	}
 
 	status := "disabled"
 	if enable {
 		status = "enabled"
 	}
-	fmt.Printf("%s %s successfully.\n", strings.Title(action), status)
+	printStatus(cmd, "%s %s successfully.", strings.Title(action), status)
 	return nil
 }
 
@@ -383,9 +421,20 @@ func init() {
 		toggleHiPECmd, toggleFirehoseCmd, upgradeVersionsCmd,
 	}
 
+	// Restarts can also target the instances listed in --id-file
+	batchCommands := map[*cobra.Command]bool{
+		restartRabbitMQCmd: true, restartClusterCmd: true, restartManagementCmd: true,
+	}
+
 	for _, cmd := range commands {
-		cmd.Flags().StringP("id", "", "", "Instance ID (required)")
-		cmd.MarkFlagRequired("id")
+		if batchCommands[cmd] {
+			cmd.Flags().StringP("id", "", "", "Instance ID or name (required unless --id-file is given)")
+			addIDFileFlag(cmd)
+			cmd.MarkFlagsOneRequired("id", "id-file")
+		} else {
+			cmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+			cmd.MarkFlagRequired("id")
+		}
 		cmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
 	}
 
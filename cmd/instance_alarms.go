@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// alarmTypes lists the alarm types supported by the API.
+var alarmTypes = []string{
+	"cpu", "memory", "disk", "queue", "connection", "consumer", "netsplit", "server_unreachable", "notice",
+}
+
+var instanceAlarmsCmd = &cobra.Command{
+	Use:   "alarms",
+	Short: "Manage instance alarms",
+	Long:  `List, create, and delete alarms for the instance.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Help()
+		cmd.SilenceUsage = true
+		return fmt.Errorf("subcommand required")
+	},
+}
+
+var instanceAlarmsListCmd = &cobra.Command{
+	Use:     "list --id <instance_id>",
+	Aliases: []string{"ls"},
+	Short:   "List alarms",
+	Long:    `Retrieves all alarms configured for the instance.`,
+	Example: `  cloudamqp instance alarms list --id 1234`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		alarms, err := c.ListAlarms(idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to list alarms: %w", err)
+		}
+
+		if len(alarms) == 0 {
+			printStatus(cmd, "No alarms found.")
+			return nil
+		}
+
+		p, err := getListPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		headers := []string{"ID", "TYPE", "VALUE", "THRESHOLD", "RECIPIENTS", "ENABLED"}
+		rows := make([][]string, len(alarms))
+		for i, alarm := range alarms {
+			enabled := "No"
+			if alarm.Enabled {
+				enabled = "Yes"
+			}
+			recipients := make([]string, len(alarm.Recipients))
+			for j, r := range alarm.Recipients {
+				recipients[j] = strconv.Itoa(r)
+			}
+			rows[i] = []string{
+				strconv.Itoa(alarm.ID),
+				alarm.Type,
+				strconv.Itoa(alarm.ValueThreshold),
+				fmt.Sprintf("%ds", alarm.TimeThreshold),
+				strings.Join(recipients, ","),
+				enabled,
+			}
+		}
+		p.PrintRecords(headers, rows)
+
+		return nil
+	},
+}
+
+var instanceAlarmsCreateCmd = &cobra.Command{
+	Use:     "create --id <instance_id> --type <type>",
+	Aliases: []string{"new"},
+	Short:   "Create an alarm",
+	Long: `Create a new alarm for the instance.
+
+Available types: cpu, memory, disk, queue, connection, consumer, netsplit, server_unreachable, notice
+
+--value is the threshold that triggers the alarm (percent for cpu/memory/disk,
+count for queue/connection/consumer). --time-threshold is how many seconds the
+value must be exceeded before a notification is sent.`,
+	Example: `  cloudamqp instance alarms create --id 1234 --type cpu --value 90 --time-threshold 60 --recipients 1,2
+  cloudamqp instance alarms create --id 1234 --type queue --value 10000 --time-threshold 300 --queue-regex "^orders" --recipients 1`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		alarmType, _ := cmd.Flags().GetString("type")
+		if err := validateAlarmType(alarmType); err != nil {
+			return err
+		}
+
+		value, _ := cmd.Flags().GetInt("value")
+		timeThreshold, _ := cmd.Flags().GetInt("time-threshold")
+		recipients, _ := cmd.Flags().GetIntSlice("recipients")
+		vhostRegex, _ := cmd.Flags().GetString("vhost-regex")
+		queueRegex, _ := cmd.Flags().GetString("queue-regex")
+
+		req := &client.AlarmCreateRequest{
+			Type:           alarmType,
+			Enabled:        true,
+			ValueThreshold: value,
+			TimeThreshold:  timeThreshold,
+			VHostRegex:     vhostRegex,
+			QueueRegex:     queueRegex,
+			Recipients:     recipients,
+		}
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "POST", "/instances/"+idFlag+"/alarms", req)
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		resp, err := c.CreateAlarm(idFlag, req)
+		if err != nil {
+			return fmt.Errorf("failed to create alarm: %w", err)
+		}
+
+		output, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format response: %v", err)
+		}
+
+		printStatus(cmd, "Alarm created successfully.")
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
+		return nil
+	},
+}
+
+var instanceAlarmsDeleteCmd = &cobra.Command{
+	Use:     "delete --id <instance_id> --alarm-id <alarm_id>",
+	Aliases: []string{"rm"},
+	Short:   "Delete an alarm",
+	Long:    `Deletes an alarm from the instance.`,
+	Example: `  cloudamqp instance alarms delete --id 1234 --alarm-id 42`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		alarmID, _ := cmd.Flags().GetInt("alarm-id")
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "DELETE", "/instances/"+idFlag+"/alarms/"+strconv.Itoa(alarmID), nil)
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		err = c.DeleteAlarm(idFlag, alarmID)
+		if err != nil {
+			return fmt.Errorf("failed to delete alarm: %w", err)
+		}
+
+		printStatus(cmd, "Alarm %d deleted successfully.", alarmID)
+		return nil
+	},
+}
+
+// validateAlarmType checks alarmType against the known alarm types.
+func validateAlarmType(alarmType string) error {
+	for _, t := range alarmTypes {
+		if t == alarmType {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid alarm type %q. Valid types are: %s", alarmType, strings.Join(alarmTypes, ", "))
+}
+
+// completeAlarmTypes returns the valid alarm types for completion
+func completeAlarmTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return alarmTypes, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	instanceAlarmsListCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceAlarmsListCmd.MarkFlagRequired("id")
+
+	instanceAlarmsCreateCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceAlarmsCreateCmd.Flags().String("type", "", "Alarm type (required)")
+	instanceAlarmsCreateCmd.Flags().Int("value", 0, "Value threshold that triggers the alarm")
+	instanceAlarmsCreateCmd.Flags().Int("time-threshold", 0, "Seconds the value must be exceeded before notifying")
+	instanceAlarmsCreateCmd.Flags().IntSlice("recipients", []int{}, "Recipient IDs to notify (comma-separated)")
+	instanceAlarmsCreateCmd.Flags().String("vhost-regex", "", "Regex of vhosts to monitor (queue and consumer alarms)")
+	instanceAlarmsCreateCmd.Flags().String("queue-regex", "", "Regex of queues to monitor (queue and consumer alarms)")
+	addDryRunFlag(instanceAlarmsCreateCmd)
+	instanceAlarmsCreateCmd.MarkFlagRequired("id")
+	instanceAlarmsCreateCmd.MarkFlagRequired("type")
+	instanceAlarmsCreateCmd.RegisterFlagCompletionFunc("type", completeAlarmTypes)
+
+	instanceAlarmsDeleteCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceAlarmsDeleteCmd.Flags().Int("alarm-id", 0, "Alarm ID (required)")
+	addDryRunFlag(instanceAlarmsDeleteCmd)
+	instanceAlarmsDeleteCmd.MarkFlagRequired("id")
+	instanceAlarmsDeleteCmd.MarkFlagRequired("alarm-id")
+
+	for _, cmd := range []*cobra.Command{instanceAlarmsListCmd, instanceAlarmsCreateCmd, instanceAlarmsDeleteCmd} {
+		cmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+	}
+
+	instanceAlarmsCmd.AddCommand(instanceAlarmsListCmd)
+	instanceAlarmsCmd.AddCommand(instanceAlarmsCreateCmd)
+	instanceAlarmsCmd.AddCommand(instanceAlarmsDeleteCmd)
+}
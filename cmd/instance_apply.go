@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var instanceApplyCmd = &cobra.Command{
+	Use:   "apply --file <spec>",
+	Short: "Reconcile an instance toward a spec file",
+	Long: `Compare an instance with a spec written by 'cloudamqp instance export' and
+apply only the differences in name, plan, tags, RabbitMQ configuration,
+plugins and firewall rules. Alarms are not changed.
+
+Sections left out of the spec are not touched, and config keys not listed in
+the spec keep their current values. The instance is selected with --id, or
+by the name in the spec when --id is omitted.
+
+The planned changes are printed first. Confirm them interactively, or pass
+--yes to apply without asking. Use --dry-run to only print the changes.
+Changing to a smaller plan, or a config setting to a value outside its safe
+range (as checked by 'config set'), is refused unless --force is given.
+
+--print-schema prints a JSON Schema of spec files instead, generated from
+the fields apply reads, for editors to validate and complete specs with.
+'instance create' takes its settings as flags and reads no spec file, so the
+schema is printed by apply.`,
+	Example: `  cloudamqp instance apply --file instance.yaml --id 1234
+  cloudamqp instance apply --file instance.yaml --dry-run
+  cloudamqp instance apply --file instance.yaml --yes
+  cloudamqp instance apply --print-schema > instance.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if printSchema, _ := cmd.Flags().GetBool("print-schema"); printSchema {
+			data, err := json.MarshalIndent(specSchema(), "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to format schema: %w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+			return nil
+		}
+
+		file, _ := cmd.Flags().GetString("file")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read spec file: %w", err)
+		}
+
+		// YAML is a superset of JSON, so this reads both export formats
+		var spec InstanceSpec
+		if err := yaml.Unmarshal(data, &spec); err != nil {
+			return fmt.Errorf("failed to parse spec file: %w", err)
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		idFlag, _ := cmd.Flags().GetString("id")
+		instanceID, err := resolveSpecInstance(c, idFlag, spec.Name)
+		if err != nil {
+			return err
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		changes, err := diffInstanceSpec(c, instanceID, &spec, force)
+		if err != nil {
+			return err
+		}
+
+		if len(changes) == 0 {
+			printStatus(cmd, "Instance %d is up to date.", instanceID)
+			return nil
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Changes to apply to instance %d:\n", instanceID)
+		for _, change := range changes {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", change.Description)
+		}
+
+		if isDryRun(cmd) {
+			return nil
+		}
+
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !yes {
+			ok, err := confirm(cmd, "Apply these changes?", "--yes")
+			if err != nil {
+				return err
+			}
+			if !ok {
+				printStatus(cmd, "Apply cancelled.")
+				return nil
+			}
+		}
+
+		for i, change := range changes {
+			if err := change.Apply(); err != nil {
+				return fmt.Errorf("failed to apply %q (%d of %d changes applied): %w", change.Description, i, len(changes), err)
+			}
+		}
+
+		printStatus(cmd, "Applied %d change(s) to instance %d.", len(changes), instanceID)
+		return nil
+	},
+}
+
+// resolveSpecInstance returns the instance given with --id, or looks up
+// the instance with the spec's name.
+func resolveSpecInstance(c client.ClientAPI, idFlag, name string) (int, error) {
+	if idFlag != "" {
+		return resolveInstance(c, idFlag)
+	}
+	if name == "" {
+		return 0, fmt.Errorf("the spec has no name. Use --id to select the instance")
+	}
+	return findInstanceByName(c, name)
+}
+
+func init() {
+	instanceApplyCmd.Flags().String("file", "", "Spec file from 'instance export' (required)")
+	instanceApplyCmd.Flags().StringP("id", "", "", "Instance ID or name (defaults to the instance named in the spec)")
+	instanceApplyCmd.Flags().Bool("yes", false, "Apply without asking for confirmation")
+	instanceApplyCmd.Flags().Bool("force", false, "Allow changing to a smaller plan and config values outside their safe range")
+	addDryRunFlag(instanceApplyCmd)
+	instanceApplyCmd.Flags().Bool("print-schema", false, "Print a JSON Schema of spec files and exit")
+	instanceApplyCmd.MarkFlagsOneRequired("file", "print-schema")
+	instanceApplyCmd.MarkFlagsMutuallyExclusive("file", "print-schema")
+	instanceApplyCmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -12,7 +14,11 @@ import (
 var instanceConfigCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage RabbitMQ configuration",
-	Long:  `Get and update RabbitMQ configuration settings for the instance.`,
+	Long: `Get and update the broker configuration settings of the instance.
+
+Only RabbitMQ instances have a config endpoint in the API; LavinMQ
+instances are refused. The broker is detected from the instance, or from
+its plan when the API doesn't report it.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.Help()
 		cmd.SilenceUsage = true
@@ -22,35 +28,84 @@ var instanceConfigCmd = &cobra.Command{
 
 var instanceConfigListCmd = &cobra.Command{
 	Use:     "list --id <instance_id>",
+	Aliases: []string{"ls"},
 	Short:   "List all configuration settings",
-	Long:    `Retrieve and display all current RabbitMQ configuration settings.`,
-	Example: `  cloudamqp instance config list --id 1234`,
+	Long: `Retrieve and display all current configuration settings of the broker.
+
+Use --all to also show the settings that are not configured, with their
+default values.
+
+--grep shows only the settings whose key contains the pattern, ignoring
+case; with --regex the pattern is a regular expression matched against the
+key. The filter applies to every output format.`,
+	Example: `  cloudamqp instance config list --id 1234
+  cloudamqp instance config list --id 1234 --all
+  cloudamqp instance config list --id 1234 --grep heartbeat
+  cloudamqp instance config list --id 1234 --all --grep '^rabbit\.(heartbeat|channel_max)$' --regex`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		pattern, _ := cmd.Flags().GetString("grep")
+		useRegex, _ := cmd.Flags().GetBool("regex")
+		if useRegex && pattern == "" {
+			return fmt.Errorf("--regex needs a pattern given with --grep")
+		}
+		match, err := nameMatcher(pattern, useRegex)
+		if err != nil {
+			return err
 		}
 
-		var err error
 		apiKey, err := getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
 		}
 
-		c := client.New(apiKey, Version)
+		c := newAPIClient(apiKey)
 
-		config, err := c.GetRabbitMQConfig(idFlag)
+		backend, err := configBackend(c, idFlag)
 		if err != nil {
-			fmt.Printf("Error getting configuration: %v\n", err)
 			return err
 		}
 
+		config, err := getInstanceConfig(c, backend, idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to get configuration: %w", err)
+		}
+
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			rows := configRowsWithDefaults(backend, config)
+			if pattern != "" {
+				rows = slices.DeleteFunc(rows, func(row []string) bool { return !match(row[0]) })
+			}
+			if len(rows) == 0 {
+				printStatus(cmd, "No settings matching '%s' found.", pattern)
+				return nil
+			}
+			p, err := getListPrinter(cmd)
+			if err != nil {
+				return err
+			}
+			p.PrintRecords([]string{"SETTING", "VALUE", "SOURCE"}, rows)
+			return nil
+		}
+
+		if pattern != "" {
+			config = filterConfigKeys(config, match)
+			if len(config) == 0 {
+				printStatus(cmd, "No settings matching '%s' found.", pattern)
+				return nil
+			}
+		}
+
 		if len(config) == 0 {
-			fmt.Println("No configuration found.")
+			printStatus(cmd, "No configuration found.")
 			return nil
 		}
 
-		p, err := getPrinter(cmd)
+		p, err := getListPrinter(cmd)
 		if err != nil {
 			return err
 		}
@@ -66,38 +121,101 @@ var instanceConfigListCmd = &cobra.Command{
 	},
 }
 
+// filterConfigKeys returns the settings of config whose key matches.
+func filterConfigKeys(config map[string]interface{}, match func(string) bool) map[string]interface{} {
+	filtered := make(map[string]interface{})
+	for key, value := range config {
+		if match(key) {
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
+
+// configRowsWithDefaults merges the configured values with the defaults of
+// every known setting of the backend, sorted by setting, marking where each
+// value comes from. Null values, which the API returns for unset settings,
+// leave the default in place.
+func configRowsWithDefaults(backend string, config map[string]interface{}) [][]string {
+	schema := configSchema(backend)
+	values := make(map[string]interface{}, len(config)+len(schema))
+	for _, s := range schema {
+		values[s.Key] = s.Default
+	}
+	for key, value := range config {
+		if value != nil {
+			values[key] = value
+		}
+	}
+
+	rows := make([][]string, 0, len(values))
+	for _, key := range sortedKeys(values) {
+		source := "default"
+		if config[key] != nil {
+			source = "configured"
+		}
+		rows = append(rows, []string{key, fmt.Sprintf("%v", plainConfigValue(values[key])), source})
+	}
+	return rows
+}
+
 var instanceConfigGetCmd = &cobra.Command{
-	Use:     "get --id <instance_id> <setting>",
-	Short:   "Get a specific configuration setting",
-	Long:    `Retrieve a specific RabbitMQ configuration setting by name.`,
-	Example: `  cloudamqp instance config get --id 1234 rabbit.heartbeat`,
-	Args:    cobra.ExactArgs(1),
+	Use:   "get --id <instance_id> <setting>",
+	Short: "Get a specific configuration setting",
+	Long: `Retrieve a specific configuration setting of the broker by name.
+
+Dotted names reach into nested settings, e.g. rabbit.tcp_listen_options.backlog
+returns the backlog value of rabbit.tcp_listen_options.
+
+With --default, only the value is printed, without the setting name, and a
+setting that isn't configured prints the given value instead, so the command
+can be used in shell substitution.`,
+	Example: `  cloudamqp instance config get --id 1234 rabbit.heartbeat
+  cloudamqp instance config get --id 1234 rabbit.tcp_listen_options.backlog
+  cloudamqp instance config get --id 1234 rabbit.heartbeat --default 60`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
 		}
 
 		settingName := args[0]
 
-		var err error
 		apiKey, err := getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
 		}
 
-		c := client.New(apiKey, Version)
+		c := newAPIClient(apiKey)
 
-		config, err := c.GetRabbitMQConfig(idFlag)
+		backend, err := configBackend(c, idFlag)
 		if err != nil {
-			fmt.Printf("Error getting configuration: %v\n", err)
 			return err
 		}
 
-		if value, exists := config[settingName]; exists {
-			fmt.Printf("%s: %v\n", settingName, value)
+		config, err := getInstanceConfig(c, backend, idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to get configuration: %w", err)
+		}
+
+		// With --default the bare value is printed either way, so
+		// $(cloudamqp instance config get ...) gets just the value
+		bare := cmd.Flags().Changed("default")
+		if value, exists := getByPath(config, settingName); exists {
+			value = plainConfigValue(value)
+			if bare {
+				fmt.Fprintln(cmd.OutOrStdout(), value)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %v\n", settingName, value)
+			}
+		} else if err := otherBrokerSetting(backend, settingName); err != nil {
+			return err
+		} else if bare {
+			fallback, _ := cmd.Flags().GetString("default")
+			fmt.Fprintln(cmd.OutOrStdout(), fallback)
 		} else {
-			fmt.Printf("Setting '%s' not found\n", settingName)
+			printStatus(cmd, "Setting '%s' not found", settingName)
 		}
 
 		return nil
@@ -105,72 +223,322 @@ var instanceConfigGetCmd = &cobra.Command{
 }
 
 var instanceConfigSetCmd = &cobra.Command{
-	Use:   "set --id <instance_id> <setting> <value>",
+	Use:   "set (--id <instance_id> | --tag <tag> | --id-file <file>) <setting> <value>",
 	Short: "Set a configuration setting",
-	Long:  `Update a RabbitMQ configuration setting. The value will be automatically converted to the appropriate type.`,
+	Long: `Update a configuration setting of the broker. The value will be automatically converted to the appropriate type.
+A dotted name that reaches into a nested setting, such as
+rabbit.tcp_listen_options.backlog, updates only that value and keeps the
+rest of the nested setting.
+LavinMQ settings given for a RabbitMQ instance are rejected.
+
+With --tag the setting is applied to every instance that has all the given
+tags, and with --id-file to every instance listed in the file (one ID per
+line). --id, --id-file and --tag can be combined and select the instances of
+all of them. Several instances are updated a few at a time, and a summary with the result for each
+instance is printed. --yes is required when more than one instance matches.
+Failures don't stop the others and are all reported at the end; use
+--fail-fast to skip the remaining instances after the first failure.
+
+Settings that can destabilize the broker when set wrong, such as
+rabbit.vm_memory_high_watermark above 0.9 or rabbit.disk_free_limit below
+50 MB, are refused unless --force is given, which sets them with a warning.`,
 	Example: `  cloudamqp instance config set --id 1234 rabbit.heartbeat 120
-  cloudamqp instance config set --id 1234 rabbit.vm_memory_high_watermark 0.8`,
+  cloudamqp instance config set --id 1234 rabbit.vm_memory_high_watermark 0.8
+  cloudamqp instance config set --id 1234 rabbit.heartbeat 120 --dry-run
+  cloudamqp instance config set --tag prod rabbit.heartbeat 120 --yes
+  cloudamqp instance config set --id-file ids.txt rabbit.heartbeat 120 --yes
+  cloudamqp instance config set --id 1234 rabbit.vm_memory_high_watermark 0.95 --force`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		if idFlag == "" && len(tags) == 0 && idFilePath(cmd) == "" {
+			return fmt.Errorf("instance ID is required. Use --id flag, or --tag or --id-file to select several instances")
 		}
 
 		settingName := args[0]
-		settingValue := args[1]
+		value := coerceValue(args[1])
+		config := map[string]interface{}{
+			settingName: value,
+		}
+
+		if err := checkConfigSafety(settingName, value); err != nil {
+			if force, _ := cmd.Flags().GetBool("force"); !force {
+				return fmt.Errorf("%w. Use --force to set it anyway", err)
+			}
+			logWarn("%v", err)
+		}
+
+		if len(tags) > 0 || idFilePath(cmd) != "" {
+			return setConfigBulk(cmd, idFlag, tags, settingName, value)
+		}
 
-		var err error
 		apiKey, err := getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
 		}
 
-		c := client.New(apiKey, Version)
-
-		// Convert string value to appropriate type
-		var value interface{}
-		if strings.ToLower(settingValue) == "true" {
-			value = true
-		} else if strings.ToLower(settingValue) == "false" {
-			value = false
-		} else if strings.ToLower(settingValue) == "null" {
-			value = nil
-		} else if intVal, err := strconv.Atoi(settingValue); err == nil {
-			value = intVal
-		} else if floatVal, err := strconv.ParseFloat(settingValue, 64); err == nil {
-			value = floatVal
-		} else {
-			value = settingValue
+		c := newAPIClient(apiKey)
+
+		instanceID, err := resolveInstance(c, idFlag)
+		if err != nil {
+			return err
 		}
+		idFlag = strconv.Itoa(instanceID)
 
-		config := map[string]interface{}{
-			settingName: value,
+		backend, err := configBackend(c, idFlag)
+		if err != nil {
+			return err
+		}
+		if err := checkConfigForBackend(backend, config); err != nil {
+			return err
 		}
 
-		err = c.UpdateRabbitMQConfig(idFlag, config)
+		body, err := configSetBody(c, backend, idFlag, settingName, value)
 		if err != nil {
-			fmt.Printf("Error updating configuration: %v\n", err)
 			return err
 		}
 
-		fmt.Printf("Configuration setting '%s' updated to: %v\n", settingName, value)
+		if isDryRun(cmd) {
+			endpoint, err := configEndpoint(backend, idFlag)
+			if err != nil {
+				return err
+			}
+			return printDryRun(cmd, "PUT", endpoint, body)
+		}
+
+		err = updateInstanceConfig(c, backend, idFlag, body)
+		if err != nil {
+			return fmt.Errorf("failed to update configuration: %w", err)
+		}
+
+		printStatus(cmd, "Configuration setting '%s' updated to: %v", settingName, value)
 		return nil
 	},
 }
 
+// setConfigBulk sets a setting concurrently on the instances selected with
+// --id, --id-file and --tag, and prints a per-instance summary.
+func setConfigBulk(cmd *cobra.Command, idFlag string, tags []string, settingName string, value interface{}) error {
+	config := map[string]interface{}{settingName: value}
+
+	apiKey, err := getAPIKey()
+	if err != nil {
+		return fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	c := newAPIClient(apiKey)
+
+	instances, err := batchInstances(cmd, c, idFlag, tags)
+	if err != nil {
+		return err
+	}
+
+	// The plans are only needed for instances the list has no backend for
+	var plans []client.Plan
+	if slices.ContainsFunc(instances, func(instance client.Instance) bool { return instance.Backend == "" }) {
+		if plans, err = c.ListPlans(""); err != nil {
+			return fmt.Errorf("failed to list plans: %w", err)
+		}
+	}
+
+	if isDryRun(cmd) {
+		for _, instance := range instances {
+			backend := backendOf(plans, &instance)
+			if err := checkConfigForBackend(backend, config); err != nil {
+				return fmt.Errorf("instance %d (%s): %w", instance.ID, instance.Name, err)
+			}
+			body, err := configSetBody(c, backend, strconv.Itoa(instance.ID), settingName, value)
+			if err != nil {
+				return fmt.Errorf("instance %d (%s): %w", instance.ID, instance.Name, err)
+			}
+			endpoint, err := configEndpoint(backend, strconv.Itoa(instance.ID))
+			if err != nil {
+				return fmt.Errorf("instance %d (%s): %w", instance.ID, instance.Name, err)
+			}
+			if err := printDryRun(cmd, "PUT", endpoint, body); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if yes, _ := cmd.Flags().GetBool("yes"); len(instances) > 1 && !yes {
+		return fmt.Errorf("%d instances match: %s. Use --yes to update all of them", len(instances), instanceNames(instances))
+	}
+
+	results := runBulk(commandContext(cmd), instances, func(instance client.Instance) error {
+		backend := backendOf(plans, &instance)
+		if err := checkConfigForBackend(backend, config); err != nil {
+			return err
+		}
+		body, err := configSetBody(c, backend, strconv.Itoa(instance.ID), settingName, value)
+		if err != nil {
+			return err
+		}
+		return updateInstanceConfig(c, backend, strconv.Itoa(instance.ID), body)
+	}, isFailFast(cmd))
+	return printBulkResults(cmd, results)
+}
+
+// configSetBody is the body that sets settingName to value on the instance.
+// Known settings are sent as they are; any other dotted name may point into
+// a nested setting, which needs the current config to update in place.
+func configSetBody(c client.ClientAPI, backend, instanceID, settingName string, value interface{}) (map[string]interface{}, error) {
+	if _, known := lookupConfigSetting(configSchema(backend), settingName); known || !strings.Contains(settingName, ".") {
+		return map[string]interface{}{settingName: value}, nil
+	}
+	current, err := getInstanceConfig(c, backend, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configuration: %w", err)
+	}
+	return configUpdate(current, settingName, value), nil
+}
+
+// configBackend returns the broker of the instance given by the --id value
+// of a config command.
+func configBackend(c client.ClientAPI, idFlag string) (string, error) {
+	instanceID, err := strconv.Atoi(idFlag)
+	if err != nil {
+		return "", fmt.Errorf("invalid instance ID: %v", err)
+	}
+	return instanceBackend(c, instanceID)
+}
+
+// instanceBackend returns the broker the instance runs, from the backend
+// the API reports for it or, when the response has none, from its plan.
+func instanceBackend(c client.ClientAPI, instanceID int) (string, error) {
+	instance, err := c.GetInstance(instanceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance == nil {
+		return "", fmt.Errorf("instance %d not found", instanceID)
+	}
+	return fetchedInstanceBackend(c, instance)
+}
+
+// fetchedInstanceBackend is instanceBackend for an instance already
+// fetched. The plans are only listed when the instance has no backend.
+func fetchedInstanceBackend(c client.ClientAPI, instance *client.Instance) (string, error) {
+	if instance.Backend != "" {
+		return instance.Backend, nil
+	}
+	plans, err := c.ListPlans("")
+	if err != nil {
+		return "", fmt.Errorf("failed to list plans: %w", err)
+	}
+	return planBackend(plans, instance.Plan), nil
+}
+
+// backendOf returns the backend the API reports for instance, or else the
+// backend of its plan in plans.
+func backendOf(plans []client.Plan, instance *client.Instance) string {
+	if instance.Backend != "" {
+		return instance.Backend
+	}
+	return planBackend(plans, instance.Plan)
+}
+
+// planBackend returns the backend of the named plan. Plans missing from the
+// list are assumed to be RabbitMQ plans, which all older plans are.
+func planBackend(plans []client.Plan, plan string) string {
+	for _, p := range plans {
+		if p.Name == plan && p.Backend == client.BackendLavinMQ {
+			return client.BackendLavinMQ
+		}
+	}
+	return client.BackendRabbitMQ
+}
+
+// checkConfigForBackend rejects config that doesn't fit the broker before it
+// is sent. LavinMQ settings are validated in full. For RabbitMQ only keys
+// that belong to LavinMQ are rejected, since config set has always passed
+// other RabbitMQ keys through for the API to judge.
+func checkConfigForBackend(backend string, config map[string]any) error {
+	for _, key := range sortedKeys(config) {
+		if backend == client.BackendRabbitMQ {
+			if err := otherBrokerSetting(backend, key); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := validateConfigSetting(backend, key, config[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errLavinMQConfig is returned for LavinMQ instances. The API documents
+// only the RabbitMQ config endpoint, so LavinMQ settings can't be read or
+// changed; sending them there would be silently ignored.
+var errLavinMQConfig = errors.New("the API has no config endpoint for LavinMQ instances; only RabbitMQ settings can be managed")
+
+// configEndpoint is the API path of the backend's config of an instance.
+func configEndpoint(backend, instanceID string) (string, error) {
+	if backend == client.BackendLavinMQ {
+		return "", errLavinMQConfig
+	}
+	return "/instances/" + instanceID + "/config", nil
+}
+
+func getInstanceConfig(c client.ClientAPI, backend, instanceID string) (map[string]interface{}, error) {
+	if backend == client.BackendLavinMQ {
+		return nil, errLavinMQConfig
+	}
+	return c.GetRabbitMQConfig(instanceID)
+}
+
+func updateInstanceConfig(c client.ClientAPI, backend, instanceID string, config map[string]interface{}) error {
+	if backend == client.BackendLavinMQ {
+		return errLavinMQConfig
+	}
+	return c.UpdateRabbitMQConfig(instanceID, config)
+}
+
+// coerceValue converts a setting value given on the command line to a bool,
+// nil, int or float when it looks like one, and keeps it a string otherwise.
+func coerceValue(s string) interface{} {
+	switch strings.ToLower(s) {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if intVal, err := strconv.Atoi(s); err == nil {
+		return intVal
+	}
+	if floatVal, err := strconv.ParseFloat(s, 64); err == nil {
+		return floatVal
+	}
+	return s
+}
+
 func init() {
 	// Add --id flag to all subcommands
-	instanceConfigListCmd.Flags().StringP("id", "", "", "Instance ID (required)")
+	instanceConfigListCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
 	instanceConfigListCmd.MarkFlagRequired("id")
+	instanceConfigListCmd.Flags().Bool("all", false, "Include settings that are not configured, with their defaults")
+	instanceConfigListCmd.Flags().String("grep", "", "Only show settings whose key contains this text (case-insensitive)")
+	instanceConfigListCmd.Flags().Bool("regex", false, "Treat --grep as a regular expression")
 
-	instanceConfigGetCmd.Flags().StringP("id", "", "", "Instance ID (required)")
+	instanceConfigGetCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
 	instanceConfigGetCmd.MarkFlagRequired("id")
+	instanceConfigGetCmd.Flags().String("default", "", "Value to print when the setting isn't configured")
 
-	instanceConfigSetCmd.Flags().StringP("id", "", "", "Instance ID (required)")
-	instanceConfigSetCmd.MarkFlagRequired("id")
+	instanceConfigSetCmd.Flags().StringP("id", "", "", "Instance ID or name (required unless --tag or --id-file is given)")
+	instanceConfigSetCmd.Flags().StringSlice("tag", nil, "Apply to all instances with this tag (can be repeated; instances need all tags)")
+	instanceConfigSetCmd.Flags().Bool("force", false, "Set a value outside the safe range of a setting, with a warning")
+	instanceConfigSetCmd.Flags().Bool("yes", false, "Apply to all matching instances without refusing when more than one matches")
+	addIDFileFlag(instanceConfigSetCmd)
+	addFailFastFlag(instanceConfigSetCmd)
+	addDryRunFlag(instanceConfigSetCmd)
 
 	instanceConfigCmd.AddCommand(instanceConfigListCmd)
 	instanceConfigCmd.AddCommand(instanceConfigGetCmd)
 	instanceConfigCmd.AddCommand(instanceConfigSetCmd)
+	instanceConfigCmd.AddCommand(instanceConfigValidateCmd)
 }
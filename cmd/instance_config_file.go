@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"cloudamqp-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var instanceConfigExportCmd = &cobra.Command{
+	Use:   "export --id <instance_id> [--file <file>]",
+	Short: "Export the configuration to a YAML or JSON file",
+	Long: `Write the configured broker settings of the instance to a file that
+'cloudamqp instance config import' reads back.
+
+The format follows the file extension: JSON for .json, YAML otherwise.
+Without --file the settings are written to stdout as YAML, or as JSON with
+-o json.`,
+	Example: `  cloudamqp instance config export --id 1234 --file config.yaml
+  cloudamqp instance config export --id 1234 --file config.json
+  cloudamqp instance config export --id 1234 -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+		file, _ := cmd.Flags().GetString("file")
+		asJSON := strings.EqualFold(filepath.Ext(file), ".json")
+		if file == "" {
+			var err error
+			if asJSON, err = documentAsJSON(cmd); err != nil {
+				return err
+			}
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		backend, err := configBackend(c, idFlag)
+		if err != nil {
+			return err
+		}
+
+		config, err := getInstanceConfig(c, backend, idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to get configuration: %w", err)
+		}
+
+		data, err := marshalConfigFile(config, asJSON)
+		if err != nil {
+			return err
+		}
+
+		if file == "" {
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		}
+		if err := os.WriteFile(file, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write config file: %w", err)
+		}
+		printStatus(cmd, "Exported %d settings to %s.", len(config), file)
+		return nil
+	},
+}
+
+// marshalConfigFile formats settings for a config file, as JSON or YAML.
+// Both formats sort the keys, so exports of the same settings are identical
+// and diff cleanly under version control.
+func marshalConfigFile(config map[string]any, asJSON bool) ([]byte, error) {
+	if config == nil {
+		config = map[string]any{}
+	}
+	if !asJSON {
+		data, err := output.MarshalSortedYAML(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format configuration: %w", err)
+		}
+		return data, nil
+	}
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format configuration: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+var instanceConfigImportCmd = &cobra.Command{
+	Use:   "import --id <instance_id> --file <file>",
+	Short: "Import the configuration from a YAML or JSON file",
+	Long: `Update the broker settings of the instance from a file written by
+'cloudamqp instance config export', or any flat YAML or JSON map of settings.
+
+By default the settings are merged: only settings in the file whose value
+differs from the instance are sent, and other settings are left alone. With
+--replace the whole file is sent, and settings configured on the instance
+but missing from the file are set to their default value. Settings without a
+known default can't be reset this way and are left as they are, with a
+warning.
+
+The changes are printed to stderr first. Confirm them interactively, or pass --yes to
+import without asking. Use --dry-run to print the changes and the request that would be sent
+instead. Values
+outside the safe range of a setting are refused unless --force is given, as
+with 'config set'.`,
+	Example: `  cloudamqp instance config import --id 1234 --file config.yaml
+  cloudamqp instance config import --id 1234 --file config.yaml --dry-run
+  cloudamqp instance config import --id 1234 --file config.json --replace --yes`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+		file, _ := cmd.Flags().GetString("file")
+		replace, _ := cmd.Flags().GetBool("replace")
+		force, _ := cmd.Flags().GetBool("force")
+
+		desired, err := readConfigFile(file)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		backend, err := configBackend(c, idFlag)
+		if err != nil {
+			return err
+		}
+		if err := checkConfigForBackend(backend, desired); err != nil {
+			return err
+		}
+		if err := checkConfigValuesSafety(desired, force, "import"); err != nil {
+			return err
+		}
+
+		current, err := getInstanceConfig(c, backend, idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to get configuration: %w", err)
+		}
+
+		body, changes, kept := configImportChanges(backend, current, desired, replace)
+		for _, key := range kept {
+			logWarn("%s has no known default, so it is left at %s", key, formatConfigValue(current[key]))
+		}
+		if len(changes) == 0 {
+			printStatus(cmd, "Configuration of instance %s is up to date.", idFlag)
+			return nil
+		}
+
+		printStatus(cmd, "Changes to import to instance %s:", idFlag)
+		for _, change := range changes {
+			printStatus(cmd, "  %s", change)
+		}
+
+		if isDryRun(cmd) {
+			endpoint, err := configEndpoint(backend, idFlag)
+			if err != nil {
+				return err
+			}
+			return printDryRun(cmd, "PUT", endpoint, body)
+		}
+
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			ok, err := confirm(cmd, "Import these changes?", "--yes")
+			if err != nil {
+				return err
+			}
+			if !ok {
+				printStatus(cmd, "Import cancelled.")
+				return nil
+			}
+		}
+
+		if err := updateInstanceConfig(c, backend, idFlag, body); err != nil {
+			return fmt.Errorf("failed to update configuration: %w", err)
+		}
+		printStatus(cmd, "Imported %d change(s) to instance %s.", len(changes), idFlag)
+		return nil
+	},
+}
+
+// configImportChanges returns the request body that brings the current
+// settings in line with desired, and a description of each change, sorted
+// by setting. When merging, only the settings that differ are sent. With
+// replace, every desired setting is sent and settings missing from desired
+// are set to their default from the backend's schema; those without a known
+// default are returned in kept and not sent.
+func configImportChanges(backend string, current, desired map[string]any, replace bool) (body map[string]any, changes, kept []string) {
+	body = map[string]any{}
+	for _, key := range sortedKeys(desired) {
+		want := desired[key]
+		have, ok := current[key]
+		if replace {
+			body[key] = want
+		}
+		if ok && sameConfigValue(have, want) {
+			continue
+		}
+		body[key] = want
+		changes = append(changes, fmt.Sprintf("~ %s: %s -> %v", key, formatSpecValue(have, ok), want))
+	}
+	if replace {
+		schema := configSchema(backend)
+		for _, key := range sortedKeys(current) {
+			// Null is how the API reports a setting that is already unset
+			if _, ok := desired[key]; ok || current[key] == nil {
+				continue
+			}
+			setting, ok := lookupConfigSetting(schema, key)
+			if !ok {
+				kept = append(kept, key)
+				continue
+			}
+			if sameConfigValue(current[key], setting.Default) {
+				continue
+			}
+			body[key] = setting.Default
+			changes = append(changes, fmt.Sprintf("- %s: %v -> %v (default)", key, current[key], setting.Default))
+		}
+	}
+	return body, changes, kept
+}
+
+// sameConfigValue reports whether two setting values are equal once both
+// are read back from JSON, so 60 from a YAML file matches 60.0 from the API
+// while "60" doesn't.
+func sameConfigValue(a, b any) bool {
+	normalize := func(v any) any {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var out any
+		if err := json.Unmarshal(data, &out); err != nil {
+			return v
+		}
+		return out
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+func init() {
+	instanceConfigExportCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceConfigExportCmd.MarkFlagRequired("id")
+	instanceConfigExportCmd.Flags().String("file", "", "File to write, as JSON for .json and YAML otherwise (default: stdout)")
+
+	instanceConfigImportCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceConfigImportCmd.MarkFlagRequired("id")
+	instanceConfigImportCmd.Flags().String("file", "", "YAML or JSON file with configuration settings (required)")
+	instanceConfigImportCmd.MarkFlagRequired("file")
+	instanceConfigImportCmd.Flags().Bool("replace", false, "Send the whole file and set settings missing from it to their defaults")
+	instanceConfigImportCmd.Flags().Bool("yes", false, "Import without asking for confirmation")
+	instanceConfigImportCmd.Flags().Bool("force", false, "Import values outside the safe range of a setting, with a warning")
+	addDryRunFlag(instanceConfigImportCmd)
+
+	instanceConfigCmd.AddCommand(instanceConfigExportCmd)
+	instanceConfigCmd.AddCommand(instanceConfigImportCmd)
+}
@@ -0,0 +1,658 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRowsWithDefaults(t *testing.T) {
+	rows := configRowsWithDefaults(client.BackendRabbitMQ, map[string]interface{}{
+		"rabbit.heartbeat":        60,
+		"custom.setting":          "x",
+		"rabbit.consumer_timeout": nil,
+		"custom.unset":            nil,
+	})
+
+	bySetting := make(map[string][]string, len(rows))
+	for _, row := range rows {
+		bySetting[row[0]] = row
+	}
+
+	assert.Len(t, rows, len(rabbitMQConfigSchema)+1)
+	assert.Equal(t, []string{"rabbit.heartbeat", "60", "configured"}, bySetting["rabbit.heartbeat"])
+	assert.Equal(t, []string{"custom.setting", "x", "configured"}, bySetting["custom.setting"])
+	assert.Equal(t, []string{"rabbit.max_message_size", "134217728", "default"}, bySetting["rabbit.max_message_size"])
+	assert.Equal(t, "default", bySetting["rabbit.consumer_timeout"][2], "a null value keeps the default")
+	assert.NotContains(t, bySetting["rabbit.consumer_timeout"][1], "nil")
+	assert.NotContains(t, bySetting, "custom.unset", "unset settings without a default are left out")
+	assert.Equal(t, "custom.setting", rows[0][0], "rows should be sorted by setting")
+}
+
+func TestValidateConfigValues(t *testing.T) {
+	problems := validateConfigValues(client.BackendRabbitMQ, map[string]interface{}{
+		"rabbit.heartbeat":                  "120",
+		"rabbit.vm_memory_high_watermark":   1,
+		"rabbit.hearbeat":                   60,
+		"rabbit.cluster_partition_handling": "pause_all",
+		"ssl_options.fail_if_no_peer_cert":  true,
+		"rabbit.max_message_size":           1.5,
+	})
+
+	var messages []string
+	for _, p := range problems {
+		messages = append(messages, p.Error())
+	}
+	assert.Equal(t, []string{
+		"rabbit.cluster_partition_handling: invalid value 'pause_all', expected one of: autoheal, pause_minority, ignore",
+		"unknown setting 'rabbit.hearbeat'; did you mean 'rabbit.heartbeat'?",
+		`rabbit.heartbeat: expected int, got "120"`,
+		"rabbit.max_message_size: expected int, got 1.5",
+	}, messages)
+}
+
+func TestValidateConfigValues_LavinMQ(t *testing.T) {
+	problems := validateConfigValues(client.BackendLavinMQ, map[string]interface{}{
+		"amqp.heartbeat":   60,
+		"main.log_level":   "warning",
+		"rabbit.heartbeat": 60,
+	})
+
+	var messages []string
+	for _, p := range problems {
+		messages = append(messages, p.Error())
+	}
+	assert.Equal(t, []string{
+		"main.log_level: invalid value 'warning', expected one of: debug, info, warn, error, fatal, none",
+		"'rabbit.heartbeat' is a RabbitMQ setting, but the instance runs LavinMQ",
+	}, messages)
+}
+
+func TestPlanBackend(t *testing.T) {
+	plans := []client.Plan{
+		{Name: "bunny-1", Backend: client.BackendRabbitMQ},
+		{Name: "lemur", Backend: client.BackendLavinMQ},
+	}
+	assert.Equal(t, client.BackendLavinMQ, planBackend(plans, "lemur"))
+	assert.Equal(t, client.BackendRabbitMQ, planBackend(plans, "bunny-1"))
+	assert.Equal(t, client.BackendRabbitMQ, planBackend(plans, "retired-plan"))
+}
+
+func TestReadConfigFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("rabbit.heartbeat: 60\nrabbit.vm_memory_high_watermark: 0.6\n"), 0o600))
+
+	config, err := readConfigFile(file)
+	require.NoError(t, err)
+	assert.Empty(t, validateConfigValues(client.BackendRabbitMQ, config))
+}
+
+func TestInstanceConfigValidateCmd_BackendFromInstance(t *testing.T) {
+	useFakeClient(t, &fakeClient{
+		instances: map[int]*client.Instance{1: {ID: 1, Name: "events", Backend: client.BackendLavinMQ}},
+	})
+	file := filepath.Join(t.TempDir(), "lavinmq.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("amqp.heartbeat: 60\n"), 0o600))
+
+	cmd := instanceConfigValidateCmd
+	defer resetFlags(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--file", file, "--id", "events"}))
+	assert.NoError(t, cmd.RunE(cmd, []string{}), "LavinMQ settings are valid for a LavinMQ instance")
+
+	resetFlags(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--file", file}))
+	assert.ErrorContains(t, cmd.RunE(cmd, []string{}), "1 of 1 settings are invalid", "without --id the settings are checked as RabbitMQ ones")
+}
+
+func TestCoerceValue(t *testing.T) {
+	assert.Equal(t, true, coerceValue("True"))
+	assert.Equal(t, false, coerceValue("false"))
+	assert.Nil(t, coerceValue("null"))
+	assert.Equal(t, 120, coerceValue("120"))
+	assert.Equal(t, 0.8, coerceValue("0.8"))
+	assert.Equal(t, "autoheal", coerceValue("autoheal"))
+}
+
+// configClient records config updates and fails them for failIDs.
+type configClient struct {
+	fakeClient
+	mu      sync.Mutex
+	updated map[string]map[string]interface{}
+	failIDs map[string]bool
+	current map[string]interface{}
+}
+
+func (f *configClient) GetRabbitMQConfig(instanceID string) (map[string]interface{}, error) {
+	return f.current, nil
+}
+
+func (f *configClient) UpdateRabbitMQConfig(instanceID string, config map[string]interface{}) error {
+	if f.failIDs[instanceID] {
+		return fmt.Errorf("API error (400): invalid value")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updated[instanceID] = config
+	return nil
+}
+
+func TestInstanceConfigSetCmd_ByTag(t *testing.T) {
+	newFake := func() *configClient {
+		return &configClient{
+			fakeClient: fakeClient{instances: map[int]*client.Instance{
+				1: {ID: 1, Name: "orders", Tags: []string{"prod", "eu"}},
+				2: {ID: 2, Name: "billing", Tags: []string{"prod"}},
+				3: {ID: 3, Name: "staging", Tags: []string{"staging"}},
+			}, plans: []client.Plan{{Name: "bunny-1", Backend: client.BackendRabbitMQ}}},
+			updated: map[string]map[string]interface{}{},
+		}
+	}
+
+	run := func(t *testing.T, flags map[string]string) (string, error) {
+		t.Helper()
+		cmd := instanceConfigSetCmd
+		cmd.InheritedFlags() // merge --output from root, as Execute would
+		for name, value := range flags {
+			require.NoError(t, cmd.Flags().Set(name, value))
+		}
+		defer resetFlags(cmd)
+
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		defer cmd.SetOut(nil)
+		err := cmd.RunE(cmd, []string{"rabbit.heartbeat", "120"})
+		return out.String(), err
+	}
+
+	t.Run("requires --yes for several instances", func(t *testing.T) {
+		fake := newFake()
+		useFakeClient(t, fake)
+
+		_, err := run(t, map[string]string{"tag": "prod"})
+		assert.ErrorContains(t, err, "2 instances match: orders (1), billing (2)")
+		assert.Empty(t, fake.updated)
+	})
+
+	t.Run("applies to all matches", func(t *testing.T) {
+		fake := newFake()
+		useFakeClient(t, fake)
+
+		out, err := run(t, map[string]string{"tag": "prod", "yes": "true"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"rabbit.heartbeat": 120}, fake.updated["1"])
+		assert.Equal(t, map[string]interface{}{"rabbit.heartbeat": 120}, fake.updated["2"])
+		assert.NotContains(t, fake.updated, "3")
+		assert.Contains(t, out, "orders")
+		assert.Contains(t, out, "ok")
+	})
+
+	t.Run("reports failures", func(t *testing.T) {
+		fake := newFake()
+		fake.failIDs = map[string]bool{"2": true}
+		useFakeClient(t, fake)
+
+		out, err := run(t, map[string]string{"tag": "prod", "yes": "true"})
+		assert.ErrorContains(t, err, "1 of 2 instances failed")
+		assert.Contains(t, out, "error: API error (400): invalid value")
+		assert.Contains(t, fake.updated, "1")
+	})
+
+	t.Run("adds instances from --id-file", func(t *testing.T) {
+		fake := newFake()
+		useFakeClient(t, fake)
+
+		_, err := run(t, map[string]string{"tag": "prod", "id-file": writeIDFile(t, "3\n1\n"), "yes": "true"})
+		require.NoError(t, err)
+		assert.Len(t, fake.updated, 3)
+	})
+
+	t.Run("single match needs no --yes", func(t *testing.T) {
+		fake := newFake()
+		useFakeClient(t, fake)
+
+		_, err := run(t, map[string]string{"tag": "prod,eu"})
+		require.NoError(t, err)
+		assert.Len(t, fake.updated, 1)
+	})
+}
+
+func TestInstanceConfigSetCmd_LavinMQ(t *testing.T) {
+	newFake := func() *configClient {
+		return &configClient{
+			fakeClient: fakeClient{
+				instances: map[int]*client.Instance{1: {ID: 1, Name: "events", Plan: "lemur"}},
+				plans:     []client.Plan{{Name: "lemur", Backend: client.BackendLavinMQ}},
+			},
+			updated: map[string]map[string]interface{}{},
+		}
+	}
+
+	run := func(t *testing.T, args ...string) error {
+		t.Helper()
+		cmd := instanceConfigSetCmd
+		cmd.InheritedFlags()
+		require.NoError(t, cmd.Flags().Set("id", "1"))
+		defer resetFlags(cmd)
+		return cmd.RunE(cmd, args)
+	}
+
+	t.Run("is refused", func(t *testing.T) {
+		fake := newFake()
+		useFakeClient(t, fake)
+
+		err := run(t, "amqp.heartbeat", "60")
+		assert.ErrorIs(t, err, errLavinMQConfig)
+		assert.Empty(t, fake.updated)
+	})
+
+	t.Run("backend from the instance without listing plans", func(t *testing.T) {
+		fake := newFake()
+		fake.instances[1].Backend = client.BackendLavinMQ
+		fake.plans = nil
+		useFakeClient(t, fake)
+
+		assert.ErrorIs(t, run(t, "amqp.heartbeat", "60"), errLavinMQConfig)
+		assert.Empty(t, fake.updated)
+	})
+
+	t.Run("rejects RabbitMQ settings", func(t *testing.T) {
+		fake := newFake()
+		useFakeClient(t, fake)
+
+		err := run(t, "rabbit.heartbeat", "60")
+		assert.EqualError(t, err, "'rabbit.heartbeat' is a RabbitMQ setting, but the instance runs LavinMQ")
+		assert.Empty(t, fake.updated)
+	})
+}
+
+func TestGetByPath(t *testing.T) {
+	config := map[string]interface{}{
+		"rabbit.heartbeat": 120,
+		"rabbit.tcp_listen_options": map[string]interface{}{
+			"backlog": 128,
+			"linger":  map[string]interface{}{"timeout": 0},
+		},
+		"mqtt": map[string]interface{}{
+			"tcp_listen_options": map[string]interface{}{"backlog": 64},
+		},
+		"ssl_options.verify": "verify_none",
+	}
+
+	tests := []struct {
+		path  string
+		want  interface{}
+		found bool
+	}{
+		{path: "rabbit.heartbeat", want: 120, found: true},
+		{path: "ssl_options.verify", want: "verify_none", found: true},
+		{path: "rabbit.tcp_listen_options.backlog", want: 128, found: true},
+		{path: "rabbit.tcp_listen_options.linger.timeout", want: 0, found: true},
+		{path: "mqtt.tcp_listen_options.backlog", want: 64, found: true},
+		{path: "rabbit.tcp_listen_options", want: config["rabbit.tcp_listen_options"], found: true},
+		{path: "rabbit.tcp_listen_options.nodelay"},
+		{path: "rabbit.heartbeat.value"},
+		{path: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, found := getByPath(config, tt.path)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+}
+
+func TestSetByPath(t *testing.T) {
+	t.Run("follows existing nested maps", func(t *testing.T) {
+		config := map[string]interface{}{
+			"rabbit.tcp_listen_options": map[string]interface{}{"backlog": 128, "nodelay": true},
+		}
+		setByPath(config, "rabbit.tcp_listen_options.backlog", 256)
+		assert.Equal(t, map[string]interface{}{
+			"rabbit.tcp_listen_options": map[string]interface{}{"backlog": 256, "nodelay": true},
+		}, config)
+	})
+
+	t.Run("replaces an existing dotted key", func(t *testing.T) {
+		config := map[string]interface{}{"rabbit.heartbeat": 120}
+		setByPath(config, "rabbit.heartbeat", 60)
+		assert.Equal(t, map[string]interface{}{"rabbit.heartbeat": 60}, config)
+	})
+
+	t.Run("creates intermediate maps", func(t *testing.T) {
+		config := map[string]interface{}{"other": 1}
+		setByPath(config, "linger.timeout.seconds", 5)
+		assert.Equal(t, map[string]interface{}{
+			"other":  1,
+			"linger": map[string]interface{}{"timeout": map[string]interface{}{"seconds": 5}},
+		}, config)
+	})
+
+	t.Run("round trips with getByPath", func(t *testing.T) {
+		config := map[string]interface{}{}
+		setByPath(config, "a.b.c", "x")
+		value, found := getByPath(config, "a.b.c")
+		assert.True(t, found)
+		assert.Equal(t, "x", value)
+	})
+}
+
+func TestConfigUpdate(t *testing.T) {
+	current := map[string]interface{}{
+		"rabbit.heartbeat":          120,
+		"rabbit.tcp_listen_options": map[string]interface{}{"backlog": 128, "nodelay": true},
+	}
+
+	body := configUpdate(current, "rabbit.tcp_listen_options.backlog", 256)
+	assert.Equal(t, map[string]interface{}{
+		"rabbit.tcp_listen_options": map[string]interface{}{"backlog": 256, "nodelay": true},
+	}, body)
+	assert.Equal(t, 128, current["rabbit.tcp_listen_options"].(map[string]interface{})["backlog"], "the current config must not change")
+
+	assert.Equal(t, map[string]interface{}{"rabbit.channel_max": 64}, configUpdate(current, "rabbit.channel_max", 64))
+}
+
+func TestInstanceConfigSetCmd_NestedKey(t *testing.T) {
+	fake := &configClient{
+		fakeClient: fakeClient{instances: map[int]*client.Instance{1: {ID: 1, Name: "orders", Plan: "bunny-1"}}},
+		updated:    map[string]map[string]interface{}{},
+		current: map[string]interface{}{
+			"rabbit.tcp_listen_options": map[string]interface{}{"backlog": float64(128), "nodelay": true},
+		},
+	}
+	useFakeClient(t, fake)
+
+	cmd := instanceConfigSetCmd
+	cmd.InheritedFlags()
+	require.NoError(t, cmd.Flags().Set("id", "1"))
+	defer resetFlags(cmd)
+
+	require.NoError(t, cmd.RunE(cmd, []string{"rabbit.tcp_listen_options.backlog", "256"}))
+	assert.Equal(t, map[string]interface{}{
+		"rabbit.tcp_listen_options": map[string]interface{}{"backlog": 256, "nodelay": true},
+	}, fake.updated["1"])
+}
+
+func TestInstanceConfigListCmd_Grep(t *testing.T) {
+	fake := &configClient{
+		fakeClient: fakeClient{instances: map[int]*client.Instance{1: {ID: 1, Name: "orders", Plan: "bunny-1"}}},
+		current: map[string]interface{}{
+			"rabbit.heartbeat":          float64(120),
+			"rabbit.channel_max":        float64(1024),
+			"rabbit.consumer_timeout":   float64(7200000),
+			"rabbit.max_message_size":   float64(134217728),
+			"mqtt.exchange":             "amq.topic",
+			"rabbit.connection_max":     float64(5000),
+			"rabbit.log.exchange.level": "error",
+		},
+	}
+	useFakeClient(t, fake)
+
+	tests := []struct {
+		name  string
+		flags map[string]string
+		want  []string
+	}{
+		{"substring", map[string]string{"grep": "MAX"}, []string{"rabbit.channel_max", "rabbit.connection_max", "rabbit.max_message_size"}},
+		{"regex", map[string]string{"grep": `^rabbit\.[a-z]+_max$`, "regex": "true"}, []string{"rabbit.channel_max", "rabbit.connection_max"}},
+		{"regex special chars are literal without --regex", map[string]string{"grep": "exchange"}, []string{"mqtt.exchange", "rabbit.log.exchange.level"}},
+		{"all", map[string]string{"grep": "heartbeat", "all": "true"}, []string{"rabbit.heartbeat"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := instanceConfigListCmd
+			cmd.InheritedFlags()
+			rootCmd.PersistentFlags().Set("output", "json")
+			defer rootCmd.PersistentFlags().Set("output", "table")
+			require.NoError(t, cmd.Flags().Set("id", "1"))
+			for name, value := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(name, value))
+			}
+			defer resetFlags(cmd)
+
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			defer cmd.SetOut(nil)
+			require.NoError(t, cmd.RunE(cmd, []string{}))
+
+			var records []map[string]string
+			require.NoError(t, json.Unmarshal(out.Bytes(), &records))
+			var keys []string
+			for _, record := range records {
+				key := record["key"]
+				if key == "" {
+					key = record["setting"]
+				}
+				keys = append(keys, key)
+			}
+			assert.ElementsMatch(t, tt.want, keys)
+		})
+	}
+}
+
+func TestInstanceConfigListCmd_GrepInvalid(t *testing.T) {
+	cmd := instanceConfigListCmd
+	require.NoError(t, cmd.Flags().Set("id", "1"))
+	defer resetFlags(cmd)
+
+	require.NoError(t, cmd.Flags().Set("regex", "true"))
+	assert.ErrorContains(t, cmd.RunE(cmd, []string{}), "--regex needs a pattern")
+
+	require.NoError(t, cmd.Flags().Set("grep", "rabbit.("))
+	assert.ErrorContains(t, cmd.RunE(cmd, []string{}), "invalid regular expression")
+}
+
+func TestInstanceConfigGetCmd_Default(t *testing.T) {
+	fake := &configClient{
+		fakeClient: fakeClient{instances: map[int]*client.Instance{1: {ID: 1, Name: "orders", Plan: "bunny-1"}}},
+		current: map[string]interface{}{
+			"rabbit.heartbeat":        float64(120),
+			"rabbit.max_message_size": float64(134217728),
+			"rabbit.consumer_timeout": nil,
+		},
+	}
+	useFakeClient(t, fake)
+
+	cmd := instanceConfigGetCmd
+	cmd.InheritedFlags()
+	defer resetFlags(cmd)
+	require.NoError(t, cmd.Flags().Set("id", "1"))
+	require.NoError(t, cmd.Flags().Set("default", "60"))
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	defer cmd.SetOut(nil)
+
+	require.NoError(t, cmd.RunE(cmd, []string{"rabbit.heartbeat"}))
+	assert.Equal(t, "120\n", out.String(), "a configured value wins over the default")
+
+	out.Reset()
+	require.NoError(t, cmd.RunE(cmd, []string{"rabbit.channel_max"}))
+	assert.Equal(t, "60\n", out.String())
+
+	out.Reset()
+	require.NoError(t, cmd.RunE(cmd, []string{"rabbit.consumer_timeout"}))
+	assert.Equal(t, "60\n", out.String(), "an unset (null) setting gets the default")
+
+	out.Reset()
+	require.NoError(t, cmd.RunE(cmd, []string{"rabbit.max_message_size"}))
+	assert.Equal(t, "134217728\n", out.String(), "whole numbers are printed as integers")
+
+	// Without --default the setting name is printed with its value, and a
+	// missing setting prints nothing to stdout
+	resetFlags(cmd)
+	require.NoError(t, cmd.Flags().Set("id", "1"))
+	out.Reset()
+	require.NoError(t, cmd.RunE(cmd, []string{"rabbit.heartbeat"}))
+	assert.Equal(t, "rabbit.heartbeat: 120\n", out.String())
+
+	out.Reset()
+	require.NoError(t, cmd.RunE(cmd, []string{"rabbit.channel_max"}))
+	assert.Empty(t, out.String())
+}
+
+func TestCheckConfigSafety(t *testing.T) {
+	tests := []struct {
+		key   string
+		value any
+		err   string
+	}{
+		{"rabbit.vm_memory_high_watermark", 0.81, ""},
+		{"rabbit.vm_memory_high_watermark", 0.9, ""},
+		{"rabbit.vm_memory_high_watermark", 1.5, "outside the safe range 0.1 to 0.9"},
+		{"rabbit.vm_memory_high_watermark", 1, "outside the safe range 0.1 to 0.9"},
+		{"rabbit.vm_memory_high_watermark", 0.05, "outside the safe range 0.1 to 0.9"},
+		{"rabbit.disk_free_limit", 10000000, "below the safe minimum of 50000000"},
+		{"rabbit.disk_free_limit", 2000000000, ""},
+		{"rabbit.disk_free_limit", "2GB", ""},
+		{"rabbit.consumer_timeout", 1000, "below the safe minimum"},
+		{"amqp.frame_max", 1024, "below the safe minimum of 4096"},
+		{"rabbit.heartbeat", 0, ""},
+		{"rabbit.unknown", 1e12, ""},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s=%v", tt.key, tt.value), func(t *testing.T) {
+			err := checkConfigSafety(tt.key, tt.value)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestInstanceConfigSetCmd_UnsafeValue(t *testing.T) {
+	fake := &configClient{
+		fakeClient: fakeClient{instances: map[int]*client.Instance{1: {ID: 1, Name: "orders", Plan: "bunny-1"}}},
+		updated:    map[string]map[string]interface{}{},
+	}
+	useFakeClient(t, fake)
+
+	cmd := instanceConfigSetCmd
+	cmd.InheritedFlags()
+	require.NoError(t, cmd.Flags().Set("id", "1"))
+	defer resetFlags(cmd)
+
+	err := cmd.RunE(cmd, []string{"rabbit.vm_memory_high_watermark", "1.5"})
+	assert.ErrorContains(t, err, "Use --force to set it anyway")
+	assert.Empty(t, fake.updated)
+
+	require.NoError(t, cmd.Flags().Set("force", "true"))
+	require.NoError(t, cmd.RunE(cmd, []string{"rabbit.vm_memory_high_watermark", "1.5"}))
+	assert.Equal(t, map[string]interface{}{"rabbit.vm_memory_high_watermark": 1.5}, fake.updated["1"])
+}
+
+func TestConfigImportChanges(t *testing.T) {
+	current := map[string]interface{}{
+		"rabbit.heartbeat": 60.0, "rabbit.channel_max": 1024.0, "rabbit.connection_max": 500.0, "rabbit.log.default.level": "info",
+		"rabbit.max_message_size": nil, "custom.unset": nil,
+	}
+	desired := map[string]interface{}{"rabbit.heartbeat": 120, "rabbit.channel_max": 1024, "rabbit.consumer_timeout": 7200000}
+
+	body, changes, kept := configImportChanges(client.BackendRabbitMQ, current, desired, false)
+	assert.Equal(t, map[string]interface{}{"rabbit.heartbeat": 120, "rabbit.consumer_timeout": 7200000}, body)
+	assert.Equal(t, []string{
+		"~ rabbit.consumer_timeout: (unset) -> 7200000",
+		"~ rabbit.heartbeat: 60 -> 120",
+	}, changes)
+	assert.Empty(t, kept)
+
+	body, changes, kept = configImportChanges(client.BackendRabbitMQ, current, desired, true)
+	assert.Equal(t, map[string]interface{}{
+		"rabbit.heartbeat": 120, "rabbit.channel_max": 1024, "rabbit.consumer_timeout": 7200000,
+		"rabbit.connection_max": -1,
+	}, body, "missing settings are set to their default, not null; unset (null) ones are left alone")
+	assert.Equal(t, "- rabbit.connection_max: 500 -> -1 (default)", changes[len(changes)-1])
+	assert.Equal(t, []string{"rabbit.log.default.level"}, kept, "settings without a known default are left alone")
+}
+
+func TestSameConfigValue(t *testing.T) {
+	assert.True(t, sameConfigValue(60.0, 60))
+	assert.True(t, sameConfigValue([]any{"a"}, []string{"a"}))
+	assert.False(t, sameConfigValue("60", 60), "a string and a number differ even if they print the same")
+	assert.False(t, sameConfigValue(nil, "<nil>"))
+}
+
+func TestInstanceConfigExportImport_RoundTrip(t *testing.T) {
+	for _, name := range []string{"config.yaml", "config.json"} {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), name)
+			fake := &configClient{
+				fakeClient: fakeClient{instances: map[int]*client.Instance{1: {ID: 1, Name: "orders", Plan: "bunny-1"}}},
+				updated:    map[string]map[string]interface{}{},
+				current:    map[string]interface{}{"rabbit.heartbeat": 120.0, "rabbit.channel_max": 1024.0},
+			}
+			useFakeClient(t, fake)
+
+			export := instanceConfigExportCmd
+			export.InheritedFlags()
+			defer resetFlags(export)
+			require.NoError(t, export.ParseFlags([]string{"--id", "1", "--file", file}))
+			export.SetErr(&bytes.Buffer{})
+			defer export.SetErr(nil)
+			require.NoError(t, export.RunE(export, []string{}))
+
+			config, err := readConfigFile(file)
+			require.NoError(t, err)
+			assert.Len(t, config, 2)
+
+			// Import into an instance whose heartbeat has drifted
+			fake.current = map[string]interface{}{"rabbit.heartbeat": 60.0, "rabbit.channel_max": 1024.0}
+			imp := instanceConfigImportCmd
+			imp.InheritedFlags()
+			defer resetFlags(imp)
+			var stderr bytes.Buffer
+			imp.SetOut(&bytes.Buffer{})
+			imp.SetErr(&stderr)
+			imp.SetIn(&bytes.Buffer{})
+			defer imp.SetOut(nil)
+			defer imp.SetErr(nil)
+			defer imp.SetIn(nil)
+
+			require.NoError(t, imp.ParseFlags([]string{"--id", "1", "--file", file}))
+			assert.ErrorContains(t, imp.RunE(imp, []string{}), "Use --yes to proceed non-interactively")
+			assert.Contains(t, stderr.String(), "~ rabbit.heartbeat: 60 -> 120")
+			assert.Empty(t, fake.updated)
+
+			require.NoError(t, imp.Flags().Set("yes", "true"))
+			require.NoError(t, imp.RunE(imp, []string{}))
+			assert.Equal(t, map[string]interface{}{"rabbit.heartbeat": 120}, fake.updated["1"])
+		})
+	}
+}
+
+func TestInstanceConfigImportCmd_DryRun(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("rabbit.heartbeat: 30\n"), 0o644))
+	fake := &configClient{
+		fakeClient: fakeClient{instances: map[int]*client.Instance{1: {ID: 1, Name: "orders", Plan: "bunny-1"}}},
+		updated:    map[string]map[string]interface{}{},
+		current:    map[string]interface{}{"rabbit.heartbeat": 60.0, "rabbit.channel_max": 1024.0},
+	}
+	useFakeClient(t, fake)
+
+	cmd := instanceConfigImportCmd
+	cmd.InheritedFlags()
+	defer resetFlags(cmd)
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	defer cmd.SetOut(nil)
+	defer cmd.SetErr(nil)
+
+	require.NoError(t, cmd.ParseFlags([]string{"--id", "1", "--file", file, "--replace", "--dry-run"}))
+	require.NoError(t, cmd.RunE(cmd, []string{}))
+	assert.Equal(t, "Changes to import to instance 1:\n  ~ rabbit.heartbeat: 60 -> 30\n  - rabbit.channel_max: 1024 -> 0 (default)\n", stderr.String())
+	assert.Equal(t, "METHOD = PUT\nPATH = /instances/1/config\nBODY = {\"rabbit.channel_max\":0,\"rabbit.heartbeat\":30}\n", stdout.String())
+	assert.Empty(t, fake.updated)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var instanceConfigValidateCmd = &cobra.Command{
+	Use:   "validate --file <file>",
+	Short: "Validate a configuration file without applying it",
+	Long: `Check every setting in a YAML or JSON file of broker configuration
+against the known settings: the key must exist and the value must have the
+expected type (int, float, bool or string). All problems are reported at once.
+
+Settings are checked as RabbitMQ settings unless --backend lavinmq is given.
+With --id the broker is taken from that instance instead; this is the only
+API call made, and the file is never sent. The command exits non-zero if any
+setting is invalid.`,
+	Example: `  cloudamqp instance config validate --file config.yaml
+  cloudamqp instance config validate --file lavinmq.yaml --backend lavinmq
+  cloudamqp instance config validate --file config.yaml --id 1234`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		backend, _ := cmd.Flags().GetString("backend")
+		if cmd.Flags().Changed("id") {
+			idFlag, err := instanceIDFlag(cmd)
+			if err != nil {
+				return err
+			}
+			apiKey, err := getAPIKey()
+			if err != nil {
+				return fmt.Errorf("failed to get API key: %w", err)
+			}
+			backend, err = configBackend(newAPIClient(apiKey), idFlag)
+			if err != nil {
+				return err
+			}
+		}
+		if backend != client.BackendRabbitMQ && backend != client.BackendLavinMQ {
+			return fmt.Errorf("invalid --backend %q: must be %s or %s", backend, client.BackendRabbitMQ, client.BackendLavinMQ)
+		}
+
+		config, err := readConfigFile(file)
+		if err != nil {
+			return err
+		}
+
+		problems := validateConfigValues(backend, config)
+		if len(problems) == 0 {
+			printStatus(cmd, "%s: %d settings are valid", file, len(config))
+			return nil
+		}
+
+		for _, problem := range problems {
+			logError("%v", problem)
+		}
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s: %d of %d settings are invalid", file, len(problems), len(config))
+	},
+}
+
+// readConfigFile reads a flat map of RabbitMQ settings from a YAML or JSON
+// file.
+func readConfigFile(file string) (map[string]any, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config map[string]any
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if len(config) == 0 {
+		return nil, fmt.Errorf("config file %s contains no settings", file)
+	}
+	return config, nil
+}
+
+func init() {
+	instanceConfigValidateCmd.Flags().String("file", "", "YAML or JSON file with configuration settings (required)")
+	instanceConfigValidateCmd.MarkFlagRequired("file")
+	instanceConfigValidateCmd.Flags().String("backend", client.BackendRabbitMQ, "Broker the settings are for: rabbitmq or lavinmq")
+	instanceConfigValidateCmd.Flags().String("id", "", "Instance ID or name to take the broker from, instead of --backend")
+	instanceConfigValidateCmd.MarkFlagsMutuallyExclusive("id", "backend")
+	instanceConfigValidateCmd.RegisterFlagCompletionFunc("id", completeInstances)
+	instanceConfigValidateCmd.RegisterFlagCompletionFunc("backend", cobra.FixedCompletions([]string{client.BackendRabbitMQ, client.BackendLavinMQ}, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"cloudamqp-cli/client"
@@ -22,11 +23,16 @@ var (
 	instanceCopySettings []string
 	instanceWait         bool
 	instanceWaitTimeout  string
+	instanceWaitConfig   bool
+	instanceQuietField   string
+	instanceIdemKey      string
+	instanceCopyConfig   string
 )
 
 var instanceCreateCmd = &cobra.Command{
-	Use:   "create",
-	Short: "Create a new CloudAMQP instance",
+	Use:     "create",
+	Aliases: []string{"new"},
+	Short:   "Create a new CloudAMQP instance",
 	Long: `Create a new CloudAMQP instance with the specified configuration.
 
 Required flags:
@@ -36,32 +42,46 @@ Required flags:
 
 Optional flags:
   --rmq-version: RabbitMQ version (e.g., 4.0.5) - only for rabbitmq plans
-  --tags: Instance tags (can be specified multiple times)
+  --tags: Instance tags, repeated or comma-separated (--tags a,b)
   --vpc-subnet: VPC subnet for dedicated VPC
-  --vpc-id: ID of existing VPC to add instance to
+  --vpc-id: ID of existing VPC to add instance to; it must be in --region
   --copy-from-id: Instance ID to copy settings from (dedicated instances only)
   --copy-settings: Settings to copy (alarms, metrics, logs, firewall, config)
+  --copy-config-from: Instance ID whose broker configuration is applied to the
+                      new instance once it is ready; implies --wait and
+                      --wait-config. Works across plans, but both instances
+                      must run the same broker (RabbitMQ or LavinMQ)
   --wait: Wait for instance to be ready before returning
-  --wait-timeout: Timeout for waiting (default: 15m)`,
+  --wait-timeout: Timeout for waiting (default: 15m)
+  --wait-config: With --wait, also wait until the management API answers, so
+                 configuration can be applied right away
+  --idempotency-key: Key sent as the Idempotency-Key header (default: random).
+                     Best effort only: the API is not known to deduplicate
+                     on it, so check for the instance before retrying a
+                     create whose outcome is unknown
+  --dry-run: Print the request that would be sent without executing it
+
+With --quiet only the new instance ID is printed, or the URL with
+--quiet-field url.`,
 	Example: `  cloudamqp instance create --name=my-instance --plan=bunny-1 --region=amazon-web-services::us-east-1
   cloudamqp instance create --name=my-instance --plan=bunny-1 --region=amazon-web-services::us-east-1 --tags=production --tags=web-app
   cloudamqp instance create --name=my-copy --plan=bunny-1 --region=amazon-web-services::us-east-1 --copy-from-id=12345 --copy-settings=metrics,firewall
-  cloudamqp instance create --name=my-instance --plan=bunny-1 --region=amazon-web-services::us-east-1 --wait`,
+  cloudamqp instance create --name=my-copy --plan=rabbit-1 --region=amazon-web-services::us-east-1 --copy-config-from=12345
+  cloudamqp instance create --name=my-instance --plan=bunny-1 --region=amazon-web-services::us-east-1 --wait
+  cloudamqp instance create --name=my-instance --plan=bunny-1 --region=amazon-web-services::us-east-1 --wait --wait-config
+  cloudamqp instance create --name=my-instance --plan=bunny-1 --region=amazon-web-services::us-east-1 --vpc-id=567
+  cloudamqp instance create --name=my-instance --plan=bunny-1 --region=amazon-web-services::us-east-1 --idempotency-key=deploy-42
+  cloudamqp instance create --name=my-instance --plan=bunny-1 --region=amazon-web-services::us-east-1 --dry-run
+  ID=$(cloudamqp instance create --name=my-instance --plan=bunny-1 --region=amazon-web-services::us-east-1 -q)`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
-		apiKey, err = getAPIKey()
-		if err != nil {
-			return fmt.Errorf("failed to get API key: %w", err)
-		}
-
-		c := client.New(apiKey, Version)
-
 		req := &client.InstanceCreateRequest{
 			Name:       instanceName,
 			Plan:       instancePlan,
 			Region:     instanceRegion,
 			RMQVersion: instanceRMQVersion,
-			Tags:       instanceTags,
+			Tags:       parseTags(instanceTags),
+
+			IdempotencyKey: strings.TrimSpace(instanceIdemKey),
 		}
 
 		if instanceVPCSubnet != "" {
@@ -87,48 +107,196 @@ Optional flags:
 			}
 		}
 
-		resp, err := c.CreateInstance(req)
-		if err != nil {
-			fmt.Printf("Error creating instance: %v\n", err)
-			return err
+		var copyConfigFrom int
+		if instanceCopyConfig != "" {
+			var err error
+			copyConfigFrom, err = strconv.Atoi(instanceCopyConfig)
+			if err != nil {
+				return fmt.Errorf("invalid copy-config-from: %v", err)
+			}
+		}
+
+		if instanceWaitConfig && !instanceWait {
+			return fmt.Errorf("--wait-config requires --wait")
 		}
 
-		if instanceWait {
-			timeout, err := time.ParseDuration(instanceWaitTimeout)
+		if instanceQuietField != "id" && instanceQuietField != "url" {
+			return fmt.Errorf("invalid --quiet-field %q: must be id or url", instanceQuietField)
+		}
+
+		// Parsed before creating, so a typo can't leave a new instance
+		// behind without its copied config
+		var timeout time.Duration
+		if instanceWait || copyConfigFrom != 0 {
+			var err error
+			timeout, err = time.ParseDuration(instanceWaitTimeout)
 			if err != nil {
 				return fmt.Errorf("invalid wait-timeout value: %v", err)
 			}
+		}
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "POST", "/instances", req.Body())
+		}
+
+		var err error
+		apiKey, err = getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		// Catch typos in the plan name early; if the plans can't be listed,
+		// leave validation to the server
+		plans, err := c.ListPlans("")
+		if err == nil {
+			if err := validatePlan(req.Plan, plans); err != nil {
+				return err
+			}
+		}
+
+		if req.VPCID != nil {
+			if err := validateVPCRegion(c, *req.VPCID, req.Region); err != nil {
+				return err
+			}
+		}
+
+		// Fetch the config to copy before creating, so a missing source or a
+		// broker mismatch fails without leaving a new instance behind
+		var copyConfig map[string]any
+		var copyBackend string
+		if copyConfigFrom != 0 {
+			copyBackend, copyConfig, err = sourceConfig(c, copyConfigFrom, planBackend(plans, req.Plan), req.Plan)
+			if err != nil {
+				return err
+			}
+		}
 
-			if err := waitForInstanceReady(c, resp.ID, timeout); err != nil {
+		resp, err := c.CreateInstance(req)
+		if err != nil {
+			return fmt.Errorf("failed to create instance: %w", err)
+		}
+
+		if instanceWait || copyConfigFrom != 0 {
+			deadline := time.Now().Add(timeout)
+			err = waitForInstanceReady(commandContext(cmd), c, resp.ID, timeout)
+			// Config pushed before the management API answers may be lost
+			if err == nil && (instanceWaitConfig || copyConfigFrom != 0) {
+				err = waitForInstanceHealthy(commandContext(cmd), c, resp.ID, time.Until(deadline))
+			}
+			if err != nil {
 				// Instance was created but failed to become ready
 				output, _ := json.MarshalIndent(resp, "", "  ")
-				fmt.Printf("Instance created but not ready:\n%s\n", string(output))
+				printStatus(cmd, "Instance created but not ready.")
+				fmt.Fprintln(cmd.OutOrStdout(), string(output))
+				if copyConfigFrom != 0 {
+					return fmt.Errorf("wait failed, config was not copied from instance %d: %w", copyConfigFrom, err)
+				}
 				return fmt.Errorf("wait failed: %w", err)
 			}
 		}
 
+		if copyConfigFrom != 0 {
+			if err := copyInstanceConfig(cmd, c, copyBackend, copyConfig, copyConfigFrom, resp.ID); err != nil {
+				return err
+			}
+		}
+
+		if isQuiet(cmd) {
+			if instanceQuietField == "url" {
+				fmt.Fprintln(cmd.OutOrStdout(), resp.URL)
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), resp.ID)
+			}
+			return nil
+		}
+
 		output, err := json.MarshalIndent(resp, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to format response: %v", err)
 		}
 
-		fmt.Printf("Instance created successfully:\n%s\n", string(output))
+		printStatus(cmd, "Instance created successfully.")
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
 		return nil
 	},
 }
 
+// sourceConfig returns the broker and configured settings of the instance
+// create --copy-config-from copies from. Settings must go to an instance of
+// the same broker, so a plan of the other broker is an error.
+func sourceConfig(c client.ClientAPI, sourceID int, backend, plan string) (string, map[string]any, error) {
+	sourceBackend, err := instanceBackend(c, sourceID)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot copy config from instance %d: %w", sourceID, err)
+	}
+	if sourceBackend != backend {
+		return "", nil, fmt.Errorf("cannot copy config from instance %d: it runs %s, but plan %s is a %s plan", sourceID, sourceBackend, plan, backend)
+	}
+	config, err := getInstanceConfig(c, sourceBackend, strconv.Itoa(sourceID))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get config of instance %d: %w", sourceID, err)
+	}
+	// Unset settings are null; the new instance has the defaults already
+	for key, value := range config {
+		if value == nil {
+			delete(config, key)
+		}
+	}
+	return sourceBackend, config, nil
+}
+
+// copyInstanceConfig applies the config of instance sourceID to the new
+// instance and prints each copied setting.
+func copyInstanceConfig(cmd *cobra.Command, c client.ClientAPI, backend string, config map[string]any, sourceID, instanceID int) error {
+	if len(config) == 0 {
+		printStatus(cmd, "Instance %d has no configured settings, nothing to copy.", sourceID)
+		return nil
+	}
+	if err := updateInstanceConfig(c, backend, strconv.Itoa(instanceID), config); err != nil {
+		return fmt.Errorf("instance %d was created, but copying config from instance %d failed: %w", instanceID, sourceID, err)
+	}
+	printStatus(cmd, "Copied %d config settings from instance %d:", len(config), sourceID)
+	for _, key := range sortedKeys(config) {
+		printStatus(cmd, "  %s = %v", key, config[key])
+	}
+	return nil
+}
+
+// validateVPCRegion checks that the VPC exists and is in region, since an
+// instance can only be placed in a VPC of its own region.
+func validateVPCRegion(c client.ClientAPI, vpcID int, region string) error {
+	vpc, err := c.GetVPC(vpcID)
+	if err != nil {
+		if client.IsNotFound(err) {
+			return fmt.Errorf("VPC %d not found", vpcID)
+		}
+		return fmt.Errorf("failed to get VPC %d: %w", vpcID, err)
+	}
+	if vpc.Region != region {
+		return fmt.Errorf("VPC %d (%s) is in region %s, but the instance would be created in %s. Use --region %s or a VPC in %s", vpcID, vpc.Name, vpc.Region, region, vpc.Region, region)
+	}
+	return nil
+}
+
 func init() {
 	instanceCreateCmd.Flags().StringVar(&instanceName, "name", "", "Name of the instance (required)")
 	instanceCreateCmd.Flags().StringVar(&instancePlan, "plan", "", "Subscription plan (required)")
 	instanceCreateCmd.Flags().StringVar(&instanceRegion, "region", "", "Region identifier (required)")
 	instanceCreateCmd.Flags().StringVar(&instanceRMQVersion, "rmq-version", "", "RabbitMQ version (e.g., 4.0.5); only applies to rabbitmq plans, ignored otherwise")
-	instanceCreateCmd.Flags().StringSliceVar(&instanceTags, "tags", []string{}, "Instance tags")
+	instanceCreateCmd.Flags().StringSliceVar(&instanceTags, "tags", []string{}, "Instance tags (repeat the flag or separate with commas)")
 	instanceCreateCmd.Flags().StringVar(&instanceVPCSubnet, "vpc-subnet", "", "VPC subnet")
 	instanceCreateCmd.Flags().StringVar(&instanceVPCID, "vpc-id", "", "VPC ID")
 	instanceCreateCmd.Flags().StringVar(&instanceCopyFromID, "copy-from-id", "", "Instance ID to copy settings from")
 	instanceCreateCmd.Flags().StringSliceVar(&instanceCopySettings, "copy-settings", []string{}, "Settings to copy (alarms, metrics, logs, firewall, config)")
+	instanceCreateCmd.Flags().StringVar(&instanceCopyConfig, "copy-config-from", "", "Instance ID to copy broker configuration from once the new instance is ready (implies --wait --wait-config)")
 	instanceCreateCmd.Flags().BoolVar(&instanceWait, "wait", false, "Wait for instance to be ready")
 	instanceCreateCmd.Flags().StringVar(&instanceWaitTimeout, "wait-timeout", "15m", "Timeout for waiting (e.g., 15m, 30m)")
+	instanceCreateCmd.Flags().BoolVar(&instanceWaitConfig, "wait-config", false, "With --wait, also wait until the management API answers")
+	instanceCreateCmd.Flags().StringVar(&instanceIdemKey, "idempotency-key", "", "Idempotency-Key header for the create request, best effort as the API is not known to honour it (default: random)")
+	instanceCreateCmd.Flags().StringVar(&instanceQuietField, "quiet-field", "id", "Field printed with --quiet: id or url")
+	addDryRunFlag(instanceCreateCmd)
 
 	instanceCreateCmd.MarkFlagRequired("name")
 	instanceCreateCmd.MarkFlagRequired("plan")
@@ -139,5 +307,6 @@ func init() {
 	instanceCreateCmd.RegisterFlagCompletionFunc("region", completeRegions)
 	instanceCreateCmd.RegisterFlagCompletionFunc("vpc-id", completeVPCIDFlag)
 	instanceCreateCmd.RegisterFlagCompletionFunc("copy-from-id", completeCopyFromIDFlag)
+	instanceCreateCmd.RegisterFlagCompletionFunc("copy-config-from", completeCopyFromIDFlag)
 	instanceCreateCmd.RegisterFlagCompletionFunc("copy-settings", completeCopySettings)
 }
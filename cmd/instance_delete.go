@@ -1,77 +1,152 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
-	"os"
 	"strconv"
-	"strings"
+	"time"
 
 	"cloudamqp-cli/client"
 	"github.com/spf13/cobra"
 )
 
 var (
-	deleteInstanceID string
-	forceDelete      bool
+	deleteInstanceID  string
+	forceDelete       bool
+	deleteWait        bool
+	deleteWaitTimeout string
 )
 
 var instanceDeleteCmd = &cobra.Command{
-	Use:   "delete --id <id>",
-	Short: "Delete a CloudAMQP instance",
+	Use:     "delete --id <id>",
+	Aliases: []string{"rm"},
+	Short:   "Delete a CloudAMQP instance",
 	Long: `Delete a CloudAMQP instance permanently.
 
+With --id-file every instance listed in the file (one ID per line, blank
+lines and # comments ignored) is deleted, plus the --id instance if given.
+One confirmation covers all of them, and a summary with the result for each
+instance is printed.
+
+The API accepts the delete before the instance is gone. With --wait the
+command polls until the API reports the instance as not found, and fails if
+it still exists after --wait-timeout.
+
 WARNING: This action cannot be undone. All data will be lost.`,
 	Example: `  cloudamqp instance delete --id 1234
-  cloudamqp instance delete --id 1234 --force`,
+  cloudamqp instance delete --id 1234 --force
+  cloudamqp instance delete --id 1234 --force --wait --wait-timeout 10m
+  cloudamqp instance delete --id 1234 --dry-run
+  cloudamqp instance delete --id-file ids.txt`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
-		apiKey, err = getAPIKey()
+		if idFilePath(cmd) != "" {
+			if deleteWait {
+				return fmt.Errorf("--wait can't be combined with --id-file")
+			}
+			return deleteInstanceBatch(cmd)
+		}
+		instanceID, err := flagInstanceID(cmd)
 		if err != nil {
-			return fmt.Errorf("failed to get API key: %w", err)
+			return err
 		}
 
-		if deleteInstanceID == "" {
-			return fmt.Errorf("--id is required")
+		var timeout time.Duration
+		if deleteWait {
+			timeout, err = time.ParseDuration(deleteWaitTimeout)
+			if err != nil {
+				return fmt.Errorf("invalid wait-timeout value: %v", err)
+			}
 		}
 
-		instanceID, err := strconv.Atoi(deleteInstanceID)
-		if err != nil {
-			return fmt.Errorf("invalid instance ID: %v", err)
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "DELETE", "/instances/"+strconv.Itoa(instanceID), nil)
 		}
 
 		if !forceDelete {
-			fmt.Printf("Are you sure you want to delete instance %d? This action cannot be undone. (y/N): ", instanceID)
-			reader := bufio.NewReader(os.Stdin)
-			response, err := reader.ReadString('\n')
+			ok, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete instance %d? This action cannot be undone.", instanceID), "--force")
 			if err != nil {
-				return fmt.Errorf("failed to read confirmation: %v", err)
+				return err
 			}
-
-			response = strings.TrimSpace(strings.ToLower(response))
-			if response != "y" && response != "yes" {
-				fmt.Println("Delete operation cancelled.")
+			if !ok {
+				printStatus(cmd, "Delete operation cancelled.")
 				return nil
 			}
 		}
 
-		c := client.New(apiKey, Version)
+		apiKey, err = getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
 
 		err = c.DeleteInstance(instanceID)
 		if err != nil {
-			fmt.Printf("Error deleting instance: %v\n", err)
-			return err
+			return fmt.Errorf("failed to delete instance: %w", err)
 		}
 
-		fmt.Printf("Instance %d deleted successfully.\n", instanceID)
+		if deleteWait {
+			if err := waitForInstanceDeleted(commandContext(cmd), c, instanceID, timeout); err != nil {
+				return fmt.Errorf("delete was accepted, but %w", err)
+			}
+		}
+
+		if !isQuiet(cmd) {
+			printStatus(cmd, "Instance %d deleted successfully.", instanceID)
+		}
 		return nil
 	},
 }
 
+// deleteInstanceBatch deletes the instances selected with --id and --id-file,
+// after a single confirmation, and prints a per-instance summary.
+func deleteInstanceBatch(cmd *cobra.Command) error {
+	var err error
+	apiKey, err = getAPIKey()
+	if err != nil {
+		return fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	c := newAPIClient(apiKey)
+
+	instances, err := batchInstances(cmd, c, deleteInstanceID, nil)
+	if err != nil {
+		return err
+	}
+
+	if isDryRun(cmd) {
+		for _, instance := range instances {
+			if err := printDryRun(cmd, "DELETE", "/instances/"+strconv.Itoa(instance.ID), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !forceDelete {
+		ok, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete %d instances: %s? This action cannot be undone.", len(instances), instanceNames(instances)), "--force")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			printStatus(cmd, "Delete operation cancelled.")
+			return nil
+		}
+	}
+
+	results := runBulk(commandContext(cmd), instances, func(instance client.Instance) error {
+		return c.DeleteInstance(instance.ID)
+	}, false)
+	return printBulkResults(cmd, results)
+}
+
 func init() {
-	instanceDeleteCmd.Flags().StringVar(&deleteInstanceID, "id", "", "Instance ID (required)")
+	instanceDeleteCmd.Flags().StringVar(&deleteInstanceID, "id", "", "Instance ID or name (required unless --id-file is given)")
 	instanceDeleteCmd.Flags().BoolVar(&forceDelete, "force", false, "Skip confirmation prompt")
-	instanceDeleteCmd.MarkFlagRequired("id")
+	instanceDeleteCmd.Flags().BoolVar(&deleteWait, "wait", false, "Wait until the instance no longer exists")
+	instanceDeleteCmd.Flags().StringVar(&deleteWaitTimeout, "wait-timeout", "15m", "Timeout for waiting (e.g., 15m, 30m)")
+	addIDFileFlag(instanceDeleteCmd)
+	addDryRunFlag(instanceDeleteCmd)
+	instanceDeleteCmd.MarkFlagsOneRequired("id", "id-file")
 	instanceDeleteCmd.RegisterFlagCompletionFunc("id", completeInstances)
 }
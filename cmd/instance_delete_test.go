@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deletingClient answers GetInstance with the instance until goneAfter
+// calls have been made and with 404 afterwards; with goneAfter < 0 the
+// instance never goes away. getErr, when set, is returned instead.
+type deletingClient struct {
+	fakeClient
+	goneAfter int
+	getErr    error
+	gets      int
+	deleted   []int
+}
+
+func (f *deletingClient) DeleteInstance(id int) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func (f *deletingClient) GetInstance(id int) (*client.Instance, error) {
+	f.gets++
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	if f.goneAfter >= 0 && f.gets > f.goneAfter {
+		return nil, &client.APIError{StatusCode: http.StatusNotFound, Message: "Not found"}
+	}
+	return &client.Instance{ID: id}, nil
+}
+
+func TestInstanceDeleteCmd_Wait(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	run := func(t *testing.T, fake *deletingClient, timeout string) error {
+		t.Helper()
+		useFakeClient(t, fake)
+		cmd := instanceDeleteCmd
+		defer resetFlags(cmd)
+		require.NoError(t, cmd.ParseFlags([]string{"--id", "1234", "--force", "--wait", "--wait-timeout", timeout}))
+		cmd.SetErr(&bytes.Buffer{})
+		defer cmd.SetErr(nil)
+		return cmd.RunE(cmd, []string{})
+	}
+
+	t.Run("until not found", func(t *testing.T) {
+		fake := &deletingClient{goneAfter: 2}
+		require.NoError(t, run(t, fake, "1m"))
+		assert.Equal(t, []int{1234}, fake.deleted)
+		assert.Equal(t, 3, fake.gets)
+	})
+
+	t.Run("still exists after timeout", func(t *testing.T) {
+		fake := &deletingClient{goneAfter: -1}
+		err := run(t, fake, "20ms")
+		assert.ErrorContains(t, err, "delete was accepted, but timeout after")
+		assert.ErrorContains(t, err, "instance 1234 still exists")
+	})
+
+	t.Run("other errors are not success", func(t *testing.T) {
+		fake := &deletingClient{getErr: &client.APIError{StatusCode: http.StatusInternalServerError, Message: "boom"}}
+		err := run(t, fake, "1m")
+		assert.ErrorContains(t, err, "failed to check instance status: API error (500): boom")
+		assert.Equal(t, 1, fake.gets)
+	})
+}
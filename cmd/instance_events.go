@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"cloudamqp-cli/client"
+	"cloudamqp-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var instanceEventsCmd = &cobra.Command{
+	Use:   "events --id <instance_id>",
+	Short: "Show recent events on an instance",
+	Long: `Shows events on the instance, such as upgrades, restarts and config
+changes, oldest first.
+
+The API has no per-instance events endpoint, so events are read from the
+account audit log and filtered to the instance. Without --since the current
+month is shown.
+
+--since and --until take a duration before now, such as 30m, 24h or 7d, or
+an absolute time such as 2026-10-16T10:00:00Z or 2026-10-16.
+
+Use --follow to keep polling for new events every --interval until
+interrupted with Ctrl-C. Combine it with -o jsonl for one event per line.`,
+	Example: `  cloudamqp instance events --id 1234
+  cloudamqp instance events --id 1234 --since 24h
+  cloudamqp instance events --id 1234 --since 14d --until 7d
+  cloudamqp instance events --id 1234 --since 2026-10-01 --until 2026-10-08
+  cloudamqp instance events --id 1234 --follow -o jsonl`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, _ := cmd.Flags().GetString("id")
+		if idFlag == "" {
+			return fmt.Errorf("instance ID is required. Use --id flag")
+		}
+
+		since, until, err := timeWindow(cmd)
+		if err != nil {
+			return err
+		}
+
+		follow, _ := cmd.Flags().GetBool("follow")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if follow && interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		if follow && !until.IsZero() {
+			return fmt.Errorf("--until cannot be combined with --follow")
+		}
+
+		apiKey, err = getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		instanceID, err := resolveInstance(c, idFlag)
+		if err != nil {
+			return err
+		}
+
+		p, err := getListPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		events, err := c.ListInstanceEvents(instanceID, since)
+		if err != nil {
+			return fmt.Errorf("failed to list events: %w", err)
+		}
+		events = slices.DeleteFunc(events, func(e client.InstanceEvent) bool { return !inTimeWindow(e.Time, since, until) })
+
+		if !follow {
+			if len(events) == 0 {
+				printStatus(cmd, "No events found.")
+				return nil
+			}
+			printInstanceEvents(p, events)
+			return nil
+		}
+
+		return followInstanceEvents(commandContext(cmd), c, p, instanceID, since, events, interval)
+	},
+}
+
+// followInstanceEvents prints events and then polls for new ones until ctx
+// is cancelled. Events already printed are skipped on each poll.
+func followInstanceEvents(ctx context.Context, c client.ClientAPI, p *output.Printer, instanceID int, since time.Time, events []client.InstanceEvent, interval time.Duration) error {
+	seen := map[client.InstanceEvent]bool{}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var fresh []client.InstanceEvent
+		for _, e := range events {
+			if !seen[e] {
+				seen[e] = true
+				fresh = append(fresh, e)
+			}
+		}
+		if len(fresh) > 0 {
+			printInstanceEvents(p, fresh)
+			since = fresh[len(fresh)-1].Time
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		var err error
+		events, err = c.ListInstanceEvents(instanceID, since)
+		if err != nil {
+			logWarn("failed to poll events: %v", err)
+			events = nil
+		}
+	}
+}
+
+func printInstanceEvents(p *output.Printer, events []client.InstanceEvent) {
+	rows := make([][]string, len(events))
+	for i, e := range events {
+		rows[i] = []string{e.Time.Local().Format(time.RFC3339), e.Type, e.Message}
+	}
+	p.PrintRecords([]string{"TIME", "TYPE", "MESSAGE"}, rows)
+}
+
+func init() {
+	instanceEventsCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceEventsCmd.MarkFlagRequired("id")
+	addTimeWindowFlags(instanceEventsCmd, "events")
+	instanceEventsCmd.Flags().Bool("follow", false, "Keep polling for new events until interrupted")
+	instanceEventsCmd.Flags().Duration("interval", 30*time.Second, "How often to poll for new events with --follow")
+	instanceEventsCmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type eventsClient struct {
+	fakeClient
+	since  time.Time
+	events []client.InstanceEvent
+}
+
+func (f *eventsClient) ListInstanceEvents(id int, since time.Time) ([]client.InstanceEvent, error) {
+	f.since = since
+	return f.events, nil
+}
+
+func TestInstanceEventsCmd(t *testing.T) {
+	fake := &eventsClient{events: []client.InstanceEvent{
+		{Time: time.Now().Add(-time.Hour), Type: "restart", Message: "RabbitMQ restarted"},
+	}}
+	useFakeClient(t, fake)
+
+	cmd := instanceEventsCmd
+	cmd.InheritedFlags() // merge --output from root, as Execute would
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("since", "24h")
+	defer resetFlags(cmd)
+
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"TIME", "TYPE", "MESSAGE"}, strings.Fields(lines[0]))
+	assert.Contains(t, lines[2], "restart")
+	assert.Contains(t, lines[2], "RabbitMQ restarted")
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), fake.since, time.Minute)
+}
+
+func TestInstanceEventsCmd_InvalidSince(t *testing.T) {
+	cmd := instanceEventsCmd
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("since", "yesterday")
+	defer resetFlags(cmd)
+
+	err := cmd.RunE(cmd, []string{})
+	assert.EqualError(t, err, `invalid --since "yesterday": expected a duration such as 24h or 7d, or a time such as 2026-10-16T10:00:00Z`)
+}
+
+func TestInstanceEventsCmd_Until(t *testing.T) {
+	useFakeClient(t, &eventsClient{events: []client.InstanceEvent{
+		{Time: time.Now().Add(-10 * 24 * time.Hour), Type: "restart", Message: "old"},
+		{Time: time.Now().Add(-time.Hour), Type: "restart", Message: "recent"},
+	}})
+
+	cmd := instanceEventsCmd
+	cmd.InheritedFlags()
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("since", "14d")
+	cmd.Flags().Set("until", "7d")
+	defer resetFlags(cmd)
+
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+
+	assert.Contains(t, out, "old")
+	assert.NotContains(t, out, "recent")
+}
+
+func TestTimeWindow(t *testing.T) {
+	cmd := instanceEventsCmd
+	defer resetFlags(cmd)
+
+	cmd.Flags().Set("since", "2026-10-01")
+	cmd.Flags().Set("until", "2026-10-08T12:00:00Z")
+	since, until, err := timeWindow(cmd)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local), since)
+	assert.True(t, until.Equal(time.Date(2026, 10, 8, 12, 0, 0, 0, time.UTC)))
+
+	cmd.Flags().Set("until", "2026-09-30")
+	_, _, err = timeWindow(cmd)
+	assert.EqualError(t, err, "--until must be after --since")
+
+	cmd.Flags().Set("until", "last week")
+	_, _, err = timeWindow(cmd)
+	assert.ErrorContains(t, err, `invalid --until "last week"`)
+
+	assert.True(t, inTimeWindow(since, since, until.Add(time.Hour)))
+	assert.False(t, inTimeWindow(since.Add(-time.Second), since, time.Time{}))
+	assert.True(t, inTimeWindow(time.Now(), time.Time{}, time.Time{}))
+}
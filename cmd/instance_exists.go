@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var instanceExistsCmd = &cobra.Command{
+	Use:   "exists --id <id>",
+	Short: "Check whether an instance exists",
+	Long: `Checks whether an instance exists, for use in scripts.
+
+Exits 0 if the instance exists and 4 if it doesn't, printing nothing either
+way. Use --verbose to print the result. Other failures, such as a rejected
+API key or an unreachable API, exit 1 with an error as usual.
+
+--id also takes an instance name, which exists if any instance has it.`,
+	Example: `  cloudamqp instance exists --id 1234
+  cloudamqp instance exists --id orders --verbose
+  # Exit code 4 if instance 1234 is gone
+  cloudamqp instance exists --id 1234 && echo "still there"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, _ := cmd.Flags().GetString("id")
+		idFlag = strings.TrimSpace(idFlag)
+		if idFlag == "" {
+			return fmt.Errorf("instance ID is required. Use --id flag")
+		}
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		var err error
+		apiKey, err = getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+		exists, err := instanceExists(c, idFlag)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			cmd.SilenceUsage = true
+			if verbose {
+				printStatus(cmd, "Instance %s does not exist.", idFlag)
+			} else {
+				cmd.SilenceErrors = true
+			}
+			return &exitError{code: exitNotFound, err: fmt.Errorf("instance %s not found", idFlag)}
+		}
+		if verbose {
+			printStatus(cmd, "Instance %s exists.", idFlag)
+		}
+		return nil
+	},
+}
+
+// instanceExists reports whether the instance with the given ID, or any
+// instance with the given name, exists. Only a 404 from the API counts as
+// not existing; other errors are returned.
+func instanceExists(c client.ClientAPI, idOrName string) (bool, error) {
+	id, err := strconv.Atoi(idOrName)
+	if err != nil {
+		instances, err := c.ListInstances()
+		if err != nil {
+			return false, fmt.Errorf("failed to list instances: %w", err)
+		}
+		for _, instance := range instances {
+			if instance.Name == idOrName {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if _, err := c.GetInstance(id); err != nil {
+		if client.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get instance %d: %w", id, err)
+	}
+	return true, nil
+}
+
+func init() {
+	instanceExistsCmd.Flags().String("id", "", "Instance ID or name (required)")
+	instanceExistsCmd.Flags().Bool("verbose", false, "Print whether the instance exists")
+	instanceExistsCmd.MarkFlagRequired("id")
+	instanceExistsCmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// existsClient answers GetInstance like the API: 404 for unknown IDs.
+type existsClient struct {
+	fakeClient
+	err error
+}
+
+func (e *existsClient) GetInstance(id int) (*client.Instance, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if instance, ok := e.instances[id]; ok {
+		return instance, nil
+	}
+	return nil, &client.APIError{StatusCode: http.StatusNotFound, Message: "Not found"}
+}
+
+func TestInstanceExistsCmd(t *testing.T) {
+	useFakeClient(t, &existsClient{fakeClient: fakeClient{instances: map[int]*client.Instance{
+		1234: {ID: 1234, Name: "orders"},
+	}}})
+
+	tests := []struct {
+		id   string
+		code int
+	}{
+		{"1234", 0},
+		{"orders", 0},
+		{"99", exitNotFound},
+		{"billing", exitNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			cmd := instanceExistsCmd
+			cmd.Flags().Set("id", tt.id)
+			defer resetFlags(cmd)
+			var stderr bytes.Buffer
+			cmd.SetErr(&stderr)
+			defer cmd.SetErr(nil)
+
+			out := captureStdout(t, func() {
+				err := cmd.RunE(cmd, []string{})
+				assert.Equal(t, tt.code, ExitCode(err))
+			})
+
+			assert.Empty(t, out)
+			assert.Empty(t, stderr.String())
+		})
+	}
+}
+
+func TestInstanceExistsCmd_Verbose(t *testing.T) {
+	useFakeClient(t, &existsClient{})
+
+	cmd := instanceExistsCmd
+	cmd.Flags().Set("id", "99")
+	cmd.Flags().Set("verbose", "true")
+	defer resetFlags(cmd)
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	defer cmd.SetErr(nil)
+
+	err := cmd.RunE(cmd, []string{})
+	assert.Equal(t, exitNotFound, ExitCode(err))
+	assert.Equal(t, "Instance 99 does not exist.\n", stderr.String())
+}
+
+func TestInstanceExistsCmd_OtherError(t *testing.T) {
+	useFakeClient(t, &existsClient{err: &client.APIError{StatusCode: http.StatusUnauthorized, Message: "Unauthorized"}})
+
+	cmd := instanceExistsCmd
+	cmd.Flags().Set("id", "1234")
+	defer resetFlags(cmd)
+
+	err := cmd.RunE(cmd, []string{})
+	require.Error(t, err)
+	assert.Equal(t, 1, ExitCode(err))
+	assert.ErrorContains(t, err, "API error (401)")
+}
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Equal(t, 1, ExitCode(errors.New("boom")))
+	assert.Equal(t, exitNotFound, ExitCode(&exitError{code: exitNotFound, err: errors.New("gone")}))
+	assert.Equal(t, exitInterrupted, ExitCode(fmt.Errorf("request not sent: %w", context.Canceled)))
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cloudamqp-cli/internal/output"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var instanceExportCmd = &cobra.Command{
+	Use:   "export --id <id>",
+	Short: "Export an instance as a portable spec",
+	Long: `Export everything needed to recreate an instance as a YAML document: name,
+plan, region, tags, RabbitMQ configuration, enabled plugins, alarms and
+firewall rules. Use -o json for JSON instead.
+
+The output can be applied to an instance with 'cloudamqp instance apply'.`,
+	Example: `  cloudamqp instance export --id 1234 > instance.yaml
+  cloudamqp instance export --id 1234 -o json > instance.json
+  cloudamqp instance export --id 1234 --partial > instance.yaml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, _ := cmd.Flags().GetString("id")
+		if idFlag == "" {
+			return fmt.Errorf("instance ID is required. Use --id flag")
+		}
+
+		asJSON, err := documentAsJSON(cmd)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+		instanceID, err := resolveInstance(c, idFlag)
+		if err != nil {
+			return err
+		}
+
+		partial, _ := cmd.Flags().GetBool("partial")
+		spec, err := buildInstanceSpec(c, instanceID, partial)
+		if err != nil {
+			return err
+		}
+
+		var data []byte
+		if asJSON {
+			data, err = json.MarshalIndent(spec, "", "  ")
+			data = append(data, '\n')
+		} else {
+			data, err = marshalSpecYAML(spec)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to format spec: %w", err)
+		}
+
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	},
+}
+
+// marshalSpecYAML formats spec as YAML with sorted keys, like config
+// export. It goes through a generic document so the field names from the
+// yaml tags are kept.
+func marshalSpecYAML(spec *InstanceSpec) ([]byte, error) {
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return output.MarshalSortedYAML(doc)
+}
+
+func init() {
+	instanceExportCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceExportCmd.Flags().Bool("partial", false, "Leave out sections that can't be fetched instead of failing")
+	instanceExportCmd.MarkFlagRequired("id")
+	instanceExportCmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloudamqp-cli/client"
+)
+
+// instanceFieldAliases maps friendly field names to Instance struct fields.
+var instanceFieldAliases = map[string]string{
+	"hostname": "HostnameExternal",
+	"version":  "RMQVersion",
+}
+
+// instanceSecretFields are the Instance fields that hold credentials, with
+// how --select prints them. The URL is only shown in full with --show-url;
+// the API key is never shown in full.
+var instanceSecretFields = map[string]func(value string, showURL bool) string{
+	"URL": func(value string, showURL bool) string {
+		if showURL {
+			return value
+		}
+		return maskPassword(value)
+	},
+	"APIKey": func(value string, _ bool) string {
+		if value == "" {
+			return ""
+		}
+		return maskAPIKey(value)
+	},
+}
+
+// instanceFieldNames returns the names accepted by --select for an instance.
+func instanceFieldNames() []string {
+	t := reflect.TypeOf(client.Instance{})
+	names := make([]string, 0, t.NumField()+len(instanceFieldAliases))
+	for i := 0; i < t.NumField(); i++ {
+		names = append(names, t.Field(i).Name)
+	}
+	for alias := range instanceFieldAliases {
+		names = append(names, alias)
+	}
+	return names
+}
+
+// lookupInstanceField finds the Instance struct field matching name
+// case-insensitively by Go name, JSON name or alias.
+func lookupInstanceField(name string) (reflect.StructField, bool) {
+	t := reflect.TypeOf(client.Instance{})
+	if alias, ok := instanceFieldAliases[strings.ToLower(name)]; ok {
+		name = alias
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		jsonName := strings.Split(f.Tag.Get("json"), ",")[0]
+		if strings.EqualFold(f.Name, name) || strings.EqualFold(jsonName, name) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// formatFieldValue renders a struct field value the way table output does.
+func formatFieldValue(v reflect.Value) string {
+	if t, ok := v.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return ""
+		}
+		return formatFieldValue(v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			return "Yes"
+		}
+		return "No"
+	case reflect.Int:
+		return strconv.Itoa(int(v.Int()))
+	case reflect.Slice:
+		parts := make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			parts[i] = formatFieldValue(v.Index(i))
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprintf("%v", v.Interface())
+	}
+}
+
+// resolveInstanceFields maps the names given to --select onto Instance
+// struct fields, suggesting the closest match for unknown names.
+func resolveInstanceFields(names []string) ([]reflect.StructField, error) {
+	fields := make([]reflect.StructField, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		f, ok := lookupInstanceField(name)
+		if !ok {
+			if suggestion := suggestClosest(name, instanceFieldNames()); suggestion != "" {
+				return nil, fmt.Errorf("unknown field %q, did you mean %q?", name, suggestion)
+			}
+			return nil, fmt.Errorf("unknown field %q. Valid fields are: %s", name, strings.Join(instanceFieldNames(), ", "))
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// projectInstance returns headers and values for the given instance fields,
+// in the order they were requested. Headers are the upper-cased JSON names.
+func projectInstance(instance *client.Instance, fields []reflect.StructField, showURL bool) ([]string, []string) {
+	v := reflect.ValueOf(*instance)
+	headers := make([]string, len(fields))
+	values := make([]string, len(fields))
+	for i, f := range fields {
+		value := formatFieldValue(v.FieldByIndex(f.Index))
+		if mask, ok := instanceSecretFields[f.Name]; ok {
+			value = mask(value, showURL)
+		}
+		headers[i] = strings.ToUpper(strings.Split(f.Tag.Get("json"), ",")[0])
+		values[i] = value
+	}
+	return headers, values
+}
@@ -0,0 +1,419 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// firewallServices lists the service names accepted by the firewall API.
+var firewallServices = []string{
+	"AMQP", "AMQPS", "HTTPS", "MQTT", "MQTTS", "STOMP", "STOMPS", "STREAM", "STREAM_SSL",
+}
+
+var instanceFirewallCmd = &cobra.Command{
+	Use:   "firewall",
+	Short: "Manage instance firewall rules",
+	Long:  `List, replace, and add firewall rules for the instance.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Help()
+		cmd.SilenceUsage = true
+		return fmt.Errorf("subcommand required")
+	},
+}
+
+var instanceFirewallListCmd = &cobra.Command{
+	Use:     "list --id <instance_id>",
+	Aliases: []string{"ls"},
+	Short:   "List firewall rules",
+	Long:    `Retrieves all firewall rules for the instance.`,
+	Example: `  cloudamqp instance firewall list --id 1234`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		rules, err := c.GetFirewall(idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to get firewall rules: %w", err)
+		}
+
+		if len(rules) == 0 {
+			printStatus(cmd, "No firewall rules found.")
+			return nil
+		}
+
+		p, err := getListPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(rules))
+		for i, rule := range rules {
+			rows[i] = firewallRuleRow(rule)
+		}
+		p.PrintRecords(firewallRuleHeaders, rows)
+
+		return nil
+	},
+}
+
+var instanceFirewallSetCmd = &cobra.Command{
+	Use:   "set --id <instance_id> --rules-file <file>",
+	Short: "Replace all firewall rules",
+	Long: `Replace the full set of firewall rules with the rules in a JSON file.
+
+The file must contain an array of rules, for example:
+
+  [
+    {"ip": "10.56.72.0/24", "services": ["AMQPS", "HTTPS"], "ports": [], "description": "VPC"}
+  ]
+
+Note: This action is asynchronous. The firewall is reconfigured in the background.`,
+	Example: `  cloudamqp instance firewall set --id 1234 --rules-file rules.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		rulesFile, _ := cmd.Flags().GetString("rules-file")
+		data, err := os.ReadFile(rulesFile)
+		if err != nil {
+			return fmt.Errorf("failed to read rules file: %w", err)
+		}
+
+		var rules []client.FirewallRule
+		if err := json.Unmarshal(data, &rules); err != nil {
+			return fmt.Errorf("failed to parse rules file: %w", err)
+		}
+
+		for _, rule := range rules {
+			if err := validateCIDR(rule.IP); err != nil {
+				return err
+			}
+		}
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "PUT", "/instances/"+idFlag+"/security/firewall", rules)
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		err = c.UpdateFirewall(idFlag, rules)
+		if err != nil {
+			return fmt.Errorf("failed to update firewall rules: %w", err)
+		}
+
+		printStatus(cmd, "Firewall updated with %d rule(s).", len(rules))
+		return nil
+	},
+}
+
+var instanceFirewallAddCmd = &cobra.Command{
+	Use:   "add --id <instance_id> --ip <cidr>",
+	Short: "Add a firewall rule",
+	Long: `Add a firewall rule while keeping the existing rules.
+
+--ports accepts service names (amqp, amqps, https, mqtt, mqtts, stomp, stomps,
+stream, stream_ssl) and custom port numbers, separated by commas.
+
+Note: This action is asynchronous. The firewall is reconfigured in the background.`,
+	Example: `  cloudamqp instance firewall add --id 1234 --ip 1.2.3.4/32 --ports amqps,https --description office
+  cloudamqp instance firewall add --id 1234 --ip 10.0.0.0/16 --ports amqps,5552`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		ip, _ := cmd.Flags().GetString("ip")
+		if err := validateCIDR(ip); err != nil {
+			return err
+		}
+
+		portsFlag, _ := cmd.Flags().GetStringSlice("ports")
+		services, ports, err := parseFirewallPorts(portsFlag)
+		if err != nil {
+			return err
+		}
+
+		description, _ := cmd.Flags().GetString("description")
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		rules, err := c.GetFirewall(idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to get firewall rules: %w", err)
+		}
+
+		rules = append(rules, client.FirewallRule{
+			IP:          ip,
+			Services:    services,
+			Ports:       ports,
+			Description: description,
+		})
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "PUT", "/instances/"+idFlag+"/security/firewall", rules)
+		}
+
+		err = c.UpdateFirewall(idFlag, rules)
+		if err != nil {
+			return fmt.Errorf("failed to update firewall rules: %w", err)
+		}
+
+		printStatus(cmd, "Firewall rule for %s added.", ip)
+		return nil
+	},
+}
+
+var instanceFirewallAllowMyIPCmd = &cobra.Command{
+	Use:     "allow-my-ip --id <instance_id>",
+	Aliases: []string{"open-my-ip"},
+	Short:   "Allow this machine's public IP through the firewall",
+	Long: `Adds a rule for the public IP of this machine while keeping the existing
+rules, and prints the rule.
+
+The IP is looked up with --resolver, a URL that answers with the caller's IP
+as plain text (default ` + defaultIPResolver + `), unless it is given with --ip.
+The rule is for that single address (/32, or /128 for IPv6).
+
+--ports takes the same services and port numbers as firewall add and
+defaults to amqps,https. If a rule for the IP exists, the missing services
+and ports are added to it; if it already allows all of them, nothing is
+changed. --description defaults to the host name and the current time.
+
+Note: This action is asynchronous. The firewall is reconfigured in the background.`,
+	Example: `  cloudamqp instance firewall allow-my-ip --id 1234
+  cloudamqp instance firewall allow-my-ip --id 1234 --ports amqps
+  cloudamqp instance firewall allow-my-ip --id 1234 --ip 203.0.113.7 --description "CI runner"
+  cloudamqp instance firewall allow-my-ip --id 1234 --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		portsFlag, _ := cmd.Flags().GetStringSlice("ports")
+		if len(portsFlag) == 0 {
+			portsFlag = []string{"amqps", "https"}
+		}
+		services, ports, err := parseFirewallPorts(portsFlag)
+		if err != nil {
+			return err
+		}
+
+		var ip net.IP
+		if ipFlag, _ := cmd.Flags().GetString("ip"); ipFlag != "" {
+			if ip = net.ParseIP(ipFlag); ip == nil {
+				return fmt.Errorf("invalid --ip %q: must be a single IP address such as 203.0.113.7", ipFlag)
+			}
+		} else {
+			resolver, _ := cmd.Flags().GetString("resolver")
+			if ip, err = lookupPublicIP(resolver); err != nil {
+				return err
+			}
+		}
+		cidr := hostCIDR(ip)
+
+		description, _ := cmd.Flags().GetString("description")
+		if description == "" {
+			host, _ := os.Hostname()
+			description = strings.TrimSpace(host + " " + time.Now().UTC().Format(time.RFC3339))
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		rules, err := c.GetFirewall(idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to get firewall rules: %w", err)
+		}
+
+		rules, rule, changed := allowFirewallRule(rules, cidr, services, ports, description)
+		if !changed {
+			printStatus(cmd, "%s is already allowed for %s.", cidr, strings.ToLower(strings.Join(portsFlag, ",")))
+			return nil
+		}
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "PUT", "/instances/"+idFlag+"/security/firewall", rules)
+		}
+
+		if err := c.UpdateFirewall(idFlag, rules); err != nil {
+			return fmt.Errorf("failed to update firewall rules: %w", err)
+		}
+
+		p, err := getPrinter(cmd)
+		if err != nil {
+			return err
+		}
+		printStatus(cmd, "Firewall rule for %s added.", cidr)
+		p.PrintRecord(firewallRuleHeaders, firewallRuleRow(rule))
+		return nil
+	},
+}
+
+// allowFirewallRule returns rules with cidr allowed for services and ports,
+// the rule for cidr, and whether anything changed. An existing rule for
+// cidr gets the missing services and ports added; otherwise a rule is
+// appended.
+func allowFirewallRule(rules []client.FirewallRule, cidr string, services []string, ports []int, description string) ([]client.FirewallRule, client.FirewallRule, bool) {
+	for i, rule := range rules {
+		if !sameCIDR(rule.IP, cidr) {
+			continue
+		}
+		changed := false
+		for _, service := range services {
+			if !slices.Contains(rule.Services, service) {
+				rule.Services = append(rule.Services, service)
+				changed = true
+			}
+		}
+		for _, port := range ports {
+			if !slices.Contains(rule.Ports, port) {
+				rule.Ports = append(rule.Ports, port)
+				changed = true
+			}
+		}
+		rules[i] = rule
+		return rules, rule, changed
+	}
+
+	rule := client.FirewallRule{IP: cidr, Services: services, Ports: ports, Description: description}
+	return append(rules, rule), rule, true
+}
+
+// sameCIDR reports whether a and b are the same network, so 1.2.3.4/32
+// matches a rule written as 1.2.3.4/32 with different formatting.
+func sameCIDR(a, b string) bool {
+	_, netA, errA := net.ParseCIDR(a)
+	_, netB, errB := net.ParseCIDR(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return netA.String() == netB.String()
+}
+
+var firewallRuleHeaders = []string{"IP", "SERVICES", "PORTS", "DESCRIPTION"}
+
+// firewallRuleRow formats rule for firewallRuleHeaders.
+func firewallRuleRow(rule client.FirewallRule) []string {
+	ports := make([]string, len(rule.Ports))
+	for i, port := range rule.Ports {
+		ports[i] = strconv.Itoa(port)
+	}
+	return []string{rule.IP, strings.Join(rule.Services, ","), strings.Join(ports, ","), rule.Description}
+}
+
+// validateCIDR checks that ip is in CIDR notation, e.g. 1.2.3.4/32.
+func validateCIDR(ip string) error {
+	if _, _, err := net.ParseCIDR(ip); err != nil {
+		return fmt.Errorf("invalid IP %q: must be in CIDR notation (e.g. 1.2.3.4/32)", ip)
+	}
+	return nil
+}
+
+// parseFirewallPorts splits --ports values into known service names and
+// custom port numbers.
+func parseFirewallPorts(values []string) ([]string, []int, error) {
+	services := []string{}
+	ports := []int{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if port, err := strconv.Atoi(v); err == nil {
+			if port < 1 || port > 65535 {
+				return nil, nil, fmt.Errorf("invalid port %d: must be between 1 and 65535", port)
+			}
+			ports = append(ports, port)
+			continue
+		}
+		service := strings.ToUpper(v)
+		known := false
+		for _, s := range firewallServices {
+			if s == service {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, nil, fmt.Errorf("unknown service %q: valid services are %s", v, strings.ToLower(strings.Join(firewallServices, ", ")))
+		}
+		services = append(services, service)
+	}
+	if len(services) == 0 && len(ports) == 0 {
+		return nil, nil, fmt.Errorf("at least one service or port must be specified with --ports")
+	}
+	return services, ports, nil
+}
+
+func init() {
+	instanceFirewallListCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceFirewallListCmd.MarkFlagRequired("id")
+
+	instanceFirewallSetCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceFirewallSetCmd.Flags().String("rules-file", "", "JSON file with the complete list of rules (required)")
+	addDryRunFlag(instanceFirewallSetCmd)
+	instanceFirewallSetCmd.MarkFlagRequired("id")
+	instanceFirewallSetCmd.MarkFlagRequired("rules-file")
+
+	instanceFirewallAddCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceFirewallAddCmd.Flags().String("ip", "", "IP range in CIDR notation (required)")
+	instanceFirewallAddCmd.Flags().StringSlice("ports", []string{}, "Services and/or port numbers to open (required)")
+	instanceFirewallAddCmd.Flags().String("description", "", "Description of the rule")
+	addDryRunFlag(instanceFirewallAddCmd)
+	instanceFirewallAddCmd.MarkFlagRequired("id")
+	instanceFirewallAddCmd.MarkFlagRequired("ip")
+	instanceFirewallAddCmd.MarkFlagRequired("ports")
+
+	instanceFirewallAllowMyIPCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceFirewallAllowMyIPCmd.Flags().String("ip", "", "IP address to allow instead of looking up the public IP")
+	instanceFirewallAllowMyIPCmd.Flags().String("resolver", defaultIPResolver, "URL that returns the caller's public IP as plain text")
+	instanceFirewallAllowMyIPCmd.Flags().StringSlice("ports", nil, "Services and/or port numbers to open (default amqps,https)")
+	instanceFirewallAllowMyIPCmd.Flags().String("description", "", "Description of the rule (default: host name and current time)")
+	addDryRunFlag(instanceFirewallAllowMyIPCmd)
+	instanceFirewallAllowMyIPCmd.MarkFlagRequired("id")
+
+	for _, cmd := range []*cobra.Command{instanceFirewallListCmd, instanceFirewallSetCmd, instanceFirewallAddCmd, instanceFirewallAllowMyIPCmd} {
+		cmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+	}
+
+	instanceFirewallCmd.AddCommand(instanceFirewallListCmd)
+	instanceFirewallCmd.AddCommand(instanceFirewallSetCmd)
+	instanceFirewallCmd.AddCommand(instanceFirewallAddCmd)
+	instanceFirewallCmd.AddCommand(instanceFirewallAllowMyIPCmd)
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCIDR(t *testing.T) {
+	assert.NoError(t, validateCIDR("1.2.3.4/32"))
+	assert.NoError(t, validateCIDR("10.0.0.0/16"))
+	assert.NoError(t, validateCIDR("2001:db8::/32"))
+
+	assert.Error(t, validateCIDR("1.2.3.4"))
+	assert.Error(t, validateCIDR("1.2.3.4/33"))
+	assert.Error(t, validateCIDR("office"))
+}
+
+func TestParseFirewallPorts(t *testing.T) {
+	services, ports, err := parseFirewallPorts([]string{"amqps", "HTTPS", "5552"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AMQPS", "HTTPS"}, services)
+	assert.Equal(t, []int{5552}, ports)
+
+	_, _, err = parseFirewallPorts([]string{"ftp"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown service")
+
+	_, _, err = parseFirewallPorts([]string{"70000"})
+	assert.Error(t, err)
+
+	_, _, err = parseFirewallPorts([]string{""})
+	assert.Error(t, err)
+}
+
+// firewallClient serves and records the firewall rules of an instance.
+type firewallClient struct {
+	fakeClient
+	rules   []client.FirewallRule
+	updated []client.FirewallRule
+}
+
+func (f *firewallClient) GetFirewall(string) ([]client.FirewallRule, error) {
+	return append([]client.FirewallRule(nil), f.rules...), nil
+}
+
+func (f *firewallClient) UpdateFirewall(_ string, rules []client.FirewallRule) error {
+	f.updated = rules
+	return nil
+}
+
+func useFakePublicIP(t *testing.T, ip string) {
+	t.Helper()
+	orig := lookupPublicIP
+	lookupPublicIP = func(string) (net.IP, error) { return net.ParseIP(ip), nil }
+	t.Cleanup(func() { lookupPublicIP = orig })
+}
+
+func TestInstanceFirewallAllowMyIPCmd(t *testing.T) {
+	office := client.FirewallRule{IP: "10.0.0.0/16", Services: []string{"AMQPS"}, Ports: []int{}, Description: "office"}
+
+	run := func(t *testing.T, fake *firewallClient, flags map[string]string) (string, string) {
+		t.Helper()
+		useFakeClient(t, fake)
+		useFakePublicIP(t, "203.0.113.7")
+
+		cmd := instanceFirewallAllowMyIPCmd
+		cmd.InheritedFlags()
+		require.NoError(t, cmd.Flags().Set("id", "1234"))
+		for name, value := range flags {
+			require.NoError(t, cmd.Flags().Set(name, value))
+		}
+		defer resetFlags(cmd)
+
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		defer cmd.SetOut(nil)
+		defer cmd.SetErr(nil)
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+		return stdout.String(), stderr.String()
+	}
+
+	t.Run("appends a rule for the public IP", func(t *testing.T) {
+		fake := &firewallClient{rules: []client.FirewallRule{office}}
+		out, _ := run(t, fake, map[string]string{"description": "laptop"})
+
+		require.Len(t, fake.updated, 2)
+		assert.Equal(t, office, fake.updated[0])
+		assert.Equal(t, client.FirewallRule{IP: "203.0.113.7/32", Services: []string{"AMQPS", "HTTPS"}, Ports: []int{}, Description: "laptop"}, fake.updated[1])
+		assert.Contains(t, out, "203.0.113.7/32")
+	})
+
+	t.Run("default description", func(t *testing.T) {
+		fake := &firewallClient{}
+		run(t, fake, nil)
+
+		require.Len(t, fake.updated, 1)
+		assert.NotEmpty(t, fake.updated[0].Description)
+	})
+
+	t.Run("--ip skips the lookup", func(t *testing.T) {
+		fake := &firewallClient{}
+		run(t, fake, map[string]string{"ip": "2001:db8::1", "ports": "amqps"})
+
+		require.Len(t, fake.updated, 1)
+		assert.Equal(t, "2001:db8::1/128", fake.updated[0].IP)
+		assert.Equal(t, []string{"AMQPS"}, fake.updated[0].Services)
+	})
+
+	t.Run("already allowed is a no-op", func(t *testing.T) {
+		fake := &firewallClient{rules: []client.FirewallRule{
+			{IP: "203.0.113.7/32", Services: []string{"AMQPS", "HTTPS", "MQTTS"}, Ports: []int{}},
+		}}
+		out, status := run(t, fake, nil)
+
+		assert.Nil(t, fake.updated)
+		assert.Empty(t, out)
+		assert.Contains(t, status, "203.0.113.7/32 is already allowed")
+	})
+
+	t.Run("extends an existing rule", func(t *testing.T) {
+		fake := &firewallClient{rules: []client.FirewallRule{
+			{IP: "203.0.113.7/32", Services: []string{"AMQPS"}, Ports: []int{}, Description: "home"},
+		}}
+		run(t, fake, map[string]string{"ports": "amqps,5552"})
+
+		require.Len(t, fake.updated, 1)
+		assert.Equal(t, client.FirewallRule{IP: "203.0.113.7/32", Services: []string{"AMQPS"}, Ports: []int{5552}, Description: "home"}, fake.updated[0])
+	})
+}
+
+func TestLookupPublicIP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("198.51.100.4\n"))
+	}))
+	defer server.Close()
+
+	ip, err := lookupPublicIP(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "198.51.100.4/32", hostCIDR(ip))
+
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>rate limited</html>"))
+	}))
+	defer bad.Close()
+
+	_, err = lookupPublicIP(bad.URL)
+	assert.ErrorContains(t, err, "did not return an IP address")
+}
@@ -3,10 +3,14 @@ package cmd
 import (
 	"fmt"
 	"net/url"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"cloudamqp-cli/client"
+	"cloudamqp-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -21,72 +25,326 @@ func maskPassword(urlStr string) string {
 }
 
 var instanceGetCmd = &cobra.Command{
-	Use:     "get --id <id>",
-	Short:   "Get details of a specific CloudAMQP instance",
-	Long:    `Retrieves and displays detailed information about a specific CloudAMQP instance.`,
-	Example: `  cloudamqp instance get --id 1234`,
+	Use:   "get --id <id>",
+	Short: "Get details of a specific CloudAMQP instance",
+	Long: `Retrieves and displays detailed information about a specific CloudAMQP instance.
+
+Use --select to show only the given instance attributes, in the order given.
+Names match the instance attributes (e.g. Name, Plan, Region, Tags, URL,
+Ready, RMQVersion, HostnameExternal, or their JSON names) case-insensitively;
+unknown names are an error. Credentials stay masked: the URL password unless
+--show-url is given, and the API key always. The global --fields still picks
+columns of the default view.
+
+Use --watch to re-fetch and redisplay the instance every --watch-interval
+(default 5s) until interrupted with Ctrl-C.
+
+An instance name given to --id is always resolved against a fresh instance
+list from the API, never the completion cache. --refresh also stores that
+list in the completion cache, so completion stops suggesting instances that
+were renamed or deleted.
+
+--id-file reads instance IDs from a file, one per line (blank lines and #
+comments are ignored), and prints all of them, plus the --id instance if
+given, as a list.
+
+--include fetches related resources of the instance concurrently and embeds
+them in the output under their own keys: config, plugins, nodes and alarms.
+Only the resources asked for are fetched. It needs -o json or -o yaml.`,
+	Example: `  cloudamqp instance get --id 1234
+  cloudamqp instance get --id 1234 --select Name,Plan,Hostname
+  cloudamqp instance get --id 1234 --select name,rmq_version -o json
+  cloudamqp instance get --id 1234 --watch --watch-interval 10s
+  cloudamqp instance get --id orders --refresh
+  cloudamqp instance get --id-file ids.txt
+  cloudamqp instance get --id 1234 --include config,plugins -o json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		if idFlag == "" && idFilePath(cmd) == "" {
+			return fmt.Errorf("instance ID is required. Use --id or --id-file flag")
+		}
+
+		selectNames, _ := cmd.Flags().GetStringSlice("select")
+		fields, err := resolveInstanceFields(selectNames)
+		if err != nil {
+			return err
+		}
+
+		include, err := instanceIncludes(cmd)
+		if err != nil {
+			return err
 		}
 
-		var err error
 		apiKey, err = getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
 		}
 
-		instanceID, err := strconv.Atoi(idFlag)
+		c := newAPIClient(apiKey)
+
+		showURL, _ := cmd.Flags().GetBool("show-url")
+
+		if idFilePath(cmd) != "" {
+			for _, name := range []string{"watch", "refresh", "include"} {
+				if cmd.Flags().Changed(name) {
+					return fmt.Errorf("--%s cannot be used with --id-file", name)
+				}
+			}
+			return printInstanceBatch(cmd, c, idFlag, fields, showURL)
+		}
+
+		var instanceID int
+		if refresh, _ := cmd.Flags().GetBool("refresh"); refresh {
+			instanceID, err = resolveInstanceRefreshingCache(c, idFlag)
+		} else {
+			instanceID, err = resolveInstance(c, idFlag)
+		}
 		if err != nil {
-			return fmt.Errorf("invalid instance ID: %v", err)
+			return err
 		}
 
-		c := client.New(apiKey, Version)
+		watch, _ := cmd.Flags().GetBool("watch")
+		if watch && len(include) > 0 {
+			return fmt.Errorf("--include cannot be used with --watch")
+		}
+		if watch {
+			interval, _ := cmd.Flags().GetDuration("watch-interval")
+			if interval <= 0 {
+				return fmt.Errorf("--watch-interval must be positive")
+			}
+			return watchLoop(commandContext(cmd), cmd.OutOrStdout(), interval, func() error {
+				instance, err := getInstance(c, instanceID)
+				if err != nil {
+					return err
+				}
+				return printInstance(cmd, instance, fields, showURL)
+			})
+		}
 
-		instance, err := c.GetInstance(instanceID)
+		instance, err := getInstance(c, instanceID)
 		if err != nil {
-			fmt.Printf("Error getting instance: %v\n", err)
-			return err
+			return fmt.Errorf("failed to get instance: %w", err)
+		}
+
+		if len(include) > 0 {
+			return printInstanceWithIncludes(cmd, c, instance, include, showURL)
 		}
+		return printInstance(cmd, instance, fields, showURL)
+	},
+}
+
+// resolveInstanceRefreshingCache resolves idOrName like resolveInstance but
+// always lists the instances, and stores the list in the completion cache so
+// completion stops suggesting instances that were renamed or deleted.
+func resolveInstanceRefreshingCache(c client.ClientAPI, idOrName string) (int, error) {
+	instances, err := c.ListInstances()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list instances: %w", err)
+	}
+	if err := setCachedData(apiKey, "instances", instancesCacheTTL, instances); err != nil {
+		logWarn("failed to update the completion cache: %v", err)
+	}
+
+	idOrName = strings.TrimSpace(idOrName)
+	if id, err := strconv.Atoi(idOrName); err == nil {
+		return id, nil
+	}
+	return matchInstanceName(instances, idOrName)
+}
 
-		p, err := getPrinter(cmd)
+// printInstance renders an instance, limited to fields when any are given.
+func printInstance(cmd *cobra.Command, instance *client.Instance, fields []reflect.StructField, showURL bool) error {
+	if len(fields) > 0 {
+		// The fields are already projected, so don't filter them again
+		p, err := newPrinter(cmd, nil, false)
 		if err != nil {
 			return err
 		}
+		p.PrintRecord(projectInstance(instance, fields, showURL))
+		return nil
+	}
 
-		showURL, _ := cmd.Flags().GetBool("show-url")
-		ready := "No"
-		if instance.Ready {
-			ready = "Yes"
-		}
-
-		urlVal := maskPassword(instance.URL)
-		if showURL {
-			urlVal = instance.URL
-		}
-
-		p.PrintRecord(
-			[]string{"ID", "NAME", "PLAN", "REGION", "TAGS", "URL", "HOSTNAME", "READY"},
-			[]string{
-				strconv.Itoa(instance.ID),
-				instance.Name,
-				instance.Plan,
-				instance.Region,
-				strings.Join(instance.Tags, ","),
-				urlVal,
-				instance.HostnameExternal,
-				ready,
-			},
-		)
+	p, err := getPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	p.PrintRecord(instanceRecord(instance, showURL))
+	return nil
+}
 
-		return nil
-	},
+// instanceIncludeNames are the related resources --include can embed.
+var instanceIncludeNames = []string{"config", "plugins", "nodes", "alarms"}
+
+// instanceIncludes returns the resources given to --include, checked against
+// instanceIncludeNames and the other flags.
+func instanceIncludes(cmd *cobra.Command) ([]string, error) {
+	include, _ := cmd.Flags().GetStringSlice("include")
+	var names []string
+	for _, name := range include {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || slices.Contains(names, name) {
+			continue
+		}
+		if !slices.Contains(instanceIncludeNames, name) {
+			if suggestion := suggestClosest(name, instanceIncludeNames); suggestion != "" {
+				return nil, fmt.Errorf("unknown --include %q; did you mean %q?", name, suggestion)
+			}
+			return nil, fmt.Errorf("unknown --include %q; valid values are %s", name, strings.Join(instanceIncludeNames, ", "))
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	if cmd.Flags().Changed("select") {
+		return nil, fmt.Errorf("--include cannot be used with --select")
+	}
+	if format, _ := cmd.Flags().GetString("output"); output.Format(format) != output.FormatJSON && output.Format(format) != output.FormatYAML {
+		return nil, fmt.Errorf("--include needs -o json or -o yaml")
+	}
+	return names, nil
+}
+
+// printInstanceWithIncludes fetches the included resources concurrently and
+// prints them as JSON or YAML alongside the default instance fields.
+func printInstanceWithIncludes(cmd *cobra.Command, c client.ClientAPI, instance *client.Instance, include []string, showURL bool) error {
+	id := strconv.Itoa(instance.ID)
+	var (
+		config  map[string]interface{}
+		plugins []client.Plugin
+		nodes   []client.Node
+		alarms  []client.Alarm
+	)
+	fetchers := map[string]func() error{
+		"config": func() error {
+			backend, err := fetchedInstanceBackend(c, instance)
+			if err != nil {
+				return err
+			}
+			config, err = getInstanceConfig(c, backend, id)
+			return err
+		},
+		"plugins": func() (err error) {
+			plugins, err = c.ListPlugins(id)
+			return err
+		},
+		"nodes": func() (err error) {
+			nodes, err = c.ListNodes(id)
+			return err
+		},
+		"alarms": func() (err error) {
+			alarms, err = c.ListAlarms(id)
+			return err
+		},
+	}
+	for name := range fetchers {
+		if !slices.Contains(include, name) {
+			delete(fetchers, name)
+		}
+	}
+
+	errs := fetchConcurrently(fetchers)
+	if names := sortedKeys(errs); len(names) > 0 {
+		return fmt.Errorf("failed to get %s: %w", names[0], errs[names[0]])
+	}
+
+	headers, values := instanceRecord(instance, showURL)
+	record := make(map[string]any, len(headers)+len(include))
+	for i, h := range headers {
+		record[strings.ToLower(h)] = values[i]
+	}
+	for _, name := range include {
+		switch name {
+		case "config":
+			record[name] = config
+		case "plugins":
+			record[name] = plugins
+		case "nodes":
+			record[name] = nodes
+		case "alarms":
+			record[name] = alarms
+		}
+	}
+
+	// The record nests the included resources, so --fields doesn't apply
+	p, err := newPrinter(cmd, nil, false)
+	if err != nil {
+		return err
+	}
+	p.PrintValue(record)
+	return p.Err()
+}
+
+// printInstanceBatch fetches the instances selected with --id and --id-file
+// and prints them as a list, one row per instance, in the order given.
+func printInstanceBatch(cmd *cobra.Command, c client.ClientAPI, idFlag string, fields []reflect.StructField, showURL bool) error {
+	batch, err := batchInstances(cmd, c, idFlag, nil)
+	if err != nil {
+		return err
+	}
+
+	var headers []string
+	rows := make([][]string, len(batch))
+	for i, summary := range batch {
+		instance, err := getInstance(c, summary.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get instance %d: %w", summary.ID, err)
+		}
+		if len(fields) > 0 {
+			headers, rows[i] = projectInstance(instance, fields, showURL)
+		} else {
+			headers, rows[i] = instanceRecord(instance, showURL)
+		}
+	}
+
+	var p *output.Printer
+	if len(fields) > 0 {
+		p, err = newPrinter(cmd, nil, true)
+	} else {
+		p, err = getListPrinter(cmd)
+	}
+	if err != nil {
+		return err
+	}
+	p.PrintRecords(headers, rows)
+	return nil
+}
+
+// instanceRecord is the default view of an instance for get.
+func instanceRecord(instance *client.Instance, showURL bool) ([]string, []string) {
+	ready := "No"
+	if instance.Ready {
+		ready = "Yes"
+	}
+
+	urlVal := maskPassword(instance.URL)
+	if showURL {
+		urlVal = instance.URL
+	}
+
+	return []string{"ID", "NAME", "PLAN", "REGION", "TAGS", "URL", "HOSTNAME", "READY"},
+		[]string{
+			strconv.Itoa(instance.ID),
+			instance.Name,
+			instance.Plan,
+			instance.Region,
+			strings.Join(instance.Tags, ","),
+			urlVal,
+			instance.HostnameExternal,
+			ready,
+		}
 }
 
 func init() {
-	instanceGetCmd.Flags().StringP("id", "", "", "Instance ID (required)")
-	instanceGetCmd.MarkFlagRequired("id")
+	instanceGetCmd.Flags().StringP("id", "", "", "Instance ID or name (required unless --id-file is given)")
+	addIDFileFlag(instanceGetCmd)
+	instanceGetCmd.MarkFlagsOneRequired("id", "id-file")
 	instanceGetCmd.Flags().BoolP("show-url", "", false, "Show full connection URL with credentials")
+	instanceGetCmd.Flags().StringSlice("select", nil, "Instance attributes to show, e.g. Name,Plan,Hostname (case-insensitive)")
+	instanceGetCmd.Flags().Bool("watch", false, "Re-fetch and redisplay the instance until interrupted")
+	instanceGetCmd.Flags().Duration("watch-interval", 5*time.Second, "Refresh interval for --watch")
+	instanceGetCmd.Flags().Bool("refresh", false, "List instances from the API and update the completion cache with them")
+	instanceGetCmd.Flags().StringSlice("include", nil, "Related resources to embed with -o json or -o yaml: "+strings.Join(instanceIncludeNames, ", "))
 	instanceGetCmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
 }
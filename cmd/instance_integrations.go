@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// metricsIntegrationParams maps each metrics integration type to the
+// parameters it requires.
+var metricsIntegrationParams = map[string][]string{
+	"azure_monitor": {"connection_string"},
+	"cloudwatch":    {"region", "access_key_id", "secret_access_key"},
+	"cloudwatch_v2": {"region", "access_key_id", "secret_access_key"},
+	"datadog":       {"api_key", "region"},
+	"datadog_v2":    {"api_key", "region"},
+	"dynatrace":     {"environment_id", "access_token"},
+	"librato":       {"email", "api_key"},
+	"newrelic_v2":   {"api_key", "region"},
+	"splunk":        {"token", "host_port"},
+	"stackdriver":   {"project_id", "client_email", "private_key"},
+}
+
+var instanceIntegrationsCmd = &cobra.Command{
+	Use:   "integrations",
+	Short: "Manage metrics integrations",
+	Long:  `List, add, and delete integrations that export metrics to third-party services.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Help()
+		cmd.SilenceUsage = true
+		return fmt.Errorf("subcommand required")
+	},
+}
+
+var instanceIntegrationsListCmd = &cobra.Command{
+	Use:     "list --id <instance_id>",
+	Aliases: []string{"ls"},
+	Short:   "List metrics integrations",
+	Long:    `Retrieves all metrics integrations for the instance. Secrets are redacted.`,
+	Example: `  cloudamqp instance integrations list --id 1234`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		integrations, err := c.ListIntegrations(idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to list integrations: %w", err)
+		}
+
+		return printIntegrations(cmd, integrations)
+	},
+}
+
+var instanceIntegrationsAddCmd = &cobra.Command{
+	Use:   "add --id <instance_id> --type <type>",
+	Short: "Add a metrics integration",
+	Long: `Add an integration that exports metrics to a third-party service.
+
+Required parameters per type:
+` + integrationParamsHelp(metricsIntegrationParams) + `
+Optional parameters (e.g. tags) can be passed with --param key=value.`,
+	Example: `  cloudamqp instance integrations add --id 1234 --type datadog --api-key XXX --region us
+  cloudamqp instance integrations add --id 1234 --type cloudwatch --region us-east-1 --access-key-id AKIA... --secret-access-key ...`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		integrationType, _ := cmd.Flags().GetString("type")
+		params, err := collectIntegrationParams(cmd, integrationType, metricsIntegrationParams)
+		if err != nil {
+			return err
+		}
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "POST", "/instances/"+idFlag+"/integrations/metrics/"+integrationType, params)
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		resp, err := c.CreateIntegration(idFlag, integrationType, params)
+		if err != nil {
+			return fmt.Errorf("failed to add integration: %w", err)
+		}
+
+		output, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format response: %v", err)
+		}
+
+		printStatus(cmd, "Integration added successfully.")
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
+		return nil
+	},
+}
+
+var instanceIntegrationsDeleteCmd = &cobra.Command{
+	Use:     "delete --id <instance_id> --integration-id <integration_id>",
+	Aliases: []string{"rm"},
+	Short:   "Delete a metrics integration",
+	Long:    `Deletes a metrics integration from the instance.`,
+	Example: `  cloudamqp instance integrations delete --id 1234 --integration-id 3`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		integrationID, _ := cmd.Flags().GetInt("integration-id")
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "DELETE", "/instances/"+idFlag+"/integrations/metrics/"+strconv.Itoa(integrationID), nil)
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		err = c.DeleteIntegration(idFlag, integrationID)
+		if err != nil {
+			return fmt.Errorf("failed to delete integration: %w", err)
+		}
+
+		printStatus(cmd, "Integration %d deleted successfully.", integrationID)
+		return nil
+	},
+}
+
+// integrationParamFlag returns the flag name used for an integration
+// parameter, e.g. api_key -> api-key.
+func integrationParamFlag(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+// integrationParamKeys returns the sorted union of all parameters in specs.
+func integrationParamKeys(specs map[string][]string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, required := range specs {
+		for _, key := range required {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// integrationTypes returns the sorted integration types in specs.
+func integrationTypes(specs map[string][]string) []string {
+	types := make([]string, 0, len(specs))
+	for t := range specs {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// integrationParamsHelp renders the required parameters of each type for
+// use in a command's long description.
+func integrationParamsHelp(specs map[string][]string) string {
+	var sb strings.Builder
+	for _, t := range integrationTypes(specs) {
+		flags := make([]string, len(specs[t]))
+		for i, key := range specs[t] {
+			flags[i] = "--" + integrationParamFlag(key)
+		}
+		fmt.Fprintf(&sb, "  %-14s %s\n", t+":", strings.Join(flags, ", "))
+	}
+	return sb.String()
+}
+
+// addIntegrationParamFlags registers --type, --param and one flag per
+// known parameter on an integration add command.
+func addIntegrationParamFlags(cmd *cobra.Command, specs map[string][]string) {
+	cmd.Flags().String("type", "", "Integration type (required)")
+	cmd.Flags().StringToString("param", map[string]string{}, "Additional parameter as key=value (can be repeated)")
+	for _, key := range integrationParamKeys(specs) {
+		cmd.Flags().String(integrationParamFlag(key), "", fmt.Sprintf("Integration parameter %s", key))
+	}
+	cmd.MarkFlagRequired("type")
+	cmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return integrationTypes(specs), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// collectIntegrationParams gathers the parameters given on the command line
+// and checks that every parameter required by integrationType is present.
+func collectIntegrationParams(cmd *cobra.Command, integrationType string, specs map[string][]string) (map[string]string, error) {
+	required, ok := specs[integrationType]
+	if !ok {
+		return nil, fmt.Errorf("unknown integration type %q. Valid types are: %s", integrationType, strings.Join(integrationTypes(specs), ", "))
+	}
+
+	params := map[string]string{}
+	extra, _ := cmd.Flags().GetStringToString("param")
+	for k, v := range extra {
+		params[k] = v
+	}
+	for _, key := range integrationParamKeys(specs) {
+		if value, _ := cmd.Flags().GetString(integrationParamFlag(key)); value != "" {
+			params[key] = value
+		}
+	}
+
+	var missing []string
+	for _, key := range required {
+		if params[key] == "" {
+			missing = append(missing, "--"+integrationParamFlag(key))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required parameters for %s: %s", integrationType, strings.Join(missing, ", "))
+	}
+
+	return params, nil
+}
+
+// formatIntegrationParams renders params as sorted key=value pairs with
+// secrets redacted.
+func formatIntegrationParams(params map[string]interface{}) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if params[k] == nil {
+			continue
+		}
+		value := fmt.Sprintf("%v", params[k])
+		if client.IsSecretKey(k) {
+			value = "****"
+		}
+		pairs = append(pairs, k+"="+value)
+	}
+	return strings.Join(pairs, " ")
+}
+
+func printIntegrations(cmd *cobra.Command, integrations []client.Integration) error {
+	if len(integrations) == 0 {
+		printStatus(cmd, "No integrations found.")
+		return nil
+	}
+
+	p, err := getListPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
+	headers := []string{"ID", "TYPE", "PARAMS"}
+	rows := make([][]string, len(integrations))
+	for i, integration := range integrations {
+		rows[i] = []string{
+			strconv.Itoa(integration.ID),
+			integration.Type,
+			formatIntegrationParams(integration.Params),
+		}
+	}
+	p.PrintRecords(headers, rows)
+
+	return nil
+}
+
+func init() {
+	instanceIntegrationsListCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceIntegrationsListCmd.MarkFlagRequired("id")
+
+	instanceIntegrationsAddCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	addIntegrationParamFlags(instanceIntegrationsAddCmd, metricsIntegrationParams)
+	addDryRunFlag(instanceIntegrationsAddCmd)
+	instanceIntegrationsAddCmd.MarkFlagRequired("id")
+
+	instanceIntegrationsDeleteCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceIntegrationsDeleteCmd.Flags().Int("integration-id", 0, "Integration ID (required)")
+	addDryRunFlag(instanceIntegrationsDeleteCmd)
+	instanceIntegrationsDeleteCmd.MarkFlagRequired("id")
+	instanceIntegrationsDeleteCmd.MarkFlagRequired("integration-id")
+
+	for _, cmd := range []*cobra.Command{instanceIntegrationsListCmd, instanceIntegrationsAddCmd, instanceIntegrationsDeleteCmd} {
+		cmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+	}
+
+	instanceIntegrationsCmd.AddCommand(instanceIntegrationsListCmd)
+	instanceIntegrationsCmd.AddCommand(instanceIntegrationsAddCmd)
+	instanceIntegrationsCmd.AddCommand(instanceIntegrationsDeleteCmd)
+}
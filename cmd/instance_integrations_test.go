@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIntegrationAddCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "add"}
+	addIntegrationParamFlags(cmd, metricsIntegrationParams)
+	require.NoError(t, cmd.ParseFlags(args))
+	return cmd
+}
+
+func TestCollectIntegrationParams(t *testing.T) {
+	cmd := newIntegrationAddCmd(t, "--type", "datadog", "--api-key", "XXX", "--region", "us", "--param", "tags=env:prod")
+
+	params, err := collectIntegrationParams(cmd, "datadog", metricsIntegrationParams)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"api_key": "XXX", "region": "us", "tags": "env:prod"}, params)
+}
+
+func TestCollectIntegrationParams_Missing(t *testing.T) {
+	cmd := newIntegrationAddCmd(t, "--type", "cloudwatch", "--region", "us-east-1")
+
+	_, err := collectIntegrationParams(cmd, "cloudwatch", metricsIntegrationParams)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required parameters for cloudwatch: --access-key-id, --secret-access-key")
+}
+
+func TestCollectIntegrationParams_UnknownType(t *testing.T) {
+	cmd := newIntegrationAddCmd(t, "--type", "graphite")
+
+	_, err := collectIntegrationParams(cmd, "graphite", metricsIntegrationParams)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown integration type")
+}
+
+func TestFormatIntegrationParams_RedactsSecrets(t *testing.T) {
+	out := formatIntegrationParams(map[string]interface{}{
+		"region":            "us",
+		"api_key":           "XXX",
+		"secret_access_key": "YYY",
+	})
+	assert.Equal(t, "api_key=**** region=us secret_access_key=****", out)
+}
+
+func TestInstanceIntegrationsAddCmd_DryRunRedactsSecrets(t *testing.T) {
+	t.Setenv("CLOUDAMQP_APIKEY", "")
+	t.Setenv("HOME", t.TempDir())
+
+	cmd := instanceIntegrationsAddCmd
+	cmd.InheritedFlags() // merge --output from root, as Execute would
+	require.NoError(t, cmd.ParseFlags([]string{"--id", "1234", "--type", "datadog", "--api-key", "XXX", "--region", "us", "--dry-run"}))
+	rootCmd.PersistentFlags().Set("output", "json")
+	defer func() {
+		resetFlags(cmd)
+		rootCmd.PersistentFlags().Set("output", "table")
+	}()
+
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+
+	var record map[string]string
+	require.NoError(t, json.Unmarshal([]byte(out), &record))
+	assert.JSONEq(t, `{"api_key":"REDACTED","region":"us"}`, record["body"])
+	assert.NotContains(t, out, "XXX")
+}
+
+func TestCollectIntegrationParams_LogTypes(t *testing.T) {
+	cmd := &cobra.Command{Use: "add"}
+	addIntegrationParamFlags(cmd, logIntegrationParams)
+	require.NoError(t, cmd.ParseFlags([]string{"--type", "cloudwatchlog", "--region", "eu-west-1"}))
+
+	_, err := collectIntegrationParams(cmd, "cloudwatchlog", logIntegrationParams)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--access-key-id, --secret-access-key")
+}
+
+func TestValidateHostPort(t *testing.T) {
+	assert.NoError(t, validateHostPort("logs.papertrailapp.com:12345"))
+
+	assert.Error(t, validateHostPort("logs.papertrailapp.com"))
+	assert.Error(t, validateHostPort(":12345"))
+	assert.Error(t, validateHostPort("logs.papertrailapp.com:http"))
+	assert.Error(t, validateHostPort("logs.papertrailapp.com:99999"))
+}
@@ -2,118 +2,369 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"cloudamqp-cli/client"
+	"cloudamqp-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 var instanceListCmd = &cobra.Command{
 	Use:     "list",
+	Aliases: []string{"ls"},
 	Short:   "List all CloudAMQP instances",
-	Long:    `Retrieves and displays all CloudAMQP instances in your account.`,
-	Example: `  cloudamqp instance list`,
+	Long: `Retrieves and displays all CloudAMQP instances in your account.
+
+Instances are sorted by name; use --sort id|name|plan|region to choose the
+sort key and --reverse for descending order.
+
+Use --columns to pick and order the columns from id, name, plan, region,
+tags, tier, nodes, backend, url, hostname, version and ready. The url,
+hostname, version and ready columns need a request per instance, as with
+--details. tier, nodes and backend come from the list itself and are empty
+when the API doesn't include them.
+
+-o wide is a preset for id, name, plan, region, tags, hostname, version and
+ready, without having to list them with --columns. -o table stays compact.
+
+Use --state ready or --state configuring to list only instances that are
+ready, or still being set up. Like --details, this needs a request per
+instance; the default, --state all, doesn't.
+
+--tag lists only instances with all of the given tags, and --created-before
+and --created-after only those created in that period; both take a duration
+ago such as 7d or a time such as 2026-10-16T10:00:00Z. Instances whose
+creation time the API doesn't report are left out by these two.
+
+--limit applies after the filters and the sort, so --limit 10 shows the
+first 10 matching instances in the chosen order.`,
+	Example: `  cloudamqp instance list
+  cloudamqp instance list -q   # one instance ID per line
+  cloudamqp instance list --limit 10
+  cloudamqp instance list --sort plan --reverse
+  cloudamqp instance list --columns id,name,plan,hostname
+  cloudamqp instance list -o wide
+  cloudamqp instance list --state=ready
+  cloudamqp instance list --state=configuring -q
+  cloudamqp instance list --tag test --created-before 7d -q > stale.txt`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
-		apiKey, err = getAPIKey()
+		opts, err := getListOptions(cmd)
 		if err != nil {
-			return fmt.Errorf("failed to get API key: %w", err)
+			return err
 		}
 
-		c := client.New(apiKey, Version)
+		sortKey, _ := cmd.Flags().GetString("sort")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+		less, err := instanceSortFunc(sortKey)
+		if err != nil {
+			return err
+		}
 
-		instances, err := c.ListInstances()
+		details, _ := cmd.Flags().GetBool("details")
+		columnNames, _ := cmd.Flags().GetStringSlice("columns")
+		format, _ := cmd.Flags().GetString("output")
+		columns, err := parseInstanceListColumns(columnNames, details, output.Format(format) == output.FormatWide)
 		if err != nil {
-			fmt.Printf("Error listing instances: %v\n", err)
 			return err
 		}
 
-		if len(instances) == 0 {
-			fmt.Println("No instances found.")
-			return nil
+		state, _ := cmd.Flags().GetString("state")
+		if !slices.Contains(instanceStates, state) {
+			return fmt.Errorf("invalid --state %q: must be one of %s", state, strings.Join(instanceStates, ", "))
 		}
 
-		p, err := getPrinter(cmd)
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		createdAfter, createdBefore, err := timeRange(cmd, "created-after", "created-before")
 		if err != nil {
 			return err
 		}
 
-		details, _ := cmd.Flags().GetBool("details")
+		apiKey, err = getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
 
-		if details {
-			showURL, _ := cmd.Flags().GetBool("show-url")
-			detailed := make([]*client.Instance, len(instances))
-			headers := []string{"ID", "NAME", "PLAN", "REGION", "TAGS", "URL", "HOSTNAME", "READY"}
-			rows := make([][]string, len(instances))
-			var (
-				mu       sync.Mutex
-				firstErr error
-				wg       sync.WaitGroup
-			)
-			for i, instance := range instances {
-				wg.Add(1)
-				go func(idx, id int) {
-					defer wg.Done()
-					det, err := c.GetInstance(id)
-					mu.Lock()
-					defer mu.Unlock()
-					if err != nil {
-						if firstErr == nil {
-							firstErr = fmt.Errorf("error fetching instance %d: %w", id, err)
-						}
-						return
-					}
-					detailed[idx] = det
-				}(i, instance.ID)
+		c := newAPIClient(apiKey)
+
+		// Filtering and sorting happen here, so --limit is applied after
+		// them rather than to the pages fetched
+		limit := opts.Limit
+		opts.Limit = 0
+		instances, err := c.ListInstancesWithOptions(opts)
+		if err != nil {
+			return fmt.Errorf("failed to list instances: %w", err)
+		}
+		if len(tags) > 0 {
+			instances = instancesWithTags(instances, tags)
+		}
+		if !createdAfter.IsZero() || !createdBefore.IsZero() {
+			instances = instancesCreatedIn(instances, createdAfter, createdBefore)
+		}
+		sortInstances(instances, less, reverse)
+
+		// Only the details say whether an instance is ready
+		var detailed []*client.Instance
+		if state != "all" {
+			if detailed, err = fetchInstanceDetails(c, instances); err != nil {
+				return err
 			}
-			wg.Wait()
-			if firstErr != nil {
-				return firstErr
+			instances, detailed = filterInstancesByState(instances, detailed, state)
+		}
+		if limit > 0 && len(instances) > limit {
+			instances = instances[:limit]
+			if detailed != nil {
+				detailed = detailed[:limit]
 			}
+		}
 
-			for i, inst := range detailed {
-				ready := "No"
-				if inst.Ready {
-					ready = "Yes"
-				}
-				urlVal := maskPassword(inst.URL)
-				if showURL {
-					urlVal = inst.URL
-				}
-				rows[i] = []string{
-					strconv.Itoa(inst.ID),
-					inst.Name,
-					inst.Plan,
-					inst.Region,
-					strings.Join(inst.Tags, ","),
-					urlVal,
-					inst.HostnameExternal,
-					ready,
-				}
+		if isQuiet(cmd) {
+			for _, instance := range instances {
+				fmt.Fprintln(cmd.OutOrStdout(), instance.ID)
 			}
-			p.PrintRecords(headers, rows)
 			return nil
 		}
 
-		headers := []string{"ID", "NAME", "PLAN", "REGION"}
-		rows := make([][]string, len(instances))
-		for i, instance := range instances {
-			rows[i] = []string{
-				strconv.Itoa(instance.ID),
-				instance.Name,
-				instance.Plan,
-				instance.Region,
+		if len(instances) == 0 {
+			printStatus(cmd, "No instances found.")
+			return nil
+		}
+
+		p, err := getListPrinter(cmd)
+		if err != nil {
+			return err
+		}
+		p.StyleColumn("READY", readyStyle)
+
+		list := make([]*client.Instance, len(instances))
+		for i := range instances {
+			list[i] = &instances[i]
+		}
+		if detailed != nil {
+			list = detailed
+		} else if details || columnsNeedDetails(columns) {
+			if list, err = fetchInstanceDetails(c, instances); err != nil {
+				return err
 			}
 		}
-		p.PrintRecords(headers, rows)
 
+		showURL, _ := cmd.Flags().GetBool("show-url")
+		headers := make([]string, len(columns))
+		for i, name := range columns {
+			headers[i] = strings.ToUpper(name)
+		}
+		rows := make([][]string, len(list))
+		for i, inst := range list {
+			rows[i] = make([]string, len(columns))
+			for j, name := range columns {
+				rows[i][j] = instanceListColumns[name].value(inst, showURL)
+			}
+		}
+		p.PrintRecords(headers, rows)
 		return nil
 	},
 }
 
+// instanceListColumn is a column that instance list can show with --columns.
+type instanceListColumn struct {
+	value func(inst *client.Instance, showURL bool) string
+	// details is set for columns only filled in by a GET per instance.
+	details bool
+}
+
+var instanceListColumns = map[string]instanceListColumn{
+	"id":     {value: func(inst *client.Instance, _ bool) string { return strconv.Itoa(inst.ID) }},
+	"name":   {value: func(inst *client.Instance, _ bool) string { return inst.Name }},
+	"plan":   {value: func(inst *client.Instance, _ bool) string { return inst.Plan }},
+	"region": {value: func(inst *client.Instance, _ bool) string { return inst.Region }},
+	"tags":   {value: func(inst *client.Instance, _ bool) string { return strings.Join(inst.Tags, ",") }},
+	"tier":   {value: func(inst *client.Instance, _ bool) string { return inst.PlanTier }},
+	"nodes": {value: func(inst *client.Instance, _ bool) string {
+		if inst.Nodes == 0 {
+			return ""
+		}
+		return strconv.Itoa(inst.Nodes)
+	}},
+	"backend": {value: func(inst *client.Instance, _ bool) string { return inst.Backend }},
+	"url": {details: true, value: func(inst *client.Instance, showURL bool) string {
+		if showURL {
+			return inst.URL
+		}
+		return maskPassword(inst.URL)
+	}},
+	"hostname": {details: true, value: func(inst *client.Instance, _ bool) string { return inst.HostnameExternal }},
+	"version":  {details: true, value: func(inst *client.Instance, _ bool) string { return inst.RMQVersion }},
+	"ready": {details: true, value: func(inst *client.Instance, _ bool) string {
+		if inst.Ready {
+			return "Yes"
+		}
+		return "No"
+	}},
+}
+
+var (
+	defaultInstanceListColumns = []string{"id", "name", "plan", "region"}
+	detailInstanceListColumns  = []string{"id", "name", "plan", "region", "tags", "url", "hostname", "ready"}
+	wideInstanceListColumns    = []string{"id", "name", "plan", "region", "tags", "hostname", "version", "ready"}
+	// allInstanceListColumns are the valid --columns, in the order listed
+	// in errors.
+	allInstanceListColumns = []string{"id", "name", "plan", "region", "tags", "tier", "nodes", "backend", "url", "hostname", "version", "ready"}
+)
+
+// parseInstanceListColumns validates --columns, which picks and orders the
+// columns. Without it the -o wide, --details or default set is used.
+func parseInstanceListColumns(names []string, details, wide bool) ([]string, error) {
+	if len(names) == 0 {
+		if wide {
+			return wideInstanceListColumns, nil
+		}
+		if details {
+			return detailInstanceListColumns, nil
+		}
+		return defaultInstanceListColumns, nil
+	}
+
+	columns := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := instanceListColumns[name]; !ok {
+			if suggestion := suggestClosest(name, allInstanceListColumns); suggestion != "" {
+				return nil, fmt.Errorf("unknown column %q, did you mean %q?", name, suggestion)
+			}
+			return nil, fmt.Errorf("unknown column %q. Valid columns are: %s", name, strings.Join(allInstanceListColumns, ", "))
+		}
+		columns = append(columns, name)
+	}
+	return columns, nil
+}
+
+// columnsNeedDetails reports whether any of columns needs --details data.
+func columnsNeedDetails(columns []string) bool {
+	for _, name := range columns {
+		if instanceListColumns[name].details {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchInstanceDetails gets every instance, at most concurrencyLimit at a
+// time, keeping the order.
+func fetchInstanceDetails(c client.ClientAPI, instances []client.Instance) ([]*client.Instance, error) {
+	detailed := make([]*client.Instance, len(instances))
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	sem := make(chan struct{}, concurrencyLimit())
+	for i, instance := range instances {
+		wg.Add(1)
+		go func(idx, id int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			det, err := getInstance(c, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("error fetching instance %d: %w", id, err)
+				}
+				return
+			}
+			detailed[idx] = det
+		}(i, instance.ID)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return detailed, nil
+}
+
+// instanceStates are the values of instance list --state.
+var instanceStates = []string{"all", "ready", "configuring"}
+
+// filterInstancesByState keeps the instances, and their details, that are
+// ready for state "ready" or not ready yet for "configuring".
+func filterInstancesByState(instances []client.Instance, detailed []*client.Instance, state string) ([]client.Instance, []*client.Instance) {
+	var keptInstances []client.Instance
+	var keptDetails []*client.Instance
+	for i, inst := range detailed {
+		if inst.Ready == (state == "ready") {
+			keptInstances = append(keptInstances, instances[i])
+			keptDetails = append(keptDetails, inst)
+		}
+	}
+	return keptInstances, keptDetails
+}
+
+// instanceSortKeys maps the --sort keys of instance list to a comparison.
+var instanceSortKeys = map[string]func(a, b client.Instance) bool{
+	"id":     func(a, b client.Instance) bool { return a.ID < b.ID },
+	"name":   func(a, b client.Instance) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) },
+	"plan":   func(a, b client.Instance) bool { return a.Plan < b.Plan },
+	"region": func(a, b client.Instance) bool { return a.Region < b.Region },
+}
+
+// instanceSortFunc returns the comparison for a --sort key.
+func instanceSortFunc(key string) (func(a, b client.Instance) bool, error) {
+	less, ok := instanceSortKeys[strings.ToLower(strings.TrimSpace(key))]
+	if !ok {
+		return nil, fmt.Errorf("invalid --sort %q: must be one of %s", key, strings.Join(sortedKeys(instanceSortKeys), ", "))
+	}
+	return less, nil
+}
+
+// sortInstances sorts instances with less, ties broken by ID so the order is
+// stable between runs.
+func sortInstances(instances []client.Instance, less func(a, b client.Instance) bool, reverse bool) {
+	sort.SliceStable(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if reverse {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.ID < b.ID
+	})
+}
+
+// instancesCreatedIn returns the instances created at or after since and
+// before until. Instances without a creation time never match.
+func instancesCreatedIn(instances []client.Instance, since, until time.Time) []client.Instance {
+	var matches []client.Instance
+	for _, instance := range instances {
+		if !instance.CreatedAt.IsZero() && inTimeWindow(instance.CreatedAt, since, until) {
+			matches = append(matches, instance)
+		}
+	}
+	return matches
+}
+
 func init() {
+	instanceListCmd.Flags().String("sort", "name", "Sort by id, name, plan or region")
+	instanceListCmd.Flags().Bool("reverse", false, "Reverse the sort order")
+	instanceListCmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions([]string{"id", "name", "plan", "region"}, cobra.ShellCompDirectiveNoFileComp))
 	instanceListCmd.Flags().BoolP("details", "", false, "Fetch full details for each instance (one GET request per instance)")
-	instanceListCmd.Flags().BoolP("show-url", "", false, "Show full connection URL with credentials (requires --details)")
+	instanceListCmd.Flags().BoolP("show-url", "", false, "Show full connection URL with credentials (requires --details or the url column)")
+	instanceListCmd.Flags().String("state", "all", "Only list instances that are ready or configuring (all, ready, configuring)")
+	instanceListCmd.RegisterFlagCompletionFunc("state", cobra.FixedCompletions(instanceStates, cobra.ShellCompDirectiveNoFileComp))
+	instanceListCmd.Flags().StringSlice("tag", nil, "Only list instances with this tag (can be repeated; instances need all tags)")
+	instanceListCmd.Flags().String("created-before", "", "Only list instances created before this time: a duration ago such as 7d, or a time such as 2026-10-16T10:00:00Z")
+	instanceListCmd.Flags().String("created-after", "", "Only list instances created at or after this time, in the same formats as --created-before")
+	instanceListCmd.Flags().StringSlice("columns", nil, "Columns to show, in order (id, name, plan, region, tags, tier, nodes, backend, url, hostname, version, ready)")
+	addListFlags(instanceListCmd)
 }
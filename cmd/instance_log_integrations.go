@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// logIntegrationParams maps each log integration type to the parameters it
+// requires.
+var logIntegrationParams = map[string][]string{
+	"cloudwatchlog": {"access_key_id", "secret_access_key", "region"},
+	"coralogix":     {"private_key", "endpoint", "application"},
+	"datadog":       {"api_key", "region"},
+	"logentries":    {"token"},
+	"loggly":        {"token"},
+	"papertrail":    {"url"},
+	"scalyr":        {"token", "host"},
+	"splunk":        {"token", "host_port"},
+	"stackdriver":   {"project_id", "client_email", "private_key"},
+}
+
+// logIntegrationNames maps log integration types to display names.
+var logIntegrationNames = map[string]string{
+	"cloudwatchlog": "CloudWatch Logs",
+	"coralogix":     "Coralogix",
+	"datadog":       "Datadog",
+	"logentries":    "Logentries",
+	"loggly":        "Loggly",
+	"papertrail":    "Papertrail",
+	"scalyr":        "Scalyr",
+	"splunk":        "Splunk",
+	"stackdriver":   "Google Cloud Logging",
+}
+
+var instanceLogIntegrationsCmd = &cobra.Command{
+	Use:   "log-integrations",
+	Short: "Manage log integrations",
+	Long:  `List, add, and delete integrations that ship broker logs to third-party services.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Help()
+		cmd.SilenceUsage = true
+		return fmt.Errorf("subcommand required")
+	},
+}
+
+var instanceLogIntegrationsListCmd = &cobra.Command{
+	Use:     "list --id <instance_id>",
+	Aliases: []string{"ls"},
+	Short:   "List log integrations",
+	Long:    `Retrieves all log integrations for the instance. Secrets are redacted.`,
+	Example: `  cloudamqp instance log-integrations list --id 1234`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		integrations, err := c.ListLogIntegrations(idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to list log integrations: %w", err)
+		}
+
+		if len(integrations) == 0 {
+			printStatus(cmd, "No log integrations found.")
+			return nil
+		}
+
+		p, err := getListPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		headers := []string{"ID", "NAME", "TYPE", "CONFIGURED"}
+		rows := make([][]string, len(integrations))
+		for i, integration := range integrations {
+			name := logIntegrationNames[integration.Type]
+			if name == "" {
+				name = integration.Type
+			}
+			rows[i] = []string{
+				strconv.Itoa(integration.ID),
+				name,
+				integration.Type,
+				formatIntegrationParams(integration.Params),
+			}
+		}
+		p.PrintRecords(headers, rows)
+
+		return nil
+	},
+}
+
+var instanceLogIntegrationsAddCmd = &cobra.Command{
+	Use:   "add --id <instance_id> --type <type>",
+	Short: "Add a log integration",
+	Long: `Add an integration that ships broker logs to a third-party service.
+
+Required parameters per type:
+` + integrationParamsHelp(logIntegrationParams) + `
+The Papertrail --url is the host:port of your log destination.
+Optional parameters (e.g. tags) can be passed with --param key=value.`,
+	Example: `  cloudamqp instance log-integrations add --id 1234 --type papertrail --url logs.papertrailapp.com:12345
+  cloudamqp instance log-integrations add --id 1234 --type cloudwatchlog --access-key-id AKIA... --secret-access-key ... --region us-east-1`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		integrationType, _ := cmd.Flags().GetString("type")
+		params, err := collectIntegrationParams(cmd, integrationType, logIntegrationParams)
+		if err != nil {
+			return err
+		}
+
+		if integrationType == "papertrail" {
+			if err := validateHostPort(params["url"]); err != nil {
+				return err
+			}
+		}
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "POST", "/instances/"+idFlag+"/integrations/logs/"+integrationType, params)
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		resp, err := c.CreateLogIntegration(idFlag, integrationType, params)
+		if err != nil {
+			return fmt.Errorf("failed to add log integration: %w", err)
+		}
+
+		output, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format response: %v", err)
+		}
+
+		printStatus(cmd, "Log integration added successfully.")
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
+		return nil
+	},
+}
+
+var instanceLogIntegrationsDeleteCmd = &cobra.Command{
+	Use:     "delete --id <instance_id> --integration-id <integration_id>",
+	Aliases: []string{"rm"},
+	Short:   "Delete a log integration",
+	Long:    `Deletes a log integration from the instance.`,
+	Example: `  cloudamqp instance log-integrations delete --id 1234 --integration-id 5`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		integrationID, _ := cmd.Flags().GetInt("integration-id")
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "DELETE", "/instances/"+idFlag+"/integrations/logs/"+strconv.Itoa(integrationID), nil)
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		err = c.DeleteLogIntegration(idFlag, integrationID)
+		if err != nil {
+			return fmt.Errorf("failed to delete log integration: %w", err)
+		}
+
+		printStatus(cmd, "Log integration %d deleted successfully.", integrationID)
+		return nil
+	},
+}
+
+// validateHostPort checks that value has the form host:port.
+func validateHostPort(value string) error {
+	host, port, err := net.SplitHostPort(value)
+	if err != nil || host == "" {
+		return fmt.Errorf("invalid address %q: must be host:port", value)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("invalid port in %q: must be between 1 and 65535", value)
+	}
+	return nil
+}
+
+func init() {
+	instanceLogIntegrationsListCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceLogIntegrationsListCmd.MarkFlagRequired("id")
+
+	instanceLogIntegrationsAddCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	addIntegrationParamFlags(instanceLogIntegrationsAddCmd, logIntegrationParams)
+	addDryRunFlag(instanceLogIntegrationsAddCmd)
+	instanceLogIntegrationsAddCmd.MarkFlagRequired("id")
+
+	instanceLogIntegrationsDeleteCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceLogIntegrationsDeleteCmd.Flags().Int("integration-id", 0, "Integration ID (required)")
+	addDryRunFlag(instanceLogIntegrationsDeleteCmd)
+	instanceLogIntegrationsDeleteCmd.MarkFlagRequired("id")
+	instanceLogIntegrationsDeleteCmd.MarkFlagRequired("integration-id")
+
+	for _, cmd := range []*cobra.Command{instanceLogIntegrationsListCmd, instanceLogIntegrationsAddCmd, instanceLogIntegrationsDeleteCmd} {
+		cmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+	}
+
+	instanceLogIntegrationsCmd.AddCommand(instanceLogIntegrationsListCmd)
+	instanceLogIntegrationsCmd.AddCommand(instanceLogIntegrationsAddCmd)
+	instanceLogIntegrationsCmd.AddCommand(instanceLogIntegrationsDeleteCmd)
+}
@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"cmp"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"cloudamqp-cli/client"
 	"github.com/spf13/cobra"
@@ -10,7 +14,7 @@ import (
 var instanceNodesCmd = &cobra.Command{
 	Use:   "nodes",
 	Short: "Manage instance nodes",
-	Long:  `List nodes and get available versions for the instance.`,
+	Long:  `List and reboot nodes and get available versions for the instance.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.Help()
 		cmd.SilenceUsage = true
@@ -20,35 +24,65 @@ var instanceNodesCmd = &cobra.Command{
 
 var instanceNodesListCmd = &cobra.Command{
 	Use:     "list --id <instance_id>",
+	Aliases: []string{"ls"},
 	Short:   "List nodes in the instance",
-	Long:    `Retrieves all nodes in the instance.`,
-	Example: `  cloudamqp instance nodes list --id 1234`,
+	Long: `Retrieves all nodes in the instance.
+
+Use --node to show a single node with extended details: uptime, memory
+use and breakdown, free disk and partitions.
+
+With --wait-healthy the command polls until every node is configured and
+running, e.g. after an upgrade or restart, then prints the nodes. If that
+doesn't happen within --timeout, the nodes are printed as last seen and the
+command fails naming the nodes that were still unhealthy.`,
+	Example: `  cloudamqp instance nodes list --id 1234
+  cloudamqp instance nodes list --id 1234 --node rabbit@host-01
+  cloudamqp instance nodes list --id 1234 --wait-healthy --timeout 10m`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
 		}
 
-		var err error
 		apiKey, err := getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
 		}
 
-		c := client.New(apiKey, Version)
+		c := newAPIClient(apiKey)
 
-		nodes, err := c.ListNodes(idFlag)
-		if err != nil {
-			fmt.Printf("Error listing nodes: %v\n", err)
-			return err
+		var nodes []client.Node
+		var waitErr error
+		if waitHealthy, _ := cmd.Flags().GetBool("wait-healthy"); waitHealthy {
+			timeoutFlag, _ := cmd.Flags().GetString("timeout")
+			timeout, err := time.ParseDuration(timeoutFlag)
+			if err != nil {
+				return fmt.Errorf("invalid timeout value: %v", err)
+			}
+			nodes, waitErr = waitForNodesHealthy(commandContext(cmd), c, idFlag, timeout)
+			if nodes == nil {
+				return waitErr
+			}
+		} else {
+			nodes, err = c.ListNodes(idFlag)
+			if err != nil {
+				return fmt.Errorf("failed to list nodes: %w", err)
+			}
+		}
+
+		if nodeName, _ := cmd.Flags().GetString("node"); nodeName != "" {
+			if err := printNodeDetails(cmd, c, idFlag, nodes, nodeName); err != nil {
+				return err
+			}
+			return waitErr
 		}
 
 		if len(nodes) == 0 {
-			fmt.Println("No nodes found.")
-			return nil
+			printStatus(cmd, "No nodes found.")
+			return waitErr
 		}
 
-		p, err := getPrinter(cmd)
+		p, err := getListPrinter(cmd)
 		if err != nil {
 			return err
 		}
@@ -75,54 +109,380 @@ var instanceNodesListCmd = &cobra.Command{
 		}
 		p.PrintRecords(headers, rows)
 
-		return nil
+		return waitErr
 	},
 }
 
 var instanceNodesVersionsCmd = &cobra.Command{
 	Use:     "versions --id <instance_id>",
-	Short:   "Get available versions",
-	Long:    `Lists available versions to which the instance can be upgraded. For RabbitMQ instances, shows RabbitMQ and Erlang versions. For LavinMQ instances, shows LavinMQ versions.`,
-	Example: `  cloudamqp instance nodes versions --id 1234`,
+	Aliases: []string{"rabbitmq-versions", "lavinmq-versions"},
+	Short:   "List broker versions available for upgrade",
+	Long: `Lists the broker versions the instance can be upgraded to. The output
+depends on the broker the instance runs:
+
+  RabbitMQ: RabbitMQ and Erlang versions
+  LavinMQ:  LavinMQ versions
+
+The broker is detected from the versions the API returns. Use --backend to
+force it if detection is wrong, e.g. when no upgrades are available.
+
+--check compares the broker version the instance runs with the available
+versions, for monitoring checks. It prints the result and exits 0 if the
+instance is up to date, 10 if an upgrade is available and 4 if the instance
+doesn't exist. Erlang versions are not compared.`,
+	Example: `  cloudamqp instance nodes versions --id 1234
+  cloudamqp instance nodes versions --id 1234 -o json
+  cloudamqp instance nodes versions --id 1234 --backend lavinmq
+  cloudamqp instance nodes versions --id 1234 --check`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		backend, _ := cmd.Flags().GetString("backend")
+		if backend != "" && backend != client.BackendRabbitMQ && backend != client.BackendLavinMQ {
+			return fmt.Errorf("invalid --backend %q: must be %s or %s", backend, client.BackendRabbitMQ, client.BackendLavinMQ)
 		}
 
-		var err error
 		apiKey, err := getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
 		}
 
-		c := client.New(apiKey, Version)
+		c := newAPIClient(apiKey)
+
+		check, _ := cmd.Flags().GetBool("check")
+		var instance *client.Instance
+		if check {
+			instanceID, err := strconv.Atoi(idFlag)
+			if err != nil {
+				return fmt.Errorf("invalid instance ID: %v", err)
+			}
+			instance, err = c.GetInstance(instanceID)
+			if client.IsNotFound(err) || err == nil && instance == nil {
+				cmd.SilenceUsage = true
+				return &exitError{code: exitNotFound, err: fmt.Errorf("instance %d not found", instanceID)}
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get instance: %w", err)
+			}
+		}
 
 		versions, err := c.GetAvailableVersions(idFlag)
 		if err != nil {
-			fmt.Printf("Error getting available versions: %v\n", err)
-			return err
+			return fmt.Errorf("failed to get available versions: %w", err)
+		}
+		if backend != "" {
+			versions.Backend = backend
 		}
 
-		fmt.Printf("Available versions:\n")
-		if len(versions.LavinMQVersions) > 0 {
-			fmt.Printf("LavinMQ versions: %v\n", versions.LavinMQVersions)
+		if check {
+			return checkVersionUpgrade(cmd, instance, versions)
+		}
+
+		if !textOutput(cmd) {
+			p, err := newPrinter(cmd, nil, false)
+			if err != nil {
+				return err
+			}
+			p.PrintValue(versionsOutput(versions))
+			return p.Err()
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Available versions:\n")
+		if versions.Backend == client.BackendLavinMQ {
+			fmt.Fprintf(cmd.OutOrStdout(), "LavinMQ versions: %v\n", versions.LavinMQVersions)
 		} else {
-			fmt.Printf("RabbitMQ versions: %v\n", versions.RabbitMQVersions)
-			fmt.Printf("Erlang versions: %v\n", versions.ErlangVersions)
+			fmt.Fprintf(cmd.OutOrStdout(), "RabbitMQ versions: %v\n", versions.RabbitMQVersions)
+			fmt.Fprintf(cmd.OutOrStdout(), "Erlang versions: %v\n", versions.ErlangVersions)
 		}
 		return nil
 	},
 }
 
+var instanceNodesRebootCmd = &cobra.Command{
+	Use:   "reboot --id <instance_id>",
+	Short: "Reboot nodes in the instance",
+	Long: `Reboots the given nodes, or all nodes of the instance.
+
+With --rolling the nodes are rebooted one at a time: after each reboot the
+command waits until the node has restarted, is running and the management
+API answers before rebooting the next, so the cluster stays available. If a
+node doesn't come back within --timeout the sequence stops, the remaining
+nodes are left untouched and the error names the node that failed.`,
+	Example: `  cloudamqp instance nodes reboot --id 1234
+  cloudamqp instance nodes reboot --id 1234 --rolling
+  cloudamqp instance nodes reboot --id 1234 --rolling --nodes=rabbit@host-01,rabbit@host-02 --timeout=20m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		instanceID, err := flagInstanceID(cmd)
+		if err != nil {
+			return err
+		}
+		idFlag := strconv.Itoa(instanceID)
+
+		rolling, _ := cmd.Flags().GetBool("rolling")
+		timeoutFlag, _ := cmd.Flags().GetString("timeout")
+		timeout, err := time.ParseDuration(timeoutFlag)
+		if err != nil {
+			return fmt.Errorf("invalid timeout value: %v", err)
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		nodesStr, _ := cmd.Flags().GetString("nodes")
+		var nodes []string
+		if nodesStr != "" {
+			nodes = strings.Split(nodesStr, ",")
+		}
+
+		if !rolling {
+			if err := c.RebootInstance(idFlag, nodes); err != nil {
+				return fmt.Errorf("failed to reboot nodes: %w", err)
+			}
+			printStatus(cmd, "Reboot initiated successfully.")
+			return nil
+		}
+
+		if nodes == nil {
+			all, err := c.ListNodes(idFlag)
+			if err != nil {
+				return fmt.Errorf("failed to list nodes: %w", err)
+			}
+			for _, node := range all {
+				nodes = append(nodes, node.Name)
+			}
+		}
+		if len(nodes) == 0 {
+			return fmt.Errorf("instance %d has no nodes to reboot", instanceID)
+		}
+		return rollingReboot(cmd, c, instanceID, nodes, timeout)
+	},
+}
+
+// rollingReboot reboots nodes in order, waiting for each to rejoin the
+// cluster before the next. It stops at the first node that fails, or before
+// the next node once interrupted.
+func rollingReboot(cmd *cobra.Command, c client.ClientAPI, instanceID int, nodes []string, timeout time.Duration) error {
+	ctx := commandContext(cmd)
+	for i, node := range nodes {
+		if ctx.Err() != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("rolling reboot %w after %d of %d nodes. Not rebooted: %s", errInterrupted, i, len(nodes), strings.Join(nodes[i:], ", "))
+		}
+		printStatus(cmd, "Rebooting node %s (%d/%d)...", node, i+1, len(nodes))
+		rebootedAt := time.Now()
+		err := c.RebootInstance(strconv.Itoa(instanceID), []string{node})
+		if err == nil {
+			err = waitForNodeRejoin(ctx, c, instanceID, node, rebootedAt, timeout)
+		}
+		if err != nil {
+			cmd.SilenceUsage = true
+			if remaining := nodes[i+1:]; len(remaining) > 0 {
+				return fmt.Errorf("rolling reboot stopped at node %s: %w. Not rebooted: %s", node, err, strings.Join(remaining, ", "))
+			}
+			return fmt.Errorf("rolling reboot stopped at node %s: %w", node, err)
+		}
+	}
+	printStatus(cmd, "Rolling reboot of %d nodes completed.", len(nodes))
+	return nil
+}
+
+// printNodeDetails shows the node named nodeName as a key/value view. An
+// unknown name is reported along with the names of the nodes in the instance.
+func printNodeDetails(cmd *cobra.Command, c client.ClientAPI, idFlag string, nodes []client.Node, nodeName string) error {
+	names := make([]string, len(nodes))
+	found := false
+	for i, node := range nodes {
+		names[i] = node.Name
+		found = found || node.Name == nodeName
+	}
+	if !found {
+		if len(names) == 0 {
+			return fmt.Errorf("node %q not found: the instance has no nodes", nodeName)
+		}
+		return fmt.Errorf("node %q not found. Available nodes: %s", nodeName, strings.Join(names, ", "))
+	}
+
+	instanceID, err := strconv.Atoi(idFlag)
+	if err != nil {
+		return fmt.Errorf("invalid instance ID: %v", err)
+	}
+
+	node, err := c.GetNode(instanceID, nodeName)
+	if err != nil {
+		return fmt.Errorf("failed to get node: %w", err)
+	}
+
+	p, err := getPrinter(cmd)
+	if err != nil {
+		return err
+	}
+
+	yesNo := func(b bool) string {
+		if b {
+			return "Yes"
+		}
+		return "No"
+	}
+	partitions := "None"
+	if len(node.Partitions) > 0 {
+		partitions = strings.Join(node.Partitions, ", ")
+	}
+	headers := []string{"NAME", "HOSTNAME", "AVAILABILITY_ZONE", "CONFIGURED", "RUNNING", "RABBITMQ_VERSION", "ERLANG_VERSION", "UPTIME", "MEMORY_USED", "MEMORY_LIMIT", "DISK_SIZE", "DISK_FREE", "DISK_FREE_LIMIT", "PARTITIONS"}
+	values := []string{
+		node.Name,
+		node.Hostname,
+		node.AvailabilityZone,
+		yesNo(node.Configured),
+		yesNo(node.Running),
+		node.RabbitMQVersion,
+		node.ErlangVersion,
+		(time.Duration(node.Uptime) * time.Millisecond).Round(time.Second).String(),
+		formatBytes(node.MemUsed),
+		formatBytes(node.MemLimit),
+		fmt.Sprintf("%d GB", node.DiskSize+node.AdditionalDiskSize),
+		formatBytes(node.DiskFree),
+		formatBytes(node.DiskFreeLimit),
+		partitions,
+	}
+	for _, category := range sortedKeys(node.Memory) {
+		headers = append(headers, "MEMORY_"+strings.ToUpper(category))
+		values = append(values, formatBytes(node.Memory[category]))
+	}
+	p.PrintRecord(headers, values)
+	return nil
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. 1.5 GiB.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// checkVersionUpgrade reports whether a broker version newer than the one
+// the instance runs is available, and makes the command exit with
+// exitUpgradeAvailable if so.
+func checkVersionUpgrade(cmd *cobra.Command, instance *client.Instance, versions *client.VersionInfo) error {
+	broker, available := "RabbitMQ", versions.RabbitMQVersions
+	if versions.Backend == client.BackendLavinMQ {
+		broker, available = "LavinMQ", versions.LavinMQVersions
+	}
+
+	current := instance.RMQVersion
+	latest := current
+	for _, v := range available {
+		if compareVersions(v, latest) > 0 {
+			latest = v
+		}
+	}
+	upgrade := latest != current
+
+	if !textOutput(cmd) {
+		p, err := getPrinter(cmd)
+		if err != nil {
+			return err
+		}
+		p.PrintRecord(
+			[]string{"BACKEND", "CURRENT_VERSION", "LATEST_VERSION", "UPGRADE_AVAILABLE"},
+			[]string{versions.Backend, current, latest, strconv.FormatBool(upgrade)},
+		)
+	} else if upgrade {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s is available (running %s).\n", broker, latest, current)
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s is up to date.\n", broker, current)
+	}
+
+	if upgrade {
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return &exitError{code: exitUpgradeAvailable, err: fmt.Errorf("%s %s is available", broker, latest)}
+	}
+	return nil
+}
+
+// compareVersions compares dotted versions such as 3.13.7 part by part,
+// numerically where both parts are numbers. Missing parts count as 0.
+func compareVersions(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range max(len(pa), len(pb)) {
+		x, y := "0", "0"
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		nx, errX := strconv.Atoi(x)
+		ny, errY := strconv.Atoi(y)
+		if errX == nil && errY == nil {
+			if c := cmp.Compare(nx, ny); c != 0 {
+				return c
+			}
+		} else if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// versionsOutput is the JSON and YAML shape of 'nodes versions': the
+// backend plus only the version lists that apply to it, never null.
+func versionsOutput(v *client.VersionInfo) map[string]any {
+	orEmpty := func(s []string) []string {
+		if s == nil {
+			return []string{}
+		}
+		return s
+	}
+	if v.Backend == client.BackendLavinMQ {
+		return map[string]any{
+			"backend":          v.Backend,
+			"lavinmq_versions": orEmpty(v.LavinMQVersions),
+		}
+	}
+	return map[string]any{
+		"backend":           v.Backend,
+		"rabbitmq_versions": orEmpty(v.RabbitMQVersions),
+		"erlang_versions":   orEmpty(v.ErlangVersions),
+	}
+}
+
 func init() {
 	// Add --id flag to all subcommands
-	instanceNodesListCmd.Flags().StringP("id", "", "", "Instance ID (required)")
+	instanceNodesListCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
 	instanceNodesListCmd.MarkFlagRequired("id")
+	instanceNodesListCmd.Flags().String("node", "", "Show extended details for the node with this name")
+	instanceNodesListCmd.Flags().Bool("wait-healthy", false, "Wait until every node is configured and running before printing")
+	instanceNodesListCmd.Flags().String("timeout", "10m", "With --wait-healthy, how long to wait (e.g., 10m, 30m)")
+
+	instanceNodesRebootCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceNodesRebootCmd.MarkFlagRequired("id")
+	instanceNodesRebootCmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+	instanceNodesRebootCmd.Flags().String("nodes", "", "Comma-separated list of node names; all nodes by default")
+	instanceNodesRebootCmd.Flags().Bool("rolling", false, "Reboot one node at a time, waiting for each to rejoin the cluster")
+	instanceNodesRebootCmd.Flags().String("timeout", "15m", "With --rolling, how long to wait for each node to come back (e.g., 15m, 30m)")
 
-	instanceNodesVersionsCmd.Flags().StringP("id", "", "", "Instance ID (required)")
+	instanceNodesVersionsCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
 	instanceNodesVersionsCmd.MarkFlagRequired("id")
+	instanceNodesVersionsCmd.Flags().Bool("check", false, "Exit 10 if a newer broker version is available, 4 if the instance doesn't exist")
+	instanceNodesVersionsCmd.Flags().String("backend", "", "Broker to show versions for (rabbitmq or lavinmq); detected by default")
+	instanceNodesVersionsCmd.RegisterFlagCompletionFunc("backend", cobra.FixedCompletions([]string{client.BackendRabbitMQ, client.BackendLavinMQ}, cobra.ShellCompDirectiveNoFileComp))
 
 	instanceNodesCmd.AddCommand(instanceNodesListCmd)
+	instanceNodesCmd.AddCommand(instanceNodesRebootCmd)
 	instanceNodesCmd.AddCommand(instanceNodesVersionsCmd)
 }
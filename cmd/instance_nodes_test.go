@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nodesClient struct {
+	fakeClient
+	nodes []client.Node
+}
+
+func (f *nodesClient) ListNodes(instanceID string) ([]client.Node, error) {
+	return f.nodes, nil
+}
+
+func (f *nodesClient) GetNode(id int, node string) (*client.NodeDetails, error) {
+	for _, n := range f.nodes {
+		if n.Name == node {
+			return &client.NodeDetails{
+				Node:       n,
+				Uptime:     90061000,
+				MemUsed:    512 * 1024 * 1024,
+				Memory:     map[string]int64{"queue_procs": 2048},
+				DiskFree:   3 * 1024 * 1024 * 1024,
+				Partitions: []string{"rabbit@host-02"},
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestInstanceNodesListCmd_Node(t *testing.T) {
+	useFakeClient(t, &nodesClient{nodes: []client.Node{
+		{Name: "rabbit@host-01", Running: true, DiskSize: 20},
+		{Name: "rabbit@host-02", Running: true, DiskSize: 20},
+	}})
+
+	cmd := instanceNodesListCmd
+	cmd.InheritedFlags() // merge --output from root, as Execute would
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("node", "rabbit@host-01")
+	defer resetFlags(cmd)
+
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+
+	assert.Contains(t, out, "NAME = rabbit@host-01")
+	assert.Contains(t, out, "UPTIME = 25h1m1s")
+	assert.Contains(t, out, "MEMORY_USED = 512.0 MiB")
+	assert.Contains(t, out, "DISK_FREE = 3.0 GiB")
+	assert.Contains(t, out, "PARTITIONS = rabbit@host-02")
+	assert.Contains(t, out, "MEMORY_QUEUE_PROCS = 2.0 KiB")
+}
+
+func TestInstanceNodesListCmd_UnknownNode(t *testing.T) {
+	useFakeClient(t, &nodesClient{nodes: []client.Node{
+		{Name: "rabbit@host-01"},
+		{Name: "rabbit@host-02"},
+	}})
+
+	cmd := instanceNodesListCmd
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("node", "rabbit@host-03")
+	defer resetFlags(cmd)
+
+	err := cmd.RunE(cmd, []string{})
+	assert.EqualError(t, err, `node "rabbit@host-03" not found. Available nodes: rabbit@host-01, rabbit@host-02`)
+}
+
+// rebootClient reboots nodes instantly, except those in stuck, and records
+// the order of reboots and health checks.
+type rebootClient struct {
+	fakeClient
+	nodes []string
+	stuck map[string]bool
+	calls []string
+}
+
+func (f *rebootClient) ListNodes(instanceID string) ([]client.Node, error) {
+	nodes := make([]client.Node, len(f.nodes))
+	for i, name := range f.nodes {
+		nodes[i] = client.Node{Name: name, Running: true}
+	}
+	return nodes, nil
+}
+
+func (f *rebootClient) RebootInstance(instanceID string, nodes []string) error {
+	f.calls = append(f.calls, fmt.Sprintf("reboot %v", nodes))
+	return nil
+}
+
+func (f *rebootClient) GetNode(id int, node string) (*client.NodeDetails, error) {
+	if f.stuck[node] {
+		return &client.NodeDetails{Node: client.Node{Name: node}}, nil
+	}
+	return &client.NodeDetails{Node: client.Node{Name: node, Running: true}}, nil
+}
+
+func (f *rebootClient) InstanceHealthy(id int) (bool, error) {
+	f.calls = append(f.calls, "healthy")
+	return true, nil
+}
+
+func TestInstanceNodesRebootCmd_Rolling(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	run := func(t *testing.T, fake *rebootClient) error {
+		t.Helper()
+		useFakeClient(t, fake)
+		cmd := instanceNodesRebootCmd
+		cmd.Flags().Set("id", "1234")
+		cmd.Flags().Set("rolling", "true")
+		cmd.Flags().Set("timeout", "50ms")
+		defer resetFlags(cmd)
+		return cmd.RunE(cmd, []string{})
+	}
+
+	t.Run("reboots one node at a time", func(t *testing.T) {
+		fake := &rebootClient{nodes: []string{"rabbit@host-01", "rabbit@host-02"}}
+
+		require.NoError(t, run(t, fake))
+		assert.Equal(t, []string{"reboot [rabbit@host-01]", "healthy", "reboot [rabbit@host-02]", "healthy"}, fake.calls)
+	})
+
+	t.Run("stops at a node that doesn't come back", func(t *testing.T) {
+		fake := &rebootClient{
+			nodes: []string{"rabbit@host-01", "rabbit@host-02", "rabbit@host-03"},
+			stuck: map[string]bool{"rabbit@host-02": true},
+		}
+
+		err := run(t, fake)
+		assert.ErrorContains(t, err, "rolling reboot stopped at node rabbit@host-02: timeout after")
+		assert.ErrorContains(t, err, "Not rebooted: rabbit@host-03")
+		assert.Equal(t, []string{"reboot [rabbit@host-01]", "healthy", "reboot [rabbit@host-02]"}, fake.calls)
+	})
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("3.13.7", "3.13.7"))
+	assert.Equal(t, 1, compareVersions("3.13.10", "3.13.9"))
+	assert.Equal(t, -1, compareVersions("3.13.7", "4.0.1"))
+	assert.Equal(t, 0, compareVersions("4.0", "4.0.0"))
+	assert.Equal(t, 1, compareVersions("4.0.1", "4.0"))
+}
+
+type versionsClient struct {
+	fakeClient
+	versions *client.VersionInfo
+}
+
+func (f *versionsClient) GetAvailableVersions(string) (*client.VersionInfo, error) {
+	return f.versions, nil
+}
+
+func TestInstanceNodesVersionsCmd_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		versions []string
+		code     int
+		out      string
+	}{
+		{"up to date", "1234", []string{"3.12.14", "3.13.7"}, 0, "RabbitMQ 3.13.7 is up to date."},
+		{"upgrade available", "1234", []string{"3.13.7", "4.0.5", "3.13.10"}, exitUpgradeAvailable, "RabbitMQ 4.0.5 is available (running 3.13.7)."},
+		{"missing instance", "999", nil, exitNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeClient(t, &versionsClient{
+				fakeClient: fakeClient{instances: map[int]*client.Instance{1234: {ID: 1234, RMQVersion: "3.13.7"}}},
+				versions:   &client.VersionInfo{RabbitMQVersions: tt.versions, Backend: client.BackendRabbitMQ},
+			})
+
+			cmd := instanceNodesVersionsCmd
+			cmd.InheritedFlags() // merge --output from root, as Execute would
+			defer resetFlags(cmd)
+			defer func() { cmd.SilenceErrors, cmd.SilenceUsage = false, false }()
+			require.NoError(t, cmd.ParseFlags([]string{"--id", tt.id, "--check"}))
+
+			var err error
+			out := captureStdout(t, func() {
+				err = cmd.RunE(cmd, []string{})
+			})
+			assert.Equal(t, tt.code, ExitCode(err))
+			assert.Equal(t, tt.out, strings.TrimSpace(out))
+		})
+	}
+}
+
+func TestInstanceNodesVersionsCmd_CheckOutput(t *testing.T) {
+	useFakeClient(t, &versionsClient{
+		fakeClient: fakeClient{instances: map[int]*client.Instance{1234: {ID: 1234, RMQVersion: "3.13.7"}}},
+		versions:   &client.VersionInfo{RabbitMQVersions: []string{"3.13.7", "4.0.5"}, Backend: client.BackendRabbitMQ},
+	})
+
+	cmd := instanceNodesVersionsCmd
+	cmd.InheritedFlags()
+	flags := rootCmd.PersistentFlags()
+	defer func() {
+		flags.Set("output", "table")
+		flags.Set("template", "")
+		flags.Lookup("output").Changed = false
+		flags.Lookup("template").Changed = false
+	}()
+
+	run := func(t *testing.T) string {
+		t.Helper()
+		defer resetFlags(cmd)
+		defer func() { cmd.SilenceErrors, cmd.SilenceUsage = false, false }()
+		require.NoError(t, cmd.ParseFlags([]string{"--id", "1234", "--check"}))
+		var err error
+		out := captureStdout(t, func() {
+			err = cmd.RunE(cmd, []string{})
+		})
+		assert.Equal(t, exitUpgradeAvailable, ExitCode(err))
+		return out
+	}
+
+	t.Run("json", func(t *testing.T) {
+		flags.Set("output", "json")
+		var got map[string]string
+		require.NoError(t, json.Unmarshal([]byte(run(t)), &got))
+		assert.Equal(t, map[string]string{
+			"backend":           "rabbitmq",
+			"current_version":   "3.13.7",
+			"latest_version":    "4.0.5",
+			"upgrade_available": "true",
+		}, got)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		flags.Set("output", "yaml")
+		out := run(t)
+		assert.Contains(t, out, "latest_version: 4.0.5\n")
+		assert.Contains(t, out, "upgrade_available: \"true\"\n")
+	})
+
+	t.Run("template", func(t *testing.T) {
+		flags.Set("output", "table")
+		flags.Lookup("output").Changed = false
+		flags.Set("template", "{{.current_version}} -> {{.latest_version}}")
+		assert.Equal(t, "3.13.7 -> 4.0.5\n", run(t))
+	})
+}
+
+// recoveringNodesClient reports host-02 as not running until healthyAfter
+// ListNodes calls have been made; with healthyAfter < 0 it never recovers.
+type recoveringNodesClient struct {
+	fakeClient
+	healthyAfter int
+	calls        int
+}
+
+func (f *recoveringNodesClient) ListNodes(instanceID string) ([]client.Node, error) {
+	f.calls++
+	recovered := f.healthyAfter >= 0 && f.calls > f.healthyAfter
+	return []client.Node{
+		{Name: "rabbit@host-01", Configured: true, Running: true},
+		{Name: "rabbit@host-02", Configured: true, Running: recovered},
+	}, nil
+}
+
+func TestInstanceNodesListCmd_WaitHealthy(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	run := func(t *testing.T, fake *recoveringNodesClient, timeout string) (string, error) {
+		t.Helper()
+		useFakeClient(t, fake)
+		cmd := instanceNodesListCmd
+		defer resetFlags(cmd)
+		require.NoError(t, cmd.ParseFlags([]string{"--id", "1234", "--wait-healthy", "--timeout", timeout}))
+		var err error
+		out := captureStdout(t, func() {
+			err = cmd.RunE(cmd, []string{})
+		})
+		return out, err
+	}
+
+	t.Run("becomes healthy", func(t *testing.T) {
+		fake := &recoveringNodesClient{healthyAfter: 2}
+		out, err := run(t, fake, "1m")
+		require.NoError(t, err)
+		assert.Equal(t, 3, fake.calls)
+		assert.Regexp(t, `rabbit@host-02\s+Yes\s+Yes`, out, "the final table is printed")
+	})
+
+	t.Run("timeout names unhealthy nodes", func(t *testing.T) {
+		fake := &recoveringNodesClient{healthyAfter: -1}
+		out, err := run(t, fake, "20ms")
+		assert.ErrorContains(t, err, "waiting for all nodes of instance 1234 to be healthy, still unhealthy: rabbit@host-02 (not running)")
+		assert.Regexp(t, `rabbit@host-02\s+Yes\s+No`, out, "the nodes are printed as last seen")
+	})
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// recipientTypes lists the supported notification recipient types.
+var recipientTypes = []string{"email", "slack", "webhook", "pagerduty", "opsgenie"}
+
+var instanceNotificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "Manage alarm notification recipients",
+	Long:  `List, add, and delete the recipients that alarms notify.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Help()
+		cmd.SilenceUsage = true
+		return fmt.Errorf("subcommand required")
+	},
+}
+
+var instanceNotificationsListCmd = &cobra.Command{
+	Use:     "list --id <instance_id>",
+	Aliases: []string{"ls"},
+	Short:   "List notification recipients",
+	Long:    `Retrieves all notification recipients for the instance.`,
+	Example: `  cloudamqp instance notifications list --id 1234`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		recipients, err := c.ListRecipients(idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to list recipients: %w", err)
+		}
+
+		if len(recipients) == 0 {
+			printStatus(cmd, "No recipients found.")
+			return nil
+		}
+
+		p, err := getListPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		headers := []string{"ID", "NAME", "TYPE", "VALUE"}
+		rows := make([][]string, len(recipients))
+		for i, r := range recipients {
+			rows[i] = []string{strconv.Itoa(r.ID), r.Name, r.Type, r.Value}
+		}
+		p.PrintRecords(headers, rows)
+
+		return nil
+	},
+}
+
+var instanceNotificationsAddCmd = &cobra.Command{
+	Use:   "add --id <instance_id> --type <type> --value <value>",
+	Short: "Add a notification recipient",
+	Long: `Add a recipient that alarms can notify.
+
+Available types and the expected value:
+  email:     an email address
+  slack:     a Slack incoming webhook URL
+  webhook:   an http(s) URL
+  pagerduty: a PagerDuty integration key
+  opsgenie:  an Opsgenie API key`,
+	Example: `  cloudamqp instance notifications add --id 1234 --type email --value ops@example.com --name "Ops team"
+  cloudamqp instance notifications add --id 1234 --type slack --value https://hooks.slack.com/services/T000/B000/XXX --name alerts`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		recipientType, _ := cmd.Flags().GetString("type")
+		value, _ := cmd.Flags().GetString("value")
+		name, _ := cmd.Flags().GetString("name")
+
+		if err := validateRecipient(recipientType, value); err != nil {
+			return err
+		}
+
+		req := &client.RecipientCreateRequest{
+			Type:  recipientType,
+			Value: value,
+			Name:  name,
+		}
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "POST", "/instances/"+idFlag+"/alarms/recipients", req)
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		recipient, err := c.CreateRecipient(idFlag, req)
+		if err != nil {
+			return fmt.Errorf("failed to add recipient: %w", err)
+		}
+
+		output, err := json.MarshalIndent(recipient, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format response: %v", err)
+		}
+
+		printStatus(cmd, "Recipient added successfully.")
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
+		return nil
+	},
+}
+
+var instanceNotificationsDeleteCmd = &cobra.Command{
+	Use:     "delete --id <instance_id> --recipient-id <recipient_id>",
+	Aliases: []string{"rm"},
+	Short:   "Delete a notification recipient",
+	Long:    `Deletes a notification recipient from the instance.`,
+	Example: `  cloudamqp instance notifications delete --id 1234 --recipient-id 7`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		recipientID, _ := cmd.Flags().GetInt("recipient-id")
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "DELETE", "/instances/"+idFlag+"/alarms/recipients/"+strconv.Itoa(recipientID), nil)
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		err = c.DeleteRecipient(idFlag, recipientID)
+		if err != nil {
+			return fmt.Errorf("failed to delete recipient: %w", err)
+		}
+
+		printStatus(cmd, "Recipient %d deleted successfully.", recipientID)
+		return nil
+	},
+}
+
+// validateRecipient checks the recipient type and that value has the form
+// that type expects.
+func validateRecipient(recipientType, value string) error {
+	if value == "" {
+		return fmt.Errorf("--value is required")
+	}
+
+	switch recipientType {
+	case "email":
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value {
+			return fmt.Errorf("invalid email address %q", value)
+		}
+	case "slack", "webhook":
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid %s URL %q: must be an http(s) URL", recipientType, value)
+		}
+	case "pagerduty", "opsgenie":
+		if strings.ContainsAny(value, " \t\n") || strings.Contains(value, "://") {
+			return fmt.Errorf("invalid %s key %q: expected an API key, not a URL", recipientType, value)
+		}
+	default:
+		return fmt.Errorf("invalid recipient type %q. Valid types are: %s", recipientType, strings.Join(recipientTypes, ", "))
+	}
+
+	return nil
+}
+
+// completeRecipientTypes returns the valid recipient types for completion
+func completeRecipientTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return recipientTypes, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	instanceNotificationsListCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceNotificationsListCmd.MarkFlagRequired("id")
+
+	instanceNotificationsAddCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceNotificationsAddCmd.Flags().String("type", "", "Recipient type: email, slack, webhook, pagerduty, opsgenie (required)")
+	instanceNotificationsAddCmd.Flags().String("value", "", "Email address, URL or key, depending on type (required)")
+	instanceNotificationsAddCmd.Flags().String("name", "", "Display name of the recipient")
+	addDryRunFlag(instanceNotificationsAddCmd)
+	instanceNotificationsAddCmd.MarkFlagRequired("id")
+	instanceNotificationsAddCmd.MarkFlagRequired("type")
+	instanceNotificationsAddCmd.MarkFlagRequired("value")
+	instanceNotificationsAddCmd.RegisterFlagCompletionFunc("type", completeRecipientTypes)
+
+	instanceNotificationsDeleteCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceNotificationsDeleteCmd.Flags().Int("recipient-id", 0, "Recipient ID (required)")
+	addDryRunFlag(instanceNotificationsDeleteCmd)
+	instanceNotificationsDeleteCmd.MarkFlagRequired("id")
+	instanceNotificationsDeleteCmd.MarkFlagRequired("recipient-id")
+
+	for _, cmd := range []*cobra.Command{instanceNotificationsListCmd, instanceNotificationsAddCmd, instanceNotificationsDeleteCmd} {
+		cmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+	}
+
+	instanceNotificationsCmd.AddCommand(instanceNotificationsListCmd)
+	instanceNotificationsCmd.AddCommand(instanceNotificationsAddCmd)
+	instanceNotificationsCmd.AddCommand(instanceNotificationsDeleteCmd)
+}
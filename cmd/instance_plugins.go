@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 
 	"cloudamqp-cli/client"
 	"github.com/spf13/cobra"
@@ -20,47 +22,55 @@ var instancePluginsCmd = &cobra.Command{
 
 var instancePluginsListCmd = &cobra.Command{
 	Use:     "list --id <instance_id>",
+	Aliases: []string{"ls"},
 	Short:   "List plugins",
-	Long:    `Retrieves all available RabbitMQ plugins.`,
-	Example: `  cloudamqp instance plugins list --id 1234`,
+	Long: `Retrieves all available RabbitMQ plugins with whether each is enabled,
+sorted by name. Use --enabled-only to show just the enabled ones.`,
+	Example: `  cloudamqp instance plugins list --id 1234
+  cloudamqp instance plugins list --id 1234 --enabled-only
+  cloudamqp instance plugins list --id 1234 -o json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
 		}
 
-		var err error
 		apiKey, err := getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
 		}
 
-		c := client.New(apiKey, Version)
+		c := newAPIClient(apiKey)
 
 		plugins, err := c.ListPlugins(idFlag)
 		if err != nil {
-			fmt.Printf("Error listing plugins: %v\n", err)
-			return err
+			return fmt.Errorf("failed to list plugins: %w", err)
+		}
+		if enabledOnly, _ := cmd.Flags().GetBool("enabled-only"); enabledOnly {
+			plugins = slices.DeleteFunc(plugins, func(p client.Plugin) bool { return !p.Enabled })
 		}
+		sort.SliceStable(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
 
 		if len(plugins) == 0 {
-			fmt.Println("No plugins found.")
+			printStatus(cmd, "No plugins found.")
 			return nil
 		}
 
-		p, err := getPrinter(cmd)
+		p, err := getListPrinter(cmd)
 		if err != nil {
 			return err
 		}
 
-		headers := []string{"NAME", "ENABLED"}
+		p.StyleColumn("ENABLED", readyStyle)
+
+		headers := []string{"NAME", "ENABLED", "DESCRIPTION"}
 		rows := make([][]string, len(plugins))
 		for i, plugin := range plugins {
 			enabled := "No"
 			if plugin.Enabled {
 				enabled = "Yes"
 			}
-			rows[i] = []string{plugin.Name, enabled}
+			rows[i] = []string{plugin.Name, enabled, plugin.Description}
 		}
 		p.PrintRecords(headers, rows)
 
@@ -76,12 +86,11 @@ var instancePluginsEnableCmd = &cobra.Command{
 	Example: `  cloudamqp instance plugins enable rabbitmq_top --id 1234`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		pluginName := args[0]
-		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
 		}
 
-		var err error
 		apiKey, err := getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
@@ -91,11 +100,10 @@ var instancePluginsEnableCmd = &cobra.Command{
 
 		err = c.EnablePlugin(idFlag, pluginName)
 		if err != nil {
-			fmt.Printf("Error enabling plugin '%s': %v\n", pluginName, err)
-			return err
+			return fmt.Errorf("failed to enable plugin '%s': %w", pluginName, err)
 		}
 
-		fmt.Printf("Plugin '%s' enabled successfully.\n", pluginName)
+		printStatus(cmd, "Plugin '%s' enabled successfully.", pluginName)
 		return nil
 	},
 }
@@ -108,12 +116,11 @@ var instancePluginsDisableCmd = &cobra.Command{
 	Example: `  cloudamqp instance plugins disable rabbitmq_top --id 1234`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		pluginName := args[0]
-		idFlag, _ := cmd.Flags().GetString("id")
-		if idFlag == "" {
-			return fmt.Errorf("instance ID is required. Use --id flag")
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
 		}
 
-		var err error
 		apiKey, err := getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
@@ -123,24 +130,24 @@ var instancePluginsDisableCmd = &cobra.Command{
 
 		err = c.DisablePlugin(idFlag, pluginName)
 		if err != nil {
-			fmt.Printf("Error disabling plugin '%s': %v\n", pluginName, err)
-			return err
+			return fmt.Errorf("failed to disable plugin '%s': %w", pluginName, err)
 		}
 
-		fmt.Printf("Plugin '%s' disabled successfully.\n", pluginName)
+		printStatus(cmd, "Plugin '%s' disabled successfully.", pluginName)
 		return nil
 	},
 }
 
 func init() {
 	// Add --id flag to all plugins commands
-	instancePluginsListCmd.Flags().StringP("id", "", "", "Instance ID (required)")
+	instancePluginsListCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
 	instancePluginsListCmd.MarkFlagRequired("id")
+	instancePluginsListCmd.Flags().Bool("enabled-only", false, "Only list enabled plugins")
 
-	instancePluginsEnableCmd.Flags().StringP("id", "", "", "Instance ID (required)")
+	instancePluginsEnableCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
 	instancePluginsEnableCmd.MarkFlagRequired("id")
 
-	instancePluginsDisableCmd.Flags().StringP("id", "", "", "Instance ID (required)")
+	instancePluginsDisableCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
 	instancePluginsDisableCmd.MarkFlagRequired("id")
 
 	// Add all commands to plugins
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstancePluginsListCmd(t *testing.T) {
+	fake := newSpecClient()
+	fake.plugins = []client.Plugin{
+		{Name: "rabbitmq_top", Description: "Top-like process view", Enabled: false},
+		{Name: "rabbitmq_management", Description: "Management UI and HTTP API", Enabled: true},
+		{Name: "rabbitmq_federation", Description: "Federated exchanges and queues", Enabled: true},
+	}
+	useFakeClient(t, fake)
+
+	run := func(t *testing.T, flags map[string]string) string {
+		t.Helper()
+		cmd := instancePluginsListCmd
+		cmd.InheritedFlags()
+		require.NoError(t, cmd.Flags().Set("id", "1234"))
+		for name, value := range flags {
+			require.NoError(t, cmd.Flags().Set(name, value))
+		}
+		defer resetFlags(cmd)
+
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		defer cmd.SetOut(nil)
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+		return out.String()
+	}
+
+	t.Run("sorted by name", func(t *testing.T) {
+		out := run(t, nil)
+		assert.Contains(t, out, "DESCRIPTION")
+		assert.Regexp(t, `(?s)rabbitmq_federation +Yes +Federated.*rabbitmq_management +Yes.*rabbitmq_top +No +Top-like`, out)
+	})
+
+	t.Run("enabled only", func(t *testing.T) {
+		out := run(t, map[string]string{"enabled-only": "true"})
+		assert.Contains(t, out, "rabbitmq_management")
+		assert.NotContains(t, out, "rabbitmq_top")
+	})
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var instanceRenameCmd = &cobra.Command{
+	Use:     "rename --id <id> --name <new_name>",
+	Short:   "Rename a CloudAMQP instance",
+	Long:    `Change the name of an instance without touching its plan or tags.`,
+	Example: `  cloudamqp instance rename --id 1234 --name orders-prod`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		instanceID, err := flagInstanceID(cmd)
+		if err != nil {
+			return err
+		}
+
+		name, _ := cmd.Flags().GetString("name")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("new name must not be empty")
+		}
+
+		req := &client.InstanceUpdateRequest{Name: name}
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "PUT", "/instances/"+strconv.Itoa(instanceID), req.Form())
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		instance, err := getInstance(c, instanceID)
+		if err != nil {
+			return fmt.Errorf("failed to get instance: %w", err)
+		}
+
+		if instance.Name == name {
+			printStatus(cmd, "Instance %d is already named %q.", instanceID, name)
+			return nil
+		}
+
+		err = c.UpdateInstance(instanceID, req)
+		if err != nil {
+			return fmt.Errorf("failed to rename instance: %w", err)
+		}
+
+		printStatus(cmd, "Instance %d renamed from %q to %q.", instanceID, instance.Name, name)
+		return nil
+	},
+}
+
+func init() {
+	instanceRenameCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceRenameCmd.Flags().String("name", "", "New instance name (required)")
+	addDryRunFlag(instanceRenameCmd)
+	instanceRenameCmd.MarkFlagRequired("id")
+	instanceRenameCmd.MarkFlagRequired("name")
+	instanceRenameCmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+}
@@ -3,15 +3,18 @@ package cmd
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"cloudamqp-cli/client"
 	"github.com/spf13/cobra"
 )
 
 var (
-	resizeInstanceID string
-	diskSize         int
-	allowDowntime    bool
+	resizeInstanceID  string
+	diskSize          int
+	allowDowntime     bool
+	resizeWait        bool
+	resizeWaitTimeout string
 )
 
 var instanceResizeCmd = &cobra.Command{
@@ -24,24 +27,31 @@ Note: This action is asynchronous. The request will return almost immediately. T
 
 Note: Due to restrictions from cloud providers, it's only possible to resize the disk every 8 hours unless --allow-downtime is set.
 
+This only changes the disk size; use 'instance update --plan' to change the plan.
+Disks can only grow, so --disk-size must be larger than the current additional disk size,
+or 0 when the instance has no additional disk.
+Shared plans do not support custom disk sizing.
+
 Available disk sizes: 0, 25, 50, 100, 250, 500, 1000, 2000 GB`,
 	Example: `  cloudamqp instance resize-disk --id 1234 --disk-size=100
-  cloudamqp instance resize-disk --id 1234 --disk-size=250 --allow-downtime`,
+  cloudamqp instance resize-disk --id 1234 --disk-size=250 --allow-downtime
+  cloudamqp instance resize-disk --id 1234 --disk-size=100 --dry-run
+  cloudamqp instance resize-disk --id 1234 --disk-size=500 --wait`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
-		apiKey, err = getAPIKey()
-		if err != nil {
-			return fmt.Errorf("failed to get API key: %w", err)
-		}
-
-		if resizeInstanceID == "" {
-			return fmt.Errorf("--id is required")
+		// Checked before any API call, as the resize can't be undone
+		var timeout time.Duration
+		if resizeWait {
+			var err error
+			timeout, err = time.ParseDuration(resizeWaitTimeout)
+			if err != nil {
+				return fmt.Errorf("invalid wait-timeout value: %v", err)
+			}
 		}
 
-		instanceID, err := strconv.Atoi(resizeInstanceID)
+		instanceID, err := flagInstanceID(cmd)
 		if err != nil {
-			return fmt.Errorf("invalid instance ID: %v", err)
+			return err
 		}
 
 		// Validate disk size
@@ -57,31 +67,92 @@ Available disk sizes: 0, 25, 50, 100, 250, 500, 1000, 2000 GB`,
 			return fmt.Errorf("invalid disk size. Valid sizes are: 0, 25, 50, 100, 250, 500, 1000, 2000 GB")
 		}
 
-		c := client.New(apiKey, Version)
-
 		req := &client.DiskResizeRequest{
 			ExtraDiskSize: diskSize,
 			AllowDowntime: allowDowntime,
 		}
 
-		err = c.ResizeInstanceDisk(instanceID, req)
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "PUT", "/instances/"+strconv.Itoa(instanceID)+"/disk", req.Form())
+		}
+
+		apiKey, err = getAPIKey()
 		if err != nil {
-			fmt.Printf("Error resizing instance disk: %v\n", err)
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		if err := validateDiskResize(c, instanceID, diskSize); err != nil {
 			return err
 		}
 
-		fmt.Printf("Disk resize initiated for instance %d. Additional disk size: %d GB\n", instanceID, diskSize)
 		if allowDowntime {
-			fmt.Println("Note: Downtime is allowed for this resize operation.")
+			err = c.ResizeInstanceDisk(instanceID, req)
+		} else {
+			err = c.ResizeDisk(instanceID, diskSize)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to resize instance disk: %w", err)
+		}
+
+		printStatus(cmd, "Disk resize initiated for instance %d. Additional disk size: %d GB", instanceID, diskSize)
+		if allowDowntime {
+			printStatus(cmd, "Note: Downtime is allowed for this resize operation.")
+		}
+
+		if resizeWait {
+			if err := waitForDiskResize(commandContext(cmd), c, instanceID, diskSize, timeout); err != nil {
+				return fmt.Errorf("wait failed: %w", err)
+			}
 		}
 		return nil
 	},
 }
 
+// validateDiskResize checks that the instance plan supports custom disk
+// sizes and that sizeGB grows the disk, since disks cannot shrink. A size
+// of 0 is accepted for an instance without additional disk.
+func validateDiskResize(c client.ClientAPI, instanceID, sizeGB int) error {
+	instance, err := getInstance(c, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to get instance: %w", err)
+	}
+
+	plans, err := c.ListPlans("")
+	if err != nil {
+		return fmt.Errorf("failed to list plans: %w", err)
+	}
+	for _, plan := range plans {
+		if plan.Name == instance.Plan && plan.Shared {
+			return fmt.Errorf("plan %s does not support custom disk sizing", instance.Plan)
+		}
+	}
+
+	nodes, err := c.ListNodes(strconv.Itoa(instanceID))
+	if err != nil {
+		return fmt.Errorf("failed to list nodes: %w", err)
+	}
+	current := 0
+	for _, node := range nodes {
+		if node.AdditionalDiskSize > current {
+			current = node.AdditionalDiskSize
+		}
+	}
+	if sizeGB < current || sizeGB == current && sizeGB > 0 {
+		return fmt.Errorf("disk size must be larger than the current additional disk size (%d GB); disks cannot shrink", current)
+	}
+
+	return nil
+}
+
 func init() {
-	instanceResizeCmd.Flags().StringVar(&resizeInstanceID, "id", "", "Instance ID (required)")
+	instanceResizeCmd.Flags().StringVar(&resizeInstanceID, "id", "", "Instance ID or name (required)")
 	instanceResizeCmd.Flags().IntVar(&diskSize, "disk-size", 0, "Disk size to add in gigabytes (0, 25, 50, 100, 250, 500, 1000, 2000)")
 	instanceResizeCmd.Flags().BoolVar(&allowDowntime, "allow-downtime", false, "Allow cluster downtime if needed when resizing disk")
+	instanceResizeCmd.Flags().BoolVar(&resizeWait, "wait", false, "Wait for the disk resize to complete")
+	instanceResizeCmd.Flags().StringVar(&resizeWaitTimeout, "wait-timeout", "15m", "Timeout for waiting (e.g., 15m, 30m)")
+	addDryRunFlag(instanceResizeCmd)
 	instanceResizeCmd.MarkFlagRequired("id")
 	instanceResizeCmd.MarkFlagRequired("disk-size")
 	instanceResizeCmd.RegisterFlagCompletionFunc("id", completeInstances)
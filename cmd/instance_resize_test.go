@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resizeClient has nodes with additionalGB of extra disk and records the
+// sizes ResizeDisk is called with.
+type resizeClient struct {
+	fakeClient
+	additionalGB int
+	resized      []int
+}
+
+func (f *resizeClient) ListNodes(instanceID string) ([]client.Node, error) {
+	return []client.Node{
+		{Name: "rabbit@host-01", AdditionalDiskSize: f.additionalGB},
+		{Name: "rabbit@host-02", AdditionalDiskSize: f.additionalGB},
+	}, nil
+}
+
+func (f *resizeClient) ResizeDisk(id int, sizeGB int) error {
+	f.resized = append(f.resized, sizeGB)
+	return nil
+}
+
+func TestInstanceResizeCmd(t *testing.T) {
+	tests := []struct {
+		name         string
+		plan         string
+		additionalGB int
+		size         string
+		wantErr      string
+	}{
+		{name: "grows the disk", plan: "bunny-1", additionalGB: 25, size: "100"},
+		{name: "0 without additional disk", plan: "bunny-1", size: "0"},
+		{name: "same size", plan: "bunny-1", additionalGB: 100, size: "100",
+			wantErr: "disk size must be larger than the current additional disk size (100 GB); disks cannot shrink"},
+		{name: "shrinks", plan: "bunny-1", additionalGB: 100, size: "50",
+			wantErr: "disk size must be larger than the current additional disk size (100 GB); disks cannot shrink"},
+		{name: "0 with additional disk", plan: "bunny-1", additionalGB: 25, size: "0",
+			wantErr: "disks cannot shrink"},
+		{name: "shared plan", plan: "lemur", size: "25",
+			wantErr: "plan lemur does not support custom disk sizing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &resizeClient{
+				fakeClient: fakeClient{
+					instances: map[int]*client.Instance{1234: {ID: 1234, Plan: tt.plan}},
+					plans:     []client.Plan{{Name: "bunny-1"}, {Name: "lemur", Shared: true}},
+				},
+				additionalGB: tt.additionalGB,
+			}
+			useFakeClient(t, fake)
+
+			cmd := instanceResizeCmd
+			defer resetFlags(cmd)
+			require.NoError(t, cmd.ParseFlags([]string{"--id", "1234", "--disk-size", tt.size}))
+
+			err := cmd.RunE(cmd, []string{})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Empty(t, fake.resized, "nothing is resized after a failed check")
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, fake.resized, 1)
+		})
+	}
+}
+
+func TestInstanceResizeCmd_InvalidWaitTimeout(t *testing.T) {
+	fake := &resizeClient{fakeClient: fakeClient{
+		instances: map[int]*client.Instance{1234: {ID: 1234, Plan: "bunny-1"}},
+		plans:     []client.Plan{{Name: "bunny-1"}},
+	}}
+	useFakeClient(t, fake)
+
+	cmd := instanceResizeCmd
+	defer resetFlags(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--id", "1234", "--disk-size", "100", "--wait", "--wait-timeout", "soon"}))
+
+	assert.ErrorContains(t, cmd.RunE(cmd, []string{}), "invalid wait-timeout value")
+	assert.Empty(t, fake.resized, "nothing is resized with an invalid timeout")
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var instanceSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find instances by name",
+	Long: `List the instances whose name contains the query, ignoring case.
+
+With --regex the query is a regular expression matched against the name
+instead; add (?i) to the pattern to ignore case.`,
+	Example: `  cloudamqp instance search broker
+  cloudamqp instance search --regex '^prod-.*-eu$'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useRegex, _ := cmd.Flags().GetBool("regex")
+		match, err := nameMatcher(args[0], useRegex)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err = getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		instances, err := newAPIClient(apiKey).ListInstances()
+		if err != nil {
+			return fmt.Errorf("failed to list instances: %w", err)
+		}
+
+		var matches []client.Instance
+		for _, instance := range instances {
+			if match(instance.Name) {
+				matches = append(matches, instance)
+			}
+		}
+		sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+
+		if isQuiet(cmd) {
+			for _, instance := range matches {
+				fmt.Fprintln(cmd.OutOrStdout(), instance.ID)
+			}
+			return nil
+		}
+
+		if len(matches) == 0 {
+			printStatus(cmd, "No instances matching '%s' found.", args[0])
+			return nil
+		}
+
+		p, err := getListPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		headers := []string{"ID", "NAME", "PLAN", "REGION"}
+		rows := make([][]string, len(matches))
+		for i, instance := range matches {
+			rows[i] = []string{
+				strconv.Itoa(instance.ID),
+				instance.Name,
+				instance.Plan,
+				instance.Region,
+			}
+		}
+		p.PrintRecords(headers, rows)
+		return nil
+	},
+}
+
+// nameMatcher returns a function reporting whether a name, such as an
+// instance name or a config key, matches query, either as a
+// case-insensitive substring or as a regexp.
+func nameMatcher(query string, useRegex bool) (func(string) bool, error) {
+	if useRegex {
+		re, err := regexp.Compile(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", query, err)
+		}
+		return re.MatchString, nil
+	}
+
+	query = strings.ToLower(query)
+	return func(name string) bool {
+		return strings.Contains(strings.ToLower(name), query)
+	}, nil
+}
+
+func init() {
+	instanceSearchCmd.Flags().Bool("regex", false, "Treat the query as a regular expression")
+}
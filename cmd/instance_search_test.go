@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceNameMatcher(t *testing.T) {
+	match, err := nameMatcher("Broker", false)
+	require.NoError(t, err)
+	assert.True(t, match("production-broker"))
+	assert.False(t, match("orders"))
+
+	match, err = nameMatcher("^prod-.*-eu$", true)
+	require.NoError(t, err)
+	assert.True(t, match("prod-orders-eu"))
+	assert.False(t, match("staging-prod-orders-eu"))
+
+	_, err = nameMatcher("prod-(", true)
+	assert.ErrorContains(t, err, "invalid regular expression")
+}
+
+func TestInstanceSearchCmd(t *testing.T) {
+	useFakeClient(t, &fakeClient{instances: map[int]*client.Instance{
+		1234: {ID: 1234, Name: "production-broker"},
+		5678: {ID: 5678, Name: "staging-Broker"},
+		9012: {ID: 9012, Name: "orders"},
+	}})
+
+	cmd := instanceSearchCmd
+	cmd.InheritedFlags() // merge --quiet from root, as Execute would
+	rootCmd.PersistentFlags().Set("quiet", "true")
+	defer rootCmd.PersistentFlags().Set("quiet", "false")
+
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{"broker"}))
+	})
+
+	assert.Equal(t, "1234\n5678\n", out)
+}
@@ -0,0 +1,320 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"cloudamqp-cli/client"
+)
+
+// InstanceSpec is a portable description of an instance, written by
+// instance export and read by instance apply.
+type InstanceSpec struct {
+	Name     string                 `yaml:"name" json:"name"`
+	Plan     string                 `yaml:"plan" json:"plan"`
+	Region   string                 `yaml:"region" json:"region"`
+	Tags     []string               `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Config   map[string]interface{} `yaml:"config,omitempty" json:"config,omitempty"`
+	Plugins  []string               `yaml:"plugins,omitempty" json:"plugins,omitempty"`
+	Alarms   []AlarmSpec            `yaml:"alarms,omitempty" json:"alarms,omitempty"`
+	Firewall []FirewallRuleSpec     `yaml:"firewall,omitempty" json:"firewall,omitempty"`
+}
+
+// AlarmSpec is an alarm in an InstanceSpec.
+type AlarmSpec struct {
+	Type           string `yaml:"type" json:"type"`
+	Enabled        bool   `yaml:"enabled" json:"enabled"`
+	ValueThreshold int    `yaml:"value_threshold,omitempty" json:"value_threshold,omitempty"`
+	TimeThreshold  int    `yaml:"time_threshold,omitempty" json:"time_threshold,omitempty"`
+	VHostRegex     string `yaml:"vhost_regex,omitempty" json:"vhost_regex,omitempty"`
+	QueueRegex     string `yaml:"queue_regex,omitempty" json:"queue_regex,omitempty"`
+	Recipients     []int  `yaml:"recipients,omitempty" json:"recipients,omitempty"`
+}
+
+// FirewallRuleSpec is a firewall rule in an InstanceSpec.
+type FirewallRuleSpec struct {
+	IP          string   `yaml:"ip" json:"ip"`
+	Services    []string `yaml:"services,omitempty" json:"services,omitempty"`
+	Ports       []int    `yaml:"ports,omitempty" json:"ports,omitempty"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// buildInstanceSpec fetches the instance and its config, plugins, alarms and
+// firewall rules concurrently. With partial set, sections that can't be
+// fetched are left out with a warning instead of failing the export.
+func buildInstanceSpec(c client.ClientAPI, instanceID int, partial bool) (*InstanceSpec, error) {
+	instance, err := c.GetInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("instance %d not found", instanceID)
+	}
+
+	spec := &InstanceSpec{
+		Name:   instance.Name,
+		Plan:   instance.Plan,
+		Region: instance.Region,
+		Tags:   instance.Tags,
+	}
+
+	id := strconv.Itoa(instanceID)
+	sections := map[string]func() error{
+		"config": func() error {
+			config, err := c.GetRabbitMQConfig(id)
+			if err != nil {
+				return err
+			}
+			spec.Config = portableConfig(config)
+			return nil
+		},
+		"plugins": func() error {
+			plugins, err := c.ListPlugins(id)
+			if err != nil {
+				return err
+			}
+			for _, p := range plugins {
+				if p.Enabled {
+					spec.Plugins = append(spec.Plugins, p.Name)
+				}
+			}
+			sort.Strings(spec.Plugins)
+			return nil
+		},
+		"alarms": func() error {
+			alarms, err := c.ListAlarms(id)
+			if err != nil {
+				return err
+			}
+			for _, a := range alarms {
+				spec.Alarms = append(spec.Alarms, AlarmSpec{
+					Type:           a.Type,
+					Enabled:        a.Enabled,
+					ValueThreshold: a.ValueThreshold,
+					TimeThreshold:  a.TimeThreshold,
+					VHostRegex:     a.VHostRegex,
+					QueueRegex:     a.QueueRegex,
+					Recipients:     a.Recipients,
+				})
+			}
+			return nil
+		},
+		"firewall": func() error {
+			rules, err := c.GetFirewall(id)
+			if err != nil {
+				return err
+			}
+			for _, r := range rules {
+				spec.Firewall = append(spec.Firewall, FirewallRuleSpec(r))
+			}
+			return nil
+		},
+	}
+
+	errs := fetchConcurrently(sections)
+	for _, name := range sortedKeys(errs) {
+		if !partial {
+			return nil, fmt.Errorf("failed to export %s: %w", name, errs[name])
+		}
+		logWarn("%s left out of the export: %v", name, errs[name])
+	}
+
+	return spec, nil
+}
+
+// fetchConcurrently runs the named fetchers concurrently and returns the
+// errors of those that failed, keyed by name.
+func fetchConcurrently(fetchers map[string]func() error) map[string]error {
+	var (
+		mu   sync.Mutex
+		errs = map[string]error{}
+		wg   sync.WaitGroup
+	)
+	for name, fetch := range fetchers {
+		wg.Add(1)
+		go func(name string, fetch func() error) {
+			defer wg.Done()
+			if err := fetch(); err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name, fetch)
+	}
+	wg.Wait()
+	return errs
+}
+
+// specChange is one difference between an instance and its spec, along with
+// the call that brings the instance in line.
+type specChange struct {
+	Description string
+	Apply       func() error
+}
+
+// diffInstanceSpec compares the instance with the desired spec and returns
+// the changes needed. Only sections present in the spec are compared, and
+// config keys not mentioned in the spec are left alone. Alarms are not
+// reconciled. A smaller plan or a config value outside its safe range is
+// refused unless force is set.
+func diffInstanceSpec(c client.ClientAPI, instanceID int, desired *InstanceSpec, force bool) ([]specChange, error) {
+	instance, err := c.GetInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("instance %d not found", instanceID)
+	}
+
+	id := strconv.Itoa(instanceID)
+	var changes []specChange
+
+	if desired.Config != nil {
+		current, err := c.GetRabbitMQConfig(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config: %w", err)
+		}
+		for _, key := range sortedKeys(desired.Config) {
+			want := desired.Config[key]
+			have, ok := current[key]
+			if ok && sameConfigValue(have, want) {
+				continue
+			}
+			if err := checkConfigSafety(key, want); err != nil {
+				if !force {
+					return nil, fmt.Errorf("%w. Use --force to apply it anyway", err)
+				}
+				logWarn("%v", err)
+			}
+			changes = append(changes, specChange{
+				Description: fmt.Sprintf("~ config %s: %s -> %v", key, formatSpecValue(have, ok), want),
+				Apply: func() error {
+					return c.UpdateRabbitMQConfig(id, map[string]interface{}{key: want})
+				},
+			})
+		}
+	}
+
+	if desired.Plugins != nil {
+		plugins, err := c.ListPlugins(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list plugins: %w", err)
+		}
+		enabled := map[string]bool{}
+		for _, p := range plugins {
+			if p.Enabled {
+				enabled[p.Name] = true
+			}
+		}
+		wanted := map[string]bool{}
+		for _, name := range desired.Plugins {
+			wanted[name] = true
+			if !enabled[name] {
+				changes = append(changes, specChange{
+					Description: "+ plugin " + name,
+					Apply:       func() error { return c.EnablePlugin(id, name) },
+				})
+			}
+		}
+		for _, name := range sortedKeys(enabled) {
+			if !wanted[name] {
+				changes = append(changes, specChange{
+					Description: "- plugin " + name,
+					Apply:       func() error { return c.DisablePlugin(id, name) },
+				})
+			}
+		}
+	}
+
+	if desired.Firewall != nil {
+		current, err := c.GetFirewall(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get firewall rules: %w", err)
+		}
+		rules := make([]client.FirewallRule, len(desired.Firewall))
+		for i, r := range desired.Firewall {
+			if err := validateCIDR(r.IP); err != nil {
+				return nil, err
+			}
+			rules[i] = client.FirewallRule(r)
+		}
+		if !sameFirewallRules(current, rules) {
+			changes = append(changes, specChange{
+				Description: fmt.Sprintf("~ firewall: %d rule(s) -> %d rule(s)", len(current), len(rules)),
+				Apply:       func() error { return c.UpdateFirewall(id, rules) },
+			})
+		}
+	}
+
+	if desired.Name != "" && desired.Name != instance.Name {
+		changes = append(changes, specChange{
+			Description: fmt.Sprintf("~ name: %s -> %s", instance.Name, desired.Name),
+			Apply: func() error {
+				return c.UpdateInstance(instanceID, &client.InstanceUpdateRequest{Name: desired.Name})
+			},
+		})
+	}
+
+	if tags := parseTags(desired.Tags); len(tags) > 0 && strings.Join(tags, ",") != strings.Join(instance.Tags, ",") {
+		changes = append(changes, specChange{
+			Description: fmt.Sprintf("~ tags: [%s] -> [%s]", strings.Join(instance.Tags, ", "), strings.Join(tags, ", ")),
+			Apply: func() error {
+				return c.UpdateInstance(instanceID, &client.InstanceUpdateRequest{Tags: tags})
+			},
+		})
+	}
+
+	// The plan goes last, as changing it restarts the cluster
+	if desired.Plan != "" && desired.Plan != instance.Plan {
+		if err := checkPlanDowngrade(instance.Plan, desired.Plan, force); err != nil {
+			return nil, err
+		}
+		changes = append(changes, specChange{
+			Description: fmt.Sprintf("~ plan: %s -> %s", instance.Plan, desired.Plan),
+			Apply: func() error {
+				return c.UpdateInstance(instanceID, &client.InstanceUpdateRequest{Plan: desired.Plan})
+			},
+		})
+	}
+
+	return changes, nil
+}
+
+// formatSpecValue renders a current value in a change description.
+func formatSpecValue(value interface{}, ok bool) string {
+	if !ok {
+		return "(unset)"
+	}
+	return fmt.Sprint(value)
+}
+
+// sameFirewallRules reports whether two rule lists are equal, ignoring the
+// order of rules, services and ports.
+func sameFirewallRules(a, b []client.FirewallRule) bool {
+	normalize := func(rules []client.FirewallRule) []string {
+		keys := make([]string, len(rules))
+		for i, r := range rules {
+			services := append([]string(nil), r.Services...)
+			sort.Strings(services)
+			ports := append([]int(nil), r.Ports...)
+			sort.Ints(ports)
+			keys[i] = fmt.Sprintf("%s|%v|%v|%s", r.IP, services, ports, r.Description)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+	return fmt.Sprint(normalize(a)) == fmt.Sprint(normalize(b))
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
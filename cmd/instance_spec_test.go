@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// specClient serves the pieces of an instance spec.
+type specClient struct {
+	fakeClient
+	config      map[string]interface{}
+	plugins     []client.Plugin
+	alarms      []client.Alarm
+	firewall    []client.FirewallRule
+	firewallErr error
+	calls       []string
+}
+
+func (s *specClient) UpdateInstance(id int, req *client.InstanceUpdateRequest) error {
+	s.calls = append(s.calls, fmt.Sprintf("UpdateInstance %d name=%q plan=%q tags=%v", id, req.Name, req.Plan, req.Tags))
+	return nil
+}
+
+func (s *specClient) UpdateRabbitMQConfig(id string, config map[string]interface{}) error {
+	s.calls = append(s.calls, fmt.Sprintf("UpdateRabbitMQConfig %v", config))
+	return nil
+}
+
+func (s *specClient) EnablePlugin(id, name string) error {
+	s.calls = append(s.calls, "EnablePlugin "+name)
+	return nil
+}
+
+func (s *specClient) DisablePlugin(id, name string) error {
+	s.calls = append(s.calls, "DisablePlugin "+name)
+	return nil
+}
+
+func (s *specClient) UpdateFirewall(id string, rules []client.FirewallRule) error {
+	s.calls = append(s.calls, fmt.Sprintf("UpdateFirewall %d", len(rules)))
+	return nil
+}
+
+func (s *specClient) GetRabbitMQConfig(string) (map[string]interface{}, error) {
+	return s.config, nil
+}
+
+func (s *specClient) ListPlugins(string) ([]client.Plugin, error) {
+	return s.plugins, nil
+}
+
+func (s *specClient) ListAlarms(string) ([]client.Alarm, error) {
+	return s.alarms, nil
+}
+
+func (s *specClient) GetFirewall(string) ([]client.FirewallRule, error) {
+	return s.firewall, s.firewallErr
+}
+
+func newSpecClient() *specClient {
+	return &specClient{
+		fakeClient: fakeClient{instances: map[int]*client.Instance{
+			1234: {ID: 1234, Name: "orders", Plan: "bunny-1", Region: "amazon-web-services::us-east-1", Tags: []string{"prod"}},
+		}},
+		config: map[string]interface{}{"rabbit.heartbeat": float64(120)},
+		plugins: []client.Plugin{
+			{Name: "rabbitmq_shovel", Enabled: true},
+			{Name: "rabbitmq_mqtt", Enabled: false},
+			{Name: "rabbitmq_federation", Enabled: true},
+		},
+		alarms:   []client.Alarm{{ID: 9, Type: "cpu", Enabled: true, ValueThreshold: 90, TimeThreshold: 600, Recipients: []int{1}}},
+		firewall: []client.FirewallRule{{IP: "10.0.0.0/24", Services: []string{"AMQPS"}, Ports: []int{}}},
+	}
+}
+
+func TestBuildInstanceSpec(t *testing.T) {
+	spec, err := buildInstanceSpec(newSpecClient(), 1234, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, "orders", spec.Name)
+	assert.Equal(t, "bunny-1", spec.Plan)
+	assert.Equal(t, []string{"prod"}, spec.Tags)
+	assert.Equal(t, []string{"rabbitmq_federation", "rabbitmq_shovel"}, spec.Plugins)
+	assert.Equal(t, []AlarmSpec{{Type: "cpu", Enabled: true, ValueThreshold: 90, TimeThreshold: 600, Recipients: []int{1}}}, spec.Alarms)
+	assert.Equal(t, "10.0.0.0/24", spec.Firewall[0].IP)
+
+	data, err := yaml.Marshal(spec)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "value_threshold: 90")
+	assert.Contains(t, string(data), "rabbit.heartbeat: 120")
+}
+
+func TestBuildInstanceSpec_PortableConfig(t *testing.T) {
+	c := newSpecClient()
+	c.config = map[string]interface{}{
+		"rabbit.max_message_size":         float64(134217728),
+		"rabbit.vm_memory_high_watermark": 0.6,
+		"rabbit.consumer_timeout":         nil,
+	}
+
+	spec, err := buildInstanceSpec(c, 1234, false)
+	require.NoError(t, err)
+
+	data, err := marshalSpecYAML(spec)
+	require.NoError(t, err)
+	out := string(data)
+	assert.Contains(t, out, "rabbit.max_message_size: 134217728\n", "whole numbers are written as integers")
+	assert.Contains(t, out, "rabbit.vm_memory_high_watermark: 0.6\n")
+	assert.NotContains(t, out, "consumer_timeout", "unset settings are left out")
+	assert.Less(t, strings.Index(out, "alarms:"), strings.Index(out, "name:"), "keys are sorted as in config export")
+}
+
+func TestBuildInstanceSpec_SectionError(t *testing.T) {
+	c := newSpecClient()
+	c.firewallErr = errors.New("API error (403): not available on shared plans")
+
+	_, err := buildInstanceSpec(c, 1234, false)
+	assert.ErrorContains(t, err, "failed to export firewall")
+
+	spec, err := buildInstanceSpec(c, 1234, true)
+	require.NoError(t, err)
+	assert.Empty(t, spec.Firewall)
+	assert.Equal(t, "orders", spec.Name)
+}
+
+func TestDiffInstanceSpec_NoChanges(t *testing.T) {
+	c := newSpecClient()
+	spec, err := buildInstanceSpec(c, 1234, false)
+	require.NoError(t, err)
+
+	changes, err := diffInstanceSpec(c, 1234, spec, false)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiffInstanceSpec_AppliesOnlyDifferences(t *testing.T) {
+	c := newSpecClient()
+	desired := &InstanceSpec{
+		Name:     "orders",
+		Plan:     "hare-1",
+		Tags:     []string{"prod", "team-a"},
+		Config:   map[string]interface{}{"rabbit.heartbeat": 120, "rabbit.channel_max": 100},
+		Plugins:  []string{"rabbitmq_shovel", "rabbitmq_mqtt"},
+		Firewall: []FirewallRuleSpec{{IP: "10.0.0.0/24", Services: []string{"AMQPS"}}},
+	}
+
+	changes, err := diffInstanceSpec(c, 1234, desired, false)
+	require.NoError(t, err)
+
+	descriptions := make([]string, len(changes))
+	for i, change := range changes {
+		descriptions[i] = change.Description
+		require.NoError(t, change.Apply())
+	}
+	assert.Equal(t, []string{
+		"~ config rabbit.channel_max: (unset) -> 100",
+		"+ plugin rabbitmq_mqtt",
+		"- plugin rabbitmq_federation",
+		"~ tags: [prod] -> [prod, team-a]",
+		"~ plan: bunny-1 -> hare-1",
+	}, descriptions)
+	assert.Equal(t, []string{
+		"UpdateRabbitMQConfig map[rabbit.channel_max:100]",
+		"EnablePlugin rabbitmq_mqtt",
+		"DisablePlugin rabbitmq_federation",
+		`UpdateInstance 1234 name="" plan="" tags=[prod team-a]`,
+		`UpdateInstance 1234 name="" plan="hare-1" tags=[]`,
+	}, c.calls)
+}
+
+func TestDiffInstanceSpec_RefusesDowngrade(t *testing.T) {
+	_, err := diffInstanceSpec(newSpecClient(), 1234, &InstanceSpec{Plan: "lemur"}, false)
+	assert.ErrorContains(t, err, "--force")
+}
+
+func TestDiffInstanceSpec_LargeNumbers(t *testing.T) {
+	c := newSpecClient()
+	c.config = map[string]interface{}{"rabbit.max_message_size": float64(134217728)}
+
+	changes, err := diffInstanceSpec(c, 1234, &InstanceSpec{Config: map[string]interface{}{"rabbit.max_message_size": 134217728}}, false)
+	require.NoError(t, err)
+	assert.Empty(t, changes, "an integer in the spec matches the float64 the API returns")
+}
+
+func TestDiffInstanceSpec_UnsafeConfig(t *testing.T) {
+	desired := &InstanceSpec{Config: map[string]interface{}{"rabbit.vm_memory_high_watermark": 0.95}}
+
+	_, err := diffInstanceSpec(newSpecClient(), 1234, desired, false)
+	assert.ErrorContains(t, err, "outside the safe range")
+	assert.ErrorContains(t, err, "Use --force to apply it anyway")
+
+	changes, err := diffInstanceSpec(newSpecClient(), 1234, desired, true)
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+}
+
+func TestResolveSpecInstance(t *testing.T) {
+	c := newSpecClient()
+
+	id, err := resolveSpecInstance(c, "", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, 1234, id)
+
+	id, err = resolveSpecInstance(c, "42", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, 42, id)
+
+	_, err = resolveSpecInstance(c, "", "missing")
+	assert.ErrorContains(t, err, `no instance named "missing"`)
+}
+
+func TestInstanceApplyCmd_PrintSchema(t *testing.T) {
+	cmd := instanceApplyCmd
+	defer resetFlags(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--print-schema"}))
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	defer cmd.SetOut(nil)
+
+	require.NoError(t, cmd.RunE(cmd, []string{}), "no spec file or API key is needed")
+
+	var schema struct {
+		Schema     string         `json:"$schema"`
+		Required   []string       `json:"required"`
+		Properties map[string]any `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &schema))
+	assert.Equal(t, specSchemaURL, schema.Schema)
+	assert.Equal(t, []string{"name", "plan", "region"}, schema.Required, "fields without omitempty are required")
+	assert.ElementsMatch(t, []string{"name", "plan", "region", "tags", "config", "plugins", "alarms", "firewall"}, sortedKeys(schema.Properties))
+
+	firewall := schema.Properties["firewall"].(map[string]any)["items"].(map[string]any)
+	assert.Equal(t, []any{"ip"}, firewall["required"])
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "integer"}}, firewall["properties"].(map[string]any)["ports"])
+
+	config := schema.Properties["config"].(map[string]any)
+	assert.Equal(t, true, config["additionalProperties"])
+	assert.Equal(t, map[string]any{"type": "integer", "default": float64(120), "description": "RabbitMQ setting"}, config["properties"].(map[string]any)["rabbit.heartbeat"])
+	assert.Equal(t, map[string]any{"type": "integer", "default": float64(300), "description": "LavinMQ setting"}, config["properties"].(map[string]any)["amqp.heartbeat"])
+	assert.Equal(t, []any{"verify_none", "verify_peer"}, config["properties"].(map[string]any)["ssl_options.verify"].(map[string]any)["enum"])
+}
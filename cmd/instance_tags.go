@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var instanceTagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Manage instance tags",
+	Long:  `List, add, remove, and replace the tags of an instance.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Help()
+		cmd.SilenceUsage = true
+		return fmt.Errorf("subcommand required")
+	},
+}
+
+var instanceTagsListCmd = &cobra.Command{
+	Use:     "list --id <instance_id>",
+	Aliases: []string{"ls"},
+	Short:   "List instance tags",
+	Long:    `Retrieves the tags of the instance.`,
+	Example: `  cloudamqp instance tags list --id 1234`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		instanceID, err := flagInstanceID(cmd)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		instance, err := getInstance(c, instanceID)
+		if err != nil {
+			return fmt.Errorf("failed to get instance: %w", err)
+		}
+
+		if len(instance.Tags) == 0 {
+			printStatus(cmd, "No tags found.")
+			return nil
+		}
+
+		p, err := getListPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		rows := make([][]string, len(instance.Tags))
+		for i, tag := range instance.Tags {
+			rows[i] = []string{tag}
+		}
+		p.PrintRecords([]string{"TAG"}, rows)
+
+		return nil
+	},
+}
+
+var instanceTagsAddCmd = &cobra.Command{
+	Use:   "add --id <instance_id> --tag <tag>",
+	Short: "Add tags to an instance",
+	Long:  `Add one or more tags to the instance, keeping the existing tags.`,
+	Example: `  cloudamqp instance tags add --id 1234 --tag production
+  cloudamqp instance tags add --id 1234 --tag team-a --tag billing`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		return modifyInstanceTags(cmd, func(current []string) ([]string, error) {
+			return parseTags(append(current, tags...)), nil
+		})
+	},
+}
+
+var instanceTagsRemoveCmd = &cobra.Command{
+	Use:     "remove --id <instance_id> --tag <tag>",
+	Short:   "Remove tags from an instance",
+	Long:    `Remove one or more tags from the instance, keeping the other tags.`,
+	Example: `  cloudamqp instance tags remove --id 1234 --tag staging`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		return modifyInstanceTags(cmd, func(current []string) ([]string, error) {
+			return removeTags(current, tags)
+		})
+	},
+}
+
+var instanceTagsSetCmd = &cobra.Command{
+	Use:     "set --id <instance_id> --tags <a,b,c>",
+	Short:   "Replace all instance tags",
+	Long:    `Replace the tags of the instance with the given list.`,
+	Example: `  cloudamqp instance tags set --id 1234 --tags production,team-a`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+		tags = parseTags(tags)
+		if len(tags) == 0 {
+			return fmt.Errorf("at least one tag must be specified with --tags")
+		}
+
+		instanceID, err := flagInstanceID(cmd)
+		if err != nil {
+			return err
+		}
+
+		return saveInstanceTags(cmd, nil, instanceID, tags)
+	},
+}
+
+// modifyInstanceTags fetches the current tags, applies change and saves
+// the result.
+func modifyInstanceTags(cmd *cobra.Command, change func(current []string) ([]string, error)) error {
+	instanceID, err := flagInstanceID(cmd)
+	if err != nil {
+		return err
+	}
+
+	apiKey, err := getAPIKey()
+	if err != nil {
+		return fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	c := newAPIClient(apiKey)
+
+	instance, err := getInstance(c, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to get instance: %w", err)
+	}
+
+	tags, err := change(instance.Tags)
+	if err != nil {
+		return err
+	}
+
+	return saveInstanceTags(cmd, c, instanceID, tags)
+}
+
+// saveInstanceTags saves tags on the instance, creating a client when c
+// is nil.
+func saveInstanceTags(cmd *cobra.Command, c client.ClientAPI, instanceID int, tags []string) error {
+	req := &client.InstanceUpdateRequest{Tags: tags}
+
+	if isDryRun(cmd) {
+		return printDryRun(cmd, "PUT", "/instances/"+strconv.Itoa(instanceID), req.Form())
+	}
+
+	if c == nil {
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+		c = newAPIClient(apiKey)
+	}
+
+	if err := c.UpdateInstance(instanceID, req); err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+
+	printStatus(cmd, "Instance %d tags: %s", instanceID, strings.Join(tags, ", "))
+	return nil
+}
+
+// parseTags normalizes tags from --tags flags, spec files or the API: it
+// splits comma-separated entries, trims whitespace and drops empty and
+// repeated tags, keeping the order of first occurrence. All commands that
+// take tags run them through here so --tags a --tags b and --tags a,b agree.
+func parseTags(tags []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, entry := range tags {
+		for _, tag := range strings.Split(entry, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// removeTags returns current without the tags in remove. The API cannot
+// clear all tags, so removing the last one is an error.
+func removeTags(current, remove []string) ([]string, error) {
+	drop := map[string]bool{}
+	for _, tag := range parseTags(remove) {
+		drop[tag] = true
+	}
+
+	var result []string
+	for _, tag := range parseTags(current) {
+		if !drop[tag] {
+			result = append(result, tag)
+		}
+	}
+
+	if len(result) == len(parseTags(current)) {
+		return nil, fmt.Errorf("none of the tags %s are set on the instance", strings.Join(remove, ", "))
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("cannot remove all tags: an instance update cannot clear the tag list")
+	}
+	return result, nil
+}
+
+func init() {
+	instanceTagsListCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceTagsListCmd.MarkFlagRequired("id")
+
+	instanceTagsAddCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceTagsAddCmd.Flags().StringSlice("tag", []string{}, "Tag to add (can be repeated)")
+	addDryRunFlag(instanceTagsAddCmd)
+	instanceTagsAddCmd.MarkFlagRequired("id")
+	instanceTagsAddCmd.MarkFlagRequired("tag")
+
+	instanceTagsRemoveCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceTagsRemoveCmd.Flags().StringSlice("tag", []string{}, "Tag to remove (can be repeated)")
+	addDryRunFlag(instanceTagsRemoveCmd)
+	instanceTagsRemoveCmd.MarkFlagRequired("id")
+	instanceTagsRemoveCmd.MarkFlagRequired("tag")
+
+	instanceTagsSetCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceTagsSetCmd.Flags().StringSlice("tags", []string{}, "Complete list of tags (required)")
+	addDryRunFlag(instanceTagsSetCmd)
+	instanceTagsSetCmd.MarkFlagRequired("id")
+	instanceTagsSetCmd.MarkFlagRequired("tags")
+
+	for _, cmd := range []*cobra.Command{instanceTagsListCmd, instanceTagsAddCmd, instanceTagsRemoveCmd, instanceTagsSetCmd} {
+		cmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+	}
+
+	instanceTagsCmd.AddCommand(instanceTagsListCmd)
+	instanceTagsCmd.AddCommand(instanceTagsAddCmd)
+	instanceTagsCmd.AddCommand(instanceTagsRemoveCmd)
+	instanceTagsCmd.AddCommand(instanceTagsSetCmd)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTags(t *testing.T) {
+	assert.Equal(t, []string{"b", "a", "c"}, parseTags([]string{"b", " a", "b", "", "c", "a"}))
+	assert.Equal(t, []string{"a", "b", "c"}, parseTags([]string{"a,b", " c , a ,,"}))
+	assert.Equal(t, []string{}, parseTags(nil))
+}
+
+func TestRemoveTags(t *testing.T) {
+	tags, err := removeTags([]string{"prod", "team-a", "billing"}, []string{"team-a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod", "billing"}, tags)
+
+	_, err = removeTags([]string{"prod"}, []string{"staging"})
+	assert.ErrorContains(t, err, "none of the tags")
+
+	_, err = removeTags([]string{"prod"}, []string{"prod"})
+	assert.ErrorContains(t, err, "cannot remove all tags")
+}
+
+func TestInstanceTagsSetCmd_DryRun(t *testing.T) {
+	t.Setenv("CLOUDAMQP_APIKEY", "")
+	t.Setenv("HOME", t.TempDir())
+
+	cmd := instanceTagsSetCmd
+	cmd.InheritedFlags() // merge --output from root, as Execute would
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("tags", "a,b,a")
+	cmd.Flags().Set("dry-run", "true")
+	rootCmd.PersistentFlags().Set("output", "json")
+	defer func() {
+		resetFlags(cmd)
+		rootCmd.PersistentFlags().Set("output", "table")
+	}()
+
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+
+	var record map[string]string
+	require.NoError(t, json.Unmarshal([]byte(out), &record))
+	assert.Equal(t, "/instances/1234", record["path"])
+	assert.Equal(t, "tags%5B%5D=a&tags%5B%5D=b", record["body"])
+}
@@ -3,8 +3,11 @@ package cmd
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"cloudamqp-cli/client"
+	"cloudamqp-cli/internal/table"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +16,11 @@ var (
 	updateInstanceName string
 	updateInstancePlan string
 	updateInstanceTags []string
+	updateForce        bool
+	updateWait         bool
+	updateWaitTimeout  string
+	updateConfigFile   string
+	updateYes          bool
 )
 
 var instanceUpdateCmd = &cobra.Command{
@@ -23,55 +31,307 @@ var instanceUpdateCmd = &cobra.Command{
 You can update the following fields:
   --name: Instance name
   --plan: Subscription plan
-  --tags: Instance tags (replaces existing tags)`,
+  --tags: Instance tags (replaces existing tags)
+
+Changing to a smaller plan is refused unless --force is given, since a
+downgrade (especially from a dedicated to a shared plan) can fail or lose
+data and features.
+
+Before a plan change, the current and new plan are compared side by side:
+price, nodes and, where the API provides them, memory, disk and connection
+limits. Confirm the change interactively, or pass --yes to skip the question;
+non-interactive runs need --yes. If the new plan isn't in the plan list, the
+comparison and the question are skipped.
+
+Plan changes happen in the background. With --wait the command blocks until
+the instance reports the new plan and is ready again; for other changes it
+waits until the instance is ready.
+
+--config-file applies broker configuration from a YAML or JSON file after
+the instance update: the command waits (up to --wait-timeout) until the
+instance is on the new plan and ready, then updates the config. The file is
+validated against the settings of the broker the instance runs (RabbitMQ or
+LavinMQ) before anything is changed, and values outside a setting's safe
+range are refused unless --force is given. If a phase fails, the error says which
+one and whether the instance update was already applied.`,
 	Example: `  cloudamqp instance update --id 1234 --name=new-name
   cloudamqp instance update --id 1234 --plan=rabbit-1
-  cloudamqp instance update --id 1234 --tags=production --tags=updated`,
+  cloudamqp instance update --id 1234 --plan=rabbit-3 --yes
+  cloudamqp instance update --id 1234 --plan=rabbit-1 --wait --wait-timeout=30m
+  cloudamqp instance update --id 1234 --tags=production --tags=updated
+  cloudamqp instance update --id 1234 --plan=rabbit-2 --config-file=config.yaml
+  cloudamqp instance update --id 1234 --plan=rabbit-1 --dry-run
+  cloudamqp instance update --id 1234 --plan=bunny-1 --force`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
-		apiKey, err = getAPIKey()
+		instanceID, err := flagInstanceID(cmd)
+		if err != nil {
+			return err
+		}
+
+		req, err := buildInstanceUpdateRequest(cmd)
 		if err != nil {
-			return fmt.Errorf("failed to get API key: %w", err)
+			return err
 		}
 
-		if updateInstanceID == "" {
-			return fmt.Errorf("--id is required")
+		var config map[string]any
+		if updateConfigFile != "" {
+			config, err = readConfigFile(updateConfigFile)
+			if err != nil {
+				return err
+			}
 		}
+		updateInstance := req.Name != "" || req.Plan != "" || len(req.Tags) > 0
 
-		instanceID, err := strconv.Atoi(updateInstanceID)
-		if err != nil {
-			return fmt.Errorf("invalid instance ID: %v", err)
+		var timeout time.Duration
+		if updateWait || (config != nil && updateInstance) {
+			timeout, err = time.ParseDuration(updateWaitTimeout)
+			if err != nil {
+				return fmt.Errorf("invalid wait-timeout value: %v", err)
+			}
 		}
 
-		c := client.New(apiKey, Version)
+		// A dry run only needs the API to find the broker the config is for
+		var c client.ClientAPI
+		if config != nil || !isDryRun(cmd) {
+			apiKey, err = getAPIKey()
+			if err != nil {
+				return fmt.Errorf("failed to get API key: %w", err)
+			}
+			c = newAPIClient(apiKey)
+		}
 
-		req := &client.InstanceUpdateRequest{
-			Name: updateInstanceName,
-			Plan: updateInstancePlan,
-			Tags: updateInstanceTags,
+		backend := client.BackendRabbitMQ
+		if config != nil {
+			backend, err = instanceBackend(c, instanceID)
+			if err != nil {
+				return err
+			}
+			if problems := validateConfigValues(backend, config); len(problems) > 0 {
+				for _, problem := range problems {
+					logError("%v", problem)
+				}
+				return fmt.Errorf("%s: %d of %d settings are invalid", updateConfigFile, len(problems), len(config))
+			}
+			if err := checkConfigValuesSafety(config, updateForce, "apply"); err != nil {
+				return err
+			}
 		}
 
-		if req.Name == "" && req.Plan == "" && len(req.Tags) == 0 {
-			return fmt.Errorf("at least one field must be specified for update")
+		if isDryRun(cmd) {
+			if updateInstance {
+				if err := printDryRun(cmd, "PUT", "/instances/"+strconv.Itoa(instanceID), req.Form()); err != nil {
+					return err
+				}
+			}
+			if config != nil {
+				endpoint, err := configEndpoint(backend, strconv.Itoa(instanceID))
+				if err != nil {
+					return err
+				}
+				return printDryRun(cmd, "PUT", endpoint, config)
+			}
+			return nil
+		}
+
+		if req.Plan != "" {
+			instance, err := getInstance(c, instanceID)
+			if err != nil {
+				return fmt.Errorf("failed to get instance: %w", err)
+			}
+			if err := checkPlanDowngrade(instance.Plan, req.Plan, updateForce); err != nil {
+				return err
+			}
+			if ok, err := confirmPlanChange(cmd, c, instance.Plan, req.Plan); err != nil || !ok {
+				return err
+			}
+		}
+
+		if !updateInstance {
+			return applyUpdateConfig(cmd, c, backend, instanceID, config, false)
 		}
 
 		err = c.UpdateInstance(instanceID, req)
 		if err != nil {
-			fmt.Printf("Error updating instance: %v\n", err)
-			return err
+			if config != nil {
+				return fmt.Errorf("instance update failed, config was not applied: %w", err)
+			}
+			return fmt.Errorf("failed to update instance: %w", err)
 		}
 
-		fmt.Printf("Instance %d updated successfully.\n", instanceID)
+		printStatus(cmd, "Instance %d updated successfully.", instanceID)
+
+		if updateWait || config != nil {
+			if req.Plan != "" {
+				err = waitForPlanChange(commandContext(cmd), c, instanceID, req.Plan, timeout)
+			} else {
+				err = waitForInstanceReady(commandContext(cmd), c, instanceID, timeout)
+			}
+			// Config pushed before the management API answers may be lost
+			if err == nil && config != nil {
+				err = waitForInstanceHealthy(commandContext(cmd), c, instanceID, timeout)
+			}
+			if err != nil {
+				if config != nil {
+					return fmt.Errorf("instance was updated, but waiting for it failed and config was not applied: %w", err)
+				}
+				return fmt.Errorf("wait failed: %w", err)
+			}
+		}
+
+		if config != nil {
+			return applyUpdateConfig(cmd, c, backend, instanceID, config, true)
+		}
 		return nil
 	},
 }
 
+// applyUpdateConfig is the config phase of instance update --config-file.
+// instanceUpdated says whether the instance phase already ran, for the error.
+func applyUpdateConfig(cmd *cobra.Command, c client.ClientAPI, backend string, instanceID int, config map[string]any, instanceUpdated bool) error {
+	if err := updateInstanceConfig(c, backend, strconv.Itoa(instanceID), config); err != nil {
+		if instanceUpdated {
+			return fmt.Errorf("instance was updated, but the config update failed: %w", err)
+		}
+		return fmt.Errorf("config update failed: %w", err)
+	}
+	printStatus(cmd, "Instance %d: %d config settings applied from %s.", instanceID, len(config), updateConfigFile)
+	return nil
+}
+
+// confirmPlanChange prints a comparison of the current and new plan and
+// asks for confirmation unless --yes is given. When either plan isn't in
+// the plan list there is nothing to show, and the change goes ahead; other
+// errors getting the plans are returned.
+func confirmPlanChange(cmd *cobra.Command, c client.ClientAPI, current, plan string) (bool, error) {
+	if current == plan {
+		return true, nil
+	}
+	from, err := c.GetPlan(current)
+	if client.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get plan %s: %w", current, err)
+	}
+	to, err := c.GetPlan(plan)
+	if client.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get plan %s: %w", plan, err)
+	}
+
+	t := table.New(cmd.ErrOrStderr(), "", "CURRENT", "NEW")
+	for _, row := range planComparison(from, to) {
+		t.AddRow(row...)
+	}
+	t.Print()
+
+	if updateYes {
+		return true, nil
+	}
+	ok, err := confirm(cmd, fmt.Sprintf("Change plan from %s to %s?", current, plan), "--yes")
+	if err == nil && !ok {
+		printStatus(cmd, "Update cancelled.")
+	}
+	return ok, err
+}
+
+// planComparison returns the rows comparing two plans. Specs the API left
+// out of both plans are skipped.
+func planComparison(from, to *client.Plan) [][]string {
+	rows := [][]string{
+		{"PLAN", from.Name, to.Name},
+		{"PRICE", formatPlanPrice(from.Price), formatPlanPrice(to.Price)},
+		{"NODES", formatPlanSpec(from.Nodes, ""), formatPlanSpec(to.Nodes, "")},
+	}
+	specs := []struct {
+		name     string
+		from, to int
+		unit     string
+	}{
+		{"MEMORY", from.MemoryGB, to.MemoryGB, " GB"},
+		{"DISK", from.DiskGB, to.DiskGB, " GB"},
+		{"CONNECTIONS", from.MaxConnections, to.MaxConnections, ""},
+	}
+	for _, s := range specs {
+		if s.from == 0 && s.to == 0 {
+			continue
+		}
+		rows = append(rows, []string{s.name, formatPlanSpec(s.from, s.unit), formatPlanSpec(s.to, s.unit)})
+	}
+	return rows
+}
+
+func formatPlanSpec(value int, unit string) string {
+	if value == 0 {
+		return "-"
+	}
+	return strconv.Itoa(value) + unit
+}
+
+// buildInstanceUpdateRequest includes only the fields the user set, so a
+// partial update doesn't clobber the others.
+func buildInstanceUpdateRequest(cmd *cobra.Command) (*client.InstanceUpdateRequest, error) {
+	req := &client.InstanceUpdateRequest{}
+
+	if cmd.Flags().Changed("name") {
+		req.Name = strings.TrimSpace(updateInstanceName)
+		if req.Name == "" {
+			return nil, fmt.Errorf("--name must not be empty")
+		}
+	}
+	if cmd.Flags().Changed("plan") {
+		req.Plan = strings.TrimSpace(updateInstancePlan)
+		if req.Plan == "" {
+			return nil, fmt.Errorf("--plan must not be empty")
+		}
+	}
+	if cmd.Flags().Changed("tags") {
+		req.Tags = parseTags(updateInstanceTags)
+		if len(req.Tags) == 0 {
+			return nil, fmt.Errorf("--tags must contain at least one tag")
+		}
+	}
+
+	if req.Name == "" && req.Plan == "" && len(req.Tags) == 0 && updateConfigFile == "" {
+		return nil, fmt.Errorf("at least one field must be specified for update")
+	}
+
+	return req, nil
+}
+
+// checkPlanDowngrade refuses a plan downgrade unless force is set, and
+// warns about what may be lost when it is.
+func checkPlanDowngrade(current, plan string, force bool) error {
+	if !isPlanDowngrade(current, plan) {
+		return nil
+	}
+
+	warning := fmt.Sprintf("changing plan from %s to %s is a downgrade", current, plan)
+	if !sharedPlans[current] && sharedPlans[plan] {
+		warning += " from a dedicated to a shared plan; data, configuration and features such as plugins may be lost"
+	} else {
+		warning += " and may fail if the instance uses more resources than the new plan provides"
+	}
+
+	if !force {
+		return fmt.Errorf("%s. Use --force to proceed anyway", warning)
+	}
+	logWarn("%s.", warning)
+	return nil
+}
+
 func init() {
-	instanceUpdateCmd.Flags().StringVar(&updateInstanceID, "id", "", "Instance ID (required)")
+	instanceUpdateCmd.Flags().StringVar(&updateInstanceID, "id", "", "Instance ID or name (required)")
 	instanceUpdateCmd.Flags().StringVar(&updateInstanceName, "name", "", "New instance name")
 	instanceUpdateCmd.Flags().StringVar(&updateInstancePlan, "plan", "", "New subscription plan")
 	instanceUpdateCmd.Flags().StringSliceVar(&updateInstanceTags, "tags", []string{}, "New instance tags")
+	instanceUpdateCmd.Flags().BoolVar(&updateForce, "force", false, "Allow changing to a smaller plan and config values outside their safe range")
+	instanceUpdateCmd.Flags().BoolVar(&updateWait, "wait", false, "Wait until the instance is on the new plan and ready")
+	instanceUpdateCmd.Flags().StringVar(&updateWaitTimeout, "wait-timeout", "15m", "Timeout for waiting (e.g., 15m, 30m)")
+	instanceUpdateCmd.Flags().StringVar(&updateConfigFile, "config-file", "", "YAML or JSON file with broker config to apply once the update is done")
+	instanceUpdateCmd.Flags().BoolVar(&updateYes, "yes", false, "Change the plan without asking for confirmation")
+	addDryRunFlag(instanceUpdateCmd)
 	instanceUpdateCmd.MarkFlagRequired("id")
 	instanceUpdateCmd.RegisterFlagCompletionFunc("id", completeInstances)
 	instanceUpdateCmd.RegisterFlagCompletionFunc("plan", completePlans)
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updateClient records the order of the calls instance update makes.
+type updateClient struct {
+	sequenceClient
+	calls     []string
+	configErr error
+	plans     []client.Plan
+	planErr   error
+}
+
+func (f *updateClient) UpdateInstance(id int, req *client.InstanceUpdateRequest) error {
+	f.calls = append(f.calls, "update "+req.Plan)
+	return nil
+}
+
+func (f *updateClient) InstanceHealthy(id int) (bool, error) {
+	f.calls = append(f.calls, "healthy")
+	return true, nil
+}
+
+func (f *updateClient) UpdateRabbitMQConfig(instanceID string, config map[string]interface{}) error {
+	f.calls = append(f.calls, fmt.Sprintf("config %v", config))
+	return f.configErr
+}
+
+func (f *updateClient) ListPlans(backend string) ([]client.Plan, error) {
+	return []client.Plan{{Name: "rabbit-1", Backend: client.BackendRabbitMQ}}, nil
+}
+
+func (f *updateClient) GetPlan(name string) (*client.Plan, error) {
+	if f.planErr != nil {
+		return nil, f.planErr
+	}
+	for _, plan := range f.plans {
+		if plan.Name == name {
+			return &plan, nil
+		}
+	}
+	return nil, &client.APIError{StatusCode: http.StatusNotFound, Message: "plan not found"}
+}
+
+func writeUpdateConfigFile(t *testing.T) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("rabbit.heartbeat: 60\n"), 0o600))
+	return file
+}
+
+func TestInstanceUpdateCmd_ConfigFileAfterPlan(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	fake := &updateClient{sequenceClient: sequenceClient{instances: []*client.Instance{
+		{ID: 1234, Plan: "rabbit-1", Ready: true},
+		{ID: 1234, Plan: "rabbit-1", Ready: true},
+		{ID: 1234, Plan: "rabbit-1", Ready: true},
+		{ID: 1234, Plan: "rabbit-2", Ready: true},
+	}}}
+	useFakeClient(t, fake)
+
+	cmd := instanceUpdateCmd
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("plan", "rabbit-2")
+	cmd.Flags().Set("config-file", writeUpdateConfigFile(t))
+	defer resetFlags(cmd)
+
+	require.NoError(t, cmd.RunE(cmd, []string{}))
+	assert.Equal(t, []string{"update rabbit-2", "healthy", "config map[rabbit.heartbeat:60]"}, fake.calls)
+}
+
+func TestInstanceUpdateCmd_ConfigFileFailureNamesPhase(t *testing.T) {
+	fake := &updateClient{
+		sequenceClient: sequenceClient{instances: []*client.Instance{{ID: 1234, Name: "orders", Ready: true}}},
+		configErr:      fmt.Errorf("API error (400): invalid value"),
+	}
+	useFakeClient(t, fake)
+
+	cmd := instanceUpdateCmd
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("name", "orders-2")
+	cmd.Flags().Set("config-file", writeUpdateConfigFile(t))
+	defer resetFlags(cmd)
+
+	err := cmd.RunE(cmd, []string{})
+	assert.EqualError(t, err, "instance was updated, but the config update failed: API error (400): invalid value")
+}
+
+func TestInstanceUpdateCmd_InvalidConfigFileChangesNothing(t *testing.T) {
+	fake := &updateClient{sequenceClient: sequenceClient{instances: []*client.Instance{{ID: 1234, Plan: "rabbit-1", Ready: true}}}}
+	useFakeClient(t, fake)
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("rabbit.heartbeat: often\n"), 0o600))
+
+	cmd := instanceUpdateCmd
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("plan", "rabbit-2")
+	cmd.Flags().Set("config-file", file)
+	defer resetFlags(cmd)
+
+	err := cmd.RunE(cmd, []string{})
+	assert.ErrorContains(t, err, "1 of 1 settings are invalid")
+	assert.Empty(t, fake.calls)
+}
+
+func TestInstanceUpdateCmd_UnsafeConfigFile(t *testing.T) {
+	fake := &updateClient{sequenceClient: sequenceClient{instances: []*client.Instance{{ID: 1234, Name: "orders", Ready: true}}}}
+	useFakeClient(t, fake)
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("rabbit.consumer_timeout: 1000\n"), 0o600))
+
+	cmd := instanceUpdateCmd
+	cmd.Flags().Set("id", "1234")
+	cmd.Flags().Set("config-file", file)
+	defer resetFlags(cmd)
+
+	err := cmd.RunE(cmd, []string{})
+	assert.ErrorContains(t, err, "below the safe minimum")
+	assert.ErrorContains(t, err, "Use --force to apply it anyway")
+	assert.Empty(t, fake.calls)
+
+	cmd.Flags().Set("force", "true")
+	require.NoError(t, cmd.RunE(cmd, []string{}))
+	assert.Equal(t, []string{"config map[rabbit.consumer_timeout:1000]"}, fake.calls)
+}
+
+func TestInstanceUpdateCmd_PlanPreview(t *testing.T) {
+	fake := &updateClient{
+		sequenceClient: sequenceClient{instances: []*client.Instance{{ID: 1234, Plan: "bunny-1", Ready: true}}},
+		plans: []client.Plan{
+			{Name: "bunny-1", Price: 99, Backend: client.BackendRabbitMQ},
+			{Name: "rabbit-3", Price: 897, Backend: client.BackendRabbitMQ, Nodes: 3, MemoryGB: 8},
+		},
+	}
+	useFakeClient(t, fake)
+
+	cmd := instanceUpdateCmd
+	defer resetFlags(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--id", "1234", "--plan", "rabbit-3"}))
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	cmd.SetIn(&bytes.Buffer{})
+	defer cmd.SetErr(nil)
+	defer cmd.SetIn(nil)
+
+	err := cmd.RunE(cmd, []string{})
+	assert.ErrorContains(t, err, "Use --yes to proceed non-interactively")
+	assert.Empty(t, fake.calls, "nothing may change without confirmation")
+	assert.Regexp(t, `PRICE\s+\$99\.00\s+\$897\.00`, stderr.String())
+	assert.Regexp(t, `NODES\s+-\s+3`, stderr.String(), "node counts are not guessed from the plan name")
+	assert.Regexp(t, `MEMORY\s+-\s+8 GB`, stderr.String())
+	assert.NotContains(t, stderr.String(), "DISK", "specs neither plan has are left out")
+
+	require.NoError(t, cmd.Flags().Set("yes", "true"))
+	require.NoError(t, cmd.RunE(cmd, []string{}))
+	assert.Equal(t, []string{"update rabbit-3"}, fake.calls)
+}
+
+func TestInstanceUpdateCmd_PlanPreviewErrors(t *testing.T) {
+	run := func(t *testing.T, fake *updateClient) error {
+		t.Helper()
+		useFakeClient(t, fake)
+		cmd := instanceUpdateCmd
+		defer resetFlags(cmd)
+		require.NoError(t, cmd.ParseFlags([]string{"--id", "1234", "--plan", "rabbit-3"}))
+		cmd.SetErr(&bytes.Buffer{})
+		cmd.SetIn(&bytes.Buffer{})
+		defer cmd.SetErr(nil)
+		defer cmd.SetIn(nil)
+		return cmd.RunE(cmd, []string{})
+	}
+	instances := []*client.Instance{{ID: 1234, Plan: "bunny-1", Ready: true}}
+
+	t.Run("unknown plan skips the preview", func(t *testing.T) {
+		fake := &updateClient{sequenceClient: sequenceClient{instances: instances}}
+		require.NoError(t, run(t, fake))
+		assert.Equal(t, []string{"update rabbit-3"}, fake.calls)
+	})
+
+	t.Run("other errors fail", func(t *testing.T) {
+		fake := &updateClient{
+			sequenceClient: sequenceClient{instances: instances},
+			planErr:        &client.APIError{StatusCode: http.StatusServiceUnavailable, Message: "unavailable"},
+		}
+		assert.ErrorContains(t, run(t, fake), "failed to get plan bunny-1")
+		assert.Empty(t, fake.calls)
+	})
+}
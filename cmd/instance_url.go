@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var urlProtocols = []string{client.ProtocolAMQP, client.ProtocolAMQPS, client.ProtocolHTTPS, client.ProtocolManagement}
+
+var instanceURLCmd = &cobra.Command{
+	Use:   "url --id <id>",
+	Short: "Print the connection URL of an instance",
+	Long: `Print a connection URL of an instance, with credentials, for use in
+application configuration.
+
+--protocol selects the URL:
+  amqps:      AMQP over TLS (default)
+  amqp:       plain AMQP
+  https:      the HTTP API, with credentials
+  management: the management interface, which asks for credentials itself
+
+Use --redact to mask the password. With -o json or -o yaml all URLs are
+printed, keyed by protocol.`,
+	Example: `  cloudamqp instance url --id 1234
+  cloudamqp instance url --id 1234 --protocol https --redact
+  cloudamqp instance url --id 1234 -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, _ := cmd.Flags().GetString("id")
+		protocol, _ := cmd.Flags().GetString("protocol")
+		redact, _ := cmd.Flags().GetBool("redact")
+
+		if !slices.Contains(urlProtocols, protocol) {
+			return fmt.Errorf("invalid --protocol %q: must be one of %s", protocol, strings.Join(urlProtocols, ", "))
+		}
+
+		var err error
+		apiKey, err = getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		instanceID, err := resolveInstance(c, idFlag)
+		if err != nil {
+			return err
+		}
+
+		urls, err := c.GetInstanceURLs(instanceID)
+		if err != nil {
+			return fmt.Errorf("failed to get instance URLs: %w", err)
+		}
+
+		if redact {
+			for p, u := range urls {
+				urls[p] = maskPassword(u)
+			}
+		}
+
+		if !textOutput(cmd) {
+			p, err := newPrinter(cmd, nil, false)
+			if err != nil {
+				return err
+			}
+			p.PrintValue(urls)
+			return p.Err()
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), urls[protocol])
+		return nil
+	},
+}
+
+func init() {
+	instanceURLCmd.Flags().String("id", "", "Instance ID or name (required)")
+	instanceURLCmd.MarkFlagRequired("id")
+	instanceURLCmd.Flags().String("protocol", client.ProtocolAMQPS, "URL to print: amqp, amqps, https or management")
+	instanceURLCmd.Flags().Bool("redact", false, "Mask the password in the URL")
+	instanceURLCmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+	instanceURLCmd.RegisterFlagCompletionFunc("protocol", cobra.FixedCompletions(urlProtocols, cobra.ShellCompDirectiveNoFileComp))
+}
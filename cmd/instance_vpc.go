@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var instanceVPCCmd = &cobra.Command{
+	Use:   "vpc",
+	Short: "Show instance VPC information",
+	Long:  `Show the VPC an instance runs in. Use the top-level 'vpc' command to manage VPCs and peering.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Help()
+		cmd.SilenceUsage = true
+		return fmt.Errorf("subcommand required")
+	},
+}
+
+var instanceVPCInfoCmd = &cobra.Command{
+	Use:     "info --id <instance_id>",
+	Short:   "Show the instance's VPC",
+	Long:    `Retrieves the VPC details of a dedicated instance, including the security group to use when peering.`,
+	Example: `  cloudamqp instance vpc info --id 1234`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idFlag, err := instanceIDFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err := getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := client.New(apiKey, Version)
+
+		info, err := c.GetInstanceVPCInfo(idFlag)
+		if err != nil {
+			return fmt.Errorf("failed to get VPC info: %w", err)
+		}
+
+		p, err := getPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		p.PrintRecord(
+			[]string{"VPC_ID", "NAME", "SUBNET", "OWNER_ID", "SECURITY_GROUP_ID"},
+			[]string{info.ID, info.Name, info.Subnet, info.OwnerID, info.SecurityGroupID},
+		)
+
+		return nil
+	},
+}
+
+func init() {
+	instanceVPCInfoCmd.Flags().StringP("id", "", "", "Instance ID or name (required)")
+	instanceVPCInfoCmd.MarkFlagRequired("id")
+	instanceVPCInfoCmd.RegisterFlagCompletionFunc("id", completeInstanceIDFlag)
+
+	instanceVPCCmd.AddCommand(instanceVPCInfoCmd)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// errInterrupted is returned by long operations stopped with Ctrl-C or
+// SIGTERM before they finished.
+var errInterrupted = errors.New("interrupted")
+
+// exitNow exits the process, replaced in tests.
+var exitNow = os.Exit
+
+// interruptContext returns a context that is cancelled on the first SIGINT
+// or SIGTERM, so long operations can stop starting new work and report what
+// was left undone. A second signal exits immediately. stop releases the
+// signal handler.
+func interruptContext(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-signals:
+		case <-done:
+			return
+		}
+		logWarn("Interrupted, stopping. Press Ctrl-C again to exit immediately.")
+		cancel()
+
+		select {
+		case <-signals:
+			exitNow(exitInterrupted)
+		case <-done:
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		close(done)
+		cancel()
+	}
+}
+
+// commandContext returns the context of cmd, which Execute cancels on
+// Ctrl-C. Commands run directly, as in tests, get a background context.
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterruptContext(t *testing.T) {
+	exited := make(chan int, 1)
+	orig := exitNow
+	exitNow = func(code int) { exited <- code }
+	defer func() { exitNow = orig }()
+
+	ctx, stop := interruptContext(context.Background())
+	defer stop()
+
+	self, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+
+	require.NoError(t, self.Signal(os.Interrupt))
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the first Ctrl-C should cancel the context")
+	}
+	assert.Empty(t, exited, "the first Ctrl-C must not exit")
+
+	require.NoError(t, self.Signal(os.Interrupt))
+	select {
+	case code := <-exited:
+		assert.Equal(t, exitInterrupted, code)
+	case <-time.After(5 * time.Second):
+		t.Fatal("a second Ctrl-C should exit immediately")
+	}
+}
+
+func TestWaitForInstanceReady_Interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &sequenceClient{instances: []*client.Instance{{ID: 1}}}
+	err := waitForInstanceReady(ctx, c, 1, time.Minute)
+	assert.ErrorIs(t, err, errInterrupted)
+	assert.Equal(t, exitInterrupted, ExitCode(err))
+}
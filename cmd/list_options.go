@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// addListFlags registers --limit and --page-size on a list command.
+func addListFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("limit", 0, "Maximum number of results to return (0 for all)")
+	cmd.Flags().Int("page-size", 0, "Number of results to fetch per API request (0 for the API default)")
+}
+
+// getListOptions returns the paging options given with --limit and --page-size.
+func getListOptions(cmd *cobra.Command) (client.ListOptions, error) {
+	limit, _ := cmd.Flags().GetInt("limit")
+	pageSize, _ := cmd.Flags().GetInt("page-size")
+	if limit < 0 {
+		return client.ListOptions{}, fmt.Errorf("--limit must not be negative")
+	}
+	if pageSize < 0 {
+		return client.ListOptions{}, fmt.Errorf("--page-size must not be negative")
+	}
+	return client.ListOptions{Limit: limit, PageSize: pageSize}, nil
+}
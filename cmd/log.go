@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// Log formats accepted by --log-format.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// logger receives all diagnostic output: progress, status messages,
+// warnings and errors. It writes to stderr so stdout only carries command
+// results.
+var logger = slog.New(newPlainHandler(os.Stderr, slog.LevelInfo))
+
+// logOutput is where logger writes, and so where the progress spinner is
+// drawn.
+var logOutput io.Writer = os.Stderr
+
+// logFormat and logLevel are the --log-format and --quiet settings of
+// logger, kept for the handlers printStatus creates.
+var (
+	logFormat = logFormatText
+	logLevel  = slog.LevelInfo
+)
+
+// configureLogging sets up logger to write to w in the --log-format format.
+// With --quiet, progress and status messages are left out and only warnings
+// and errors are logged.
+func configureLogging(w io.Writer, format string, quiet bool) error {
+	if format != logFormatText && format != logFormatJSON {
+		return fmt.Errorf("invalid --log-format %q: must be text or json", format)
+	}
+	logFormat = format
+	logLevel = slog.LevelInfo
+	if quiet {
+		logLevel = slog.LevelWarn
+	}
+	logger = slog.New(newLogHandler(w))
+	logOutput = w
+	return nil
+}
+
+// newLogHandler returns a handler writing to w with the configured format
+// and level.
+func newLogHandler(w io.Writer) slog.Handler {
+	if logFormat == logFormatJSON {
+		return slog.NewJSONHandler(w, &slog.HandlerOptions{Level: logLevel})
+	}
+	return newPlainHandler(w, logLevel)
+}
+
+// structuredLogs reports whether diagnostics are logged as JSON, in which
+// case interactive output such as the spinner is turned off.
+func structuredLogs() bool {
+	return logFormat == logFormatJSON
+}
+
+func logInfo(format string, args ...any) {
+	logger.Info(fmt.Sprintf(format, args...))
+}
+
+func logWarn(format string, args ...any) {
+	logger.Warn(fmt.Sprintf(format, args...))
+}
+
+func logError(format string, args ...any) {
+	logger.Error(fmt.Sprintf(format, args...))
+}
+
+// plainHandler is the human-readable slog handler: one line per record with
+// just the message, warnings prefixed with "Warning: ".
+type plainHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Level
+}
+
+func newPlainHandler(w io.Writer, level slog.Level) *plainHandler {
+	return &plainHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+func (h *plainHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *plainHandler) Handle(_ context.Context, r slog.Record) error {
+	msg := r.Message
+	if r.Level == slog.LevelWarn {
+		msg = "Warning: " + msg
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintln(h.w, msg)
+	return err
+}
+
+func (h *plainHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *plainHandler) WithGroup(string) slog.Handler { return h }
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureLogging(t *testing.T) {
+	defer configureLogging(os.Stderr, logFormatText, false)
+
+	var buf bytes.Buffer
+	require.NoError(t, configureLogging(&buf, logFormatText, false))
+	logInfo("Waiting for instance %d to be ready...", 1234)
+	logWarn("plan %s is a downgrade", "lemur")
+	assert.Equal(t, "Waiting for instance 1234 to be ready...\nWarning: plan lemur is a downgrade\n", buf.String())
+	assert.False(t, structuredLogs())
+
+	buf.Reset()
+	require.NoError(t, configureLogging(&buf, logFormatJSON, false))
+	logError("Error listing nodes: %s", "timeout")
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "Error listing nodes: timeout", record["msg"])
+	assert.Contains(t, record, "time")
+	assert.True(t, structuredLogs())
+
+	assert.ErrorContains(t, configureLogging(&buf, "xml", false), "invalid --log-format")
+}
+
+func TestConfigureLogging_Quiet(t *testing.T) {
+	defer configureLogging(os.Stderr, logFormatText, false)
+
+	var buf bytes.Buffer
+	require.NoError(t, configureLogging(&buf, logFormatText, true))
+	logInfo("Waiting for instance %d to be ready...", 1234)
+	logWarn("plan %s is a downgrade", "lemur")
+	assert.Equal(t, "Warning: plan lemur is a downgrade\n", buf.String())
+}
+
+func TestPrintStatus_LogFormat(t *testing.T) {
+	defer configureLogging(os.Stderr, logFormatText, false)
+	cmd := &cobra.Command{}
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+
+	printStatus(cmd, "Instance %d deleted.", 1234)
+	assert.Equal(t, "Instance 1234 deleted.\n", stderr.String())
+
+	stderr.Reset()
+	require.NoError(t, configureLogging(os.Stderr, logFormatJSON, false))
+	printStatus(cmd, "Instance %d deleted.", 1234)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(stderr.Bytes(), &record))
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "Instance 1234 deleted.", record["msg"])
+
+	stderr.Reset()
+	require.NoError(t, configureLogging(os.Stderr, logFormatText, true))
+	printStatus(cmd, "Instance %d deleted.", 1234)
+	assert.Empty(t, stderr.String(), "--quiet leaves status messages out")
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// outputFile is the temporary file command results are written to while
+// a command with --output-file runs, and outputPath where it ends up.
+var (
+	outputFile *os.File
+	outputPath string
+)
+
+// openOutputFile redirects the command's results to a temporary file next
+// to --output-file. Status messages keep going to stderr.
+func openOutputFile(cmd *cobra.Command) error {
+	path, _ := cmd.Flags().GetString("output-file")
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create --output-file %s: %w", path, err)
+	}
+	outputFile, outputPath = f, path
+	cmd.Root().SetOut(f)
+	return nil
+}
+
+// finishOutputFile moves the results into place once the command has run.
+// When the command failed the partial file is removed instead, leaving any
+// existing file at the path untouched.
+func finishOutputFile(runErr error) error {
+	if outputFile == nil {
+		return runErr
+	}
+	f := outputFile
+	outputFile = nil
+	rootCmd.SetOut(nil)
+	defer os.Remove(f.Name())
+
+	closeErr := f.Close()
+	if runErr != nil {
+		return runErr
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to write --output-file %s: %w", outputPath, closeErr)
+	}
+	if err := os.Rename(f.Name(), outputPath); err != nil {
+		return fmt.Errorf("failed to write --output-file %s: %w", outputPath, err)
+	}
+	return nil
+}
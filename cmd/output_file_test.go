@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.yaml")
+	instanceExportCmd.InheritedFlags() // merge --output-file from root, as Execute would
+	rootCmd.PersistentFlags().Set("output-file", path)
+	defer rootCmd.PersistentFlags().Set("output-file", "")
+
+	require.NoError(t, openOutputFile(instanceExportCmd))
+	fmt.Fprint(instanceExportCmd.OutOrStdout(), "name: orders\n")
+	printStatus(instanceExportCmd, "status goes to stderr")
+	require.NoError(t, finishOutputFile(nil))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "name: orders\n", string(data))
+	assert.Equal(t, os.Stdout, instanceExportCmd.OutOrStdout())
+}
+
+func TestOutputFile_RemovedOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "backup.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("previous\n"), 0600))
+	instanceExportCmd.InheritedFlags() // merge --output-file from root, as Execute would
+	rootCmd.PersistentFlags().Set("output-file", path)
+	defer rootCmd.PersistentFlags().Set("output-file", "")
+
+	require.NoError(t, openOutputFile(instanceExportCmd))
+	fmt.Fprint(instanceExportCmd.OutOrStdout(), "name: ord")
+	assert.EqualError(t, finishOutputFile(errors.New("boom")), "boom")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "previous\n", string(data))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestOutputFile_CannotCreate(t *testing.T) {
+	instanceExportCmd.InheritedFlags() // merge --output-file from root, as Execute would
+	rootCmd.PersistentFlags().Set("output-file", filepath.Join(t.TempDir(), "missing", "backup.yaml"))
+	defer rootCmd.PersistentFlags().Set("output-file", "")
+
+	assert.ErrorContains(t, openOutputFile(instanceExportCmd), "failed to create --output-file")
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var pingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Check that the API is reachable and the API key works",
+	Long: `Makes a single lightweight authenticated request to the API, listing at
+most one instance, and prints OK with the API URL and the round-trip time.
+
+Exits non-zero with an explanation when the API key is rejected, the API
+host can't be resolved or reached, or the API returns an error. Useful as a
+preflight step in CI before running other commands.`,
+	Example: `  cloudamqp ping
+  cloudamqp ping -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		apiKey, err = getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+		apiURL := client.APIURL()
+
+		start := time.Now()
+		if err := pingAPI(c); err != nil {
+			cmd.SilenceUsage = true
+			return pingError(apiURL, err)
+		}
+		latency := time.Since(start)
+
+		p, err := getPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		p.PrintRecord(
+			[]string{"STATUS", "API_URL", "LATENCY"},
+			[]string{"OK", apiURL, latency.Round(time.Millisecond).String()},
+		)
+		return nil
+	},
+}
+
+// pingAPI makes the cheapest authenticated request of an endpoint every
+// account has: the first page of the instance list, one instance long.
+func pingAPI(c client.ClientAPI) error {
+	_, err := c.ListInstancesWithOptions(client.ListOptions{Limit: 1, PageSize: 1})
+	return err
+}
+
+// pingError explains why ping failed: a rejected key, DNS, the network or
+// an API error.
+func pingError(apiURL string, err error) error {
+	host := apiURL
+	if u, parseErr := url.Parse(apiURL); parseErr == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	var apiErr *client.APIError
+	var dnsErr *net.DNSError
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		return fmt.Errorf("authentication failed: the API key was rejected by %s (HTTP %d)", apiURL, apiErr.StatusCode)
+	case errors.As(err, &apiErr):
+		return fmt.Errorf("%s is reachable but returned an error: %w", apiURL, err)
+	case errors.As(err, &dnsErr):
+		return fmt.Errorf("DNS lookup of %s failed: %v. Check the host name and your network", host, dnsErr)
+	case errors.As(err, &urlErr) && urlErr.Timeout():
+		return fmt.Errorf("timed out connecting to %s: %v", host, urlErr.Err)
+	case errors.As(err, &urlErr):
+		return fmt.Errorf("cannot reach %s: %v", host, urlErr.Err)
+	}
+	return fmt.Errorf("ping %s failed: %w", apiURL, err)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pingClient records the options of instance list calls.
+type pingClient struct {
+	fakeClient
+	opts []client.ListOptions
+}
+
+func (f *pingClient) ListInstancesWithOptions(opts client.ListOptions) ([]client.Instance, error) {
+	f.opts = append(f.opts, opts)
+	return nil, nil
+}
+
+func TestPingCmd(t *testing.T) {
+	t.Setenv("CLOUDAMQP_URL", "https://api.example.com/api")
+	fake := &pingClient{}
+	useFakeClient(t, fake)
+
+	cmd := pingCmd
+	cmd.InheritedFlags() // merge --output from root, as Execute would
+
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+
+	assert.Contains(t, out, "STATUS = OK")
+	assert.Contains(t, out, "API_URL = https://api.example.com/api")
+	assert.Equal(t, []client.ListOptions{{Limit: 1, PageSize: 1}}, fake.opts, "one request for at most one instance")
+}
+
+func TestPingError(t *testing.T) {
+	apiURL := "https://api.example.com/api"
+
+	err := pingError(apiURL, &client.APIError{StatusCode: 401, Message: "unauthorized"})
+	assert.EqualError(t, err, "authentication failed: the API key was rejected by https://api.example.com/api (HTTP 401)")
+
+	err = pingError(apiURL, &client.APIError{StatusCode: 503, Message: "maintenance"})
+	assert.EqualError(t, err, "https://api.example.com/api is reachable but returned an error: API error (503): maintenance")
+
+	dnsErr := &url.Error{Op: "Get", URL: apiURL, Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "api.example.com", IsNotFound: true}}}
+	err = pingError(apiURL, fmt.Errorf("request failed: %w", dnsErr))
+	assert.ErrorContains(t, err, "DNS lookup of api.example.com failed")
+
+	refused := &url.Error{Op: "Get", URL: apiURL, Err: errors.New("connection refused")}
+	err = pingError(apiURL, refused)
+	assert.EqualError(t, err, "cannot reach api.example.com: connection refused")
+}
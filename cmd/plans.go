@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"cloudamqp-cli/client"
 	"github.com/spf13/cobra"
@@ -9,6 +11,69 @@ import (
 
 var backendFilter string
 
+// planFamilies lists the plan families of each backend from smallest to
+// largest node size. Shared plans come first.
+var planFamilies = [][]string{
+	{"lemur", "tiger", "squirrel", "bunny", "hare", "rabbit", "panda", "ape", "hippo", "lion", "rhino"},
+	{"lemming", "ermine", "puffin", "penguin", "fox", "lynx", "leopard", "wolverine", "reindeer", "bear", "orca"},
+}
+
+// sharedPlans are the plans that run on shared servers.
+var sharedPlans = map[string]bool{"lemur": true, "tiger": true, "lemming": true, "ermine": true}
+
+// planRank orders plans by size, so that comparing the ranks of two plans
+// tells an upgrade from a downgrade. Node size weighs more than node count.
+// Unknown plans rank -1.
+func planRank(plan string) int {
+	family, nodes := plan, 0
+	if i := strings.LastIndex(plan, "-"); i > 0 {
+		n, err := strconv.Atoi(plan[i+1:])
+		if err != nil {
+			return -1
+		}
+		family, nodes = plan[:i], n
+	}
+
+	for _, families := range planFamilies {
+		for tier, f := range families {
+			if f == family {
+				return tier*10 + nodes
+			}
+		}
+	}
+	return -1
+}
+
+// validatePlan checks plan against the available plans and suggests the
+// closest match when it is unknown.
+func validatePlan(plan string, plans []client.Plan) error {
+	names := make([]string, 0, len(plans))
+	for _, p := range plans {
+		if p.Backend != "rabbitmq" && p.Backend != "lavinmq" {
+			continue
+		}
+		if p.Name == plan {
+			return nil
+		}
+		names = append(names, p.Name)
+	}
+
+	if suggestion := suggestClosest(plan, names); suggestion != "" {
+		return fmt.Errorf("unknown plan '%s'; did you mean '%s'?", plan, suggestion)
+	}
+	return fmt.Errorf("unknown plan '%s'. Run 'cloudamqp plans' to list available plans", plan)
+}
+
+// isPlanDowngrade reports whether moving from one plan to another is a
+// downgrade. Plans with unknown rank are never considered downgrades.
+func isPlanDowngrade(from, to string) bool {
+	fromRank, toRank := planRank(from), planRank(to)
+	if fromRank < 0 || toRank < 0 {
+		return false
+	}
+	return toRank < fromRank
+}
+
 var plansCmd = &cobra.Command{
 	Use:   "plans",
 	Short: "List available plans",
@@ -26,16 +91,15 @@ var plansCmd = &cobra.Command{
 
 		plans, err := c.ListPlans(backendFilter)
 		if err != nil {
-			fmt.Printf("Error listing plans: %v\n", err)
-			return err
+			return fmt.Errorf("failed to list plans: %w", err)
 		}
 
 		if len(plans) == 0 {
-			fmt.Println("No plans found.")
+			printStatus(cmd, "No plans found.")
 			return nil
 		}
 
-		p, err := getPrinter(cmd)
+		p, err := getListPrinter(cmd)
 		if err != nil {
 			return err
 		}
@@ -47,11 +111,7 @@ var plansCmd = &cobra.Command{
 			if plan.Shared {
 				shared = "Yes"
 			}
-			price := fmt.Sprintf("$%.2f", plan.Price)
-			if plan.Price == 0 {
-				price = "Free"
-			}
-			rows[i] = []string{plan.Name, price, plan.Backend, shared}
+			rows[i] = []string{plan.Name, formatPlanPrice(plan.Price), plan.Backend, shared}
 		}
 		p.PrintRecords(headers, rows)
 
@@ -59,6 +119,14 @@ var plansCmd = &cobra.Command{
 	},
 }
 
+// formatPlanPrice formats a monthly plan price, with free plans as Free.
+func formatPlanPrice(price float64) string {
+	if price == 0 {
+		return "Free"
+	}
+	return fmt.Sprintf("$%.2f", price)
+}
+
 func init() {
 	plansCmd.Flags().StringVar(&backendFilter, "backend", "", "Filter by specific backend software")
 }
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloudamqp-cli/client"
+)
+
+// defaultIPResolver answers a GET with the caller's public IP as plain text.
+const defaultIPResolver = "https://api.ipify.org"
+
+// lookupPublicIP asks resolver for the public IP of this machine. Tests
+// replace it to avoid the network.
+var lookupPublicIP = func(resolver string) (net.IP, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if client.ProxyURL != nil {
+		transport.Proxy = http.ProxyURL(client.ProxyURL)
+	}
+	httpClient := &http.Client{Transport: transport, Timeout: 10 * time.Second}
+
+	req, err := http.NewRequest("GET", resolver, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP resolver %q: %w", resolver, err)
+	}
+	req.Header.Set("User-Agent", "cloudamqp-cli/"+Version)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up public IP: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up public IP: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to look up public IP: %s returned %s", resolver, resp.Status)
+	}
+	ip := net.ParseIP(strings.TrimSpace(string(body)))
+	if ip == nil {
+		return nil, fmt.Errorf("failed to look up public IP: %s did not return an IP address", resolver)
+	}
+	return ip, nil
+}
+
+// hostCIDR returns the single-address CIDR of ip: /32 for IPv4, /128 for
+// IPv6.
+func hostCIDR(ip net.IP) string {
+	if ip.To4() != nil {
+		return ip.To4().String() + "/32"
+	}
+	return ip.String() + "/128"
+}
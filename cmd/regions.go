@@ -26,16 +26,15 @@ var regionsCmd = &cobra.Command{
 
 		regions, err := c.ListRegions(providerFilter)
 		if err != nil {
-			fmt.Printf("Error listing regions: %v\n", err)
-			return err
+			return fmt.Errorf("failed to list regions: %w", err)
 		}
 
 		if len(regions) == 0 {
-			fmt.Println("No regions found.")
+			printStatus(cmd, "No regions found.")
 			return nil
 		}
 
-		p, err := getPrinter(cmd)
+		p, err := getListPrinter(cmd)
 		if err != nil {
 			return err
 		}
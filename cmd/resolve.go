@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+// resolveInstance returns the ID of the instance given by idOrName, which is
+// either a numeric instance ID or an exact instance name. Names are looked up
+// with ListInstances and must match exactly one instance.
+//
+// Name resolution never reads the completion cache: a cached list can be a
+// minute old, and a renamed or recreated instance would then resolve to the
+// wrong ID. Only completion suggestions come from the cache.
+func resolveInstance(c client.ClientAPI, idOrName string) (int, error) {
+	idOrName = strings.TrimSpace(idOrName)
+	if idOrName == "" {
+		return 0, fmt.Errorf("instance ID is required. Use --id flag")
+	}
+	if id, err := strconv.Atoi(idOrName); err == nil {
+		return id, nil
+	}
+	return findInstanceByName(c, idOrName)
+}
+
+// flagInstanceID returns the instance ID given to --id. A numeric ID is
+// returned as is, without an API call, so --dry-run still works offline; a
+// name is resolved like resolveInstance.
+func flagInstanceID(cmd *cobra.Command) (int, error) {
+	idOrName, _ := cmd.Flags().GetString("id")
+	idOrName = strings.TrimSpace(idOrName)
+	if idOrName == "" {
+		return 0, fmt.Errorf("instance ID is required. Use --id flag")
+	}
+	if id, err := strconv.Atoi(idOrName); err == nil {
+		return id, nil
+	}
+
+	key, err := getAPIKey()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get API key: %w", err)
+	}
+	return findInstanceByName(newAPIClient(key), idOrName)
+}
+
+// instanceIDFlag is flagInstanceID for commands that pass the instance ID
+// on to the client as a string.
+func instanceIDFlag(cmd *cobra.Command) (string, error) {
+	id, err := flagInstanceID(cmd)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(id), nil
+}
+
+// getInstance is c.GetInstance for callers that need the instance: the nil
+// instance GetInstance returns for an empty response is an error.
+func getInstance(c client.ClientAPI, id int) (*client.Instance, error) {
+	instance, err := c.GetInstance(id)
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("the API returned no data for instance %d", id)
+	}
+	return instance, nil
+}
+
+// findInstanceByName returns the ID of the only instance named name. When
+// several instances share the name, the error lists them so the user can
+// pick one by ID.
+func findInstanceByName(c client.ClientAPI, name string) (int, error) {
+	instances, err := c.ListInstances()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list instances: %w", err)
+	}
+	return matchInstanceName(instances, name)
+}
+
+// matchInstanceName is findInstanceByName on an already fetched list.
+func matchInstanceName(instances []client.Instance, name string) (int, error) {
+	var matches []client.Instance
+	names := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Name == name {
+			matches = append(matches, instance)
+		}
+		names = append(names, instance.Name)
+	}
+
+	switch len(matches) {
+	case 0:
+		if suggestion := suggestClosest(name, names); suggestion != "" {
+			return 0, fmt.Errorf("no instance named %q; did you mean %q?", name, suggestion)
+		}
+		return 0, fmt.Errorf("no instance named %q", name)
+	case 1:
+		return matches[0].ID, nil
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d instances are named %q, use the numeric ID instead:", len(matches), name)
+	for _, instance := range matches {
+		fmt.Fprintf(&b, "\n  %d\t%s\t%s", instance.ID, instance.Plan, instance.Region)
+	}
+	return 0, fmt.Errorf("%s", b.String())
+}
+
+// findInstancesByTags returns the instances that have all of tags, sorted by
+// ID. It is an error if none match.
+func findInstancesByTags(c client.ClientAPI, tags []string) ([]client.Instance, error) {
+	instances, err := c.ListInstances()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	matches := instancesWithTags(instances, tags)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no instances tagged %s", strings.Join(tags, ", "))
+	}
+	return matches, nil
+}
+
+// instancesWithTags filters instances down to those that have every tag in
+// tags, sorted by ID.
+func instancesWithTags(instances []client.Instance, tags []string) []client.Instance {
+	tags = parseTags(tags)
+
+	var matches []client.Instance
+	for _, instance := range instances {
+		has := map[string]bool{}
+		for _, tag := range instance.Tags {
+			has[tag] = true
+		}
+		all := true
+		for _, tag := range tags {
+			if !has[tag] {
+				all = false
+				break
+			}
+		}
+		if all {
+			matches = append(matches, instance)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	return matches
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"testing"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveInstance(t *testing.T) {
+	c := &fakeClient{instances: map[int]*client.Instance{
+		1234: {ID: 1234, Name: "orders", Plan: "bunny-1", Region: "amazon-web-services::us-east-1"},
+		5678: {ID: 5678, Name: "billing", Plan: "lemur", Region: "amazon-web-services::eu-west-1"},
+		9012: {ID: 9012, Name: "billing", Plan: "hare-1", Region: "amazon-web-services::us-east-1"},
+	}}
+
+	t.Run("numeric ID", func(t *testing.T) {
+		id, err := resolveInstance(c, "42")
+		require.NoError(t, err)
+		assert.Equal(t, 42, id)
+	})
+
+	t.Run("unique name", func(t *testing.T) {
+		id, err := resolveInstance(c, "orders")
+		require.NoError(t, err)
+		assert.Equal(t, 1234, id)
+	})
+
+	t.Run("ambiguous name", func(t *testing.T) {
+		_, err := resolveInstance(c, "billing")
+		require.Error(t, err)
+		assert.Equal(t, "2 instances are named \"billing\", use the numeric ID instead:\n"+
+			"  5678\tlemur\tamazon-web-services::eu-west-1\n"+
+			"  9012\thare-1\tamazon-web-services::us-east-1", err.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := resolveInstance(c, "order")
+		assert.EqualError(t, err, `no instance named "order"; did you mean "orders"?`)
+
+		_, err = resolveInstance(c, "something-else-entirely")
+		assert.EqualError(t, err, `no instance named "something-else-entirely"`)
+	})
+}
+
+func TestInstanceGetCmd_ByName(t *testing.T) {
+	useFakeClient(t, &fakeClient{instances: map[int]*client.Instance{
+		1234: {ID: 1234, Name: "orders", Plan: "bunny-1"},
+	}})
+
+	cmd := instanceGetCmd
+	cmd.InheritedFlags() // merge --output from root, as Execute would
+	cmd.Flags().Set("id", "orders")
+	defer resetFlags(cmd)
+
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+
+	assert.Contains(t, out, "1234")
+	assert.Contains(t, out, "bunny-1")
+}
+
+func TestFlagInstanceID(t *testing.T) {
+	cmd := instanceRenameCmd
+	defer resetFlags(cmd)
+
+	t.Run("numeric ID needs no API key", func(t *testing.T) {
+		t.Setenv("CLOUDAMQP_APIKEY", "")
+		t.Setenv("HOME", t.TempDir())
+		cmd.Flags().Set("id", " 42 ")
+		id, err := flagInstanceID(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, 42, id)
+	})
+
+	t.Run("name", func(t *testing.T) {
+		useFakeClient(t, &fakeClient{instances: map[int]*client.Instance{
+			1234: {ID: 1234, Name: "orders"},
+		}})
+		cmd.Flags().Set("id", "orders")
+		id, err := instanceIDFlag(cmd)
+		require.NoError(t, err)
+		assert.Equal(t, "1234", id)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		cmd.Flags().Set("id", "")
+		_, err := flagInstanceID(cmd)
+		assert.EqualError(t, err, "instance ID is required. Use --id flag")
+	})
+}
+
+func TestInstanceRenameCmd_ByName(t *testing.T) {
+	useFakeClient(t, &fakeClient{instances: map[int]*client.Instance{
+		1234: {ID: 1234, Name: "orders"},
+	}})
+
+	cmd := instanceRenameCmd
+	defer resetFlags(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--id", "orders", "--name", "orders-prod", "--dry-run"}))
+
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+	assert.Contains(t, out, "PATH = /instances/1234\n")
+}
+
+func TestResolveInstance_IgnoresCompletionCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	useFakeClient(t, &fakeClient{instances: map[int]*client.Instance{
+		1234: {ID: 1234, Name: "orders"},
+	}})
+	// The instance was recreated since the cache was written
+	require.NoError(t, setCachedData("test-key", "instances", instancesCacheTTL, []client.Instance{{ID: 1, Name: "orders"}}))
+
+	id, err := resolveInstance(newAPIClient("test-key"), "orders")
+	require.NoError(t, err)
+	assert.Equal(t, 1234, id)
+}
+
+func TestInstanceGetCmd_RefreshUpdatesCompletionCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	useFakeClient(t, &fakeClient{instances: map[int]*client.Instance{
+		1234: {ID: 1234, Name: "orders", Plan: "bunny-1"},
+	}})
+	require.NoError(t, setCachedData("test-key", "instances", instancesCacheTTL, []client.Instance{{ID: 1, Name: "orders"}}))
+
+	cmd := instanceGetCmd
+	cmd.InheritedFlags() // merge --output from root, as Execute would
+	cmd.Flags().Set("id", "orders")
+	cmd.Flags().Set("refresh", "true")
+	defer resetFlags(cmd)
+
+	out := captureStdout(t, func() {
+		require.NoError(t, cmd.RunE(cmd, []string{}))
+	})
+	assert.Contains(t, out, "ID = 1234")
+
+	data, ok := getCachedData("test-key", "instances", instancesCacheTTL)
+	require.True(t, ok)
+	assert.Contains(t, string(data), `"id":1234`)
+	assert.NotContains(t, string(data), `"id":1,`)
+}
@@ -1,22 +1,218 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
 	"strings"
+	"time"
 
+	"cloudamqp-cli/client"
 	"cloudamqp-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
 func getPrinter(cmd *cobra.Command) (*output.Printer, error) {
-	format, _ := cmd.Flags().GetString("output")
 	fields, _ := cmd.Flags().GetStringSlice("fields")
-	return output.New(os.Stdout, output.Format(format), fields)
+	return newPrinter(cmd, fields, false)
+}
+
+// getListPrinter is getPrinter for commands that print a list of records,
+// which may also be streamed as JSON Lines.
+func getListPrinter(cmd *cobra.Command) (*output.Printer, error) {
+	fields, _ := cmd.Flags().GetStringSlice("fields")
+	return newPrinter(cmd, fields, true)
+}
+
+// isQuiet reports whether --quiet was given. Quiet commands print only what
+// scripts need, e.g. the ID of a created instance.
+func isQuiet(cmd *cobra.Command) bool {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	return quiet
+}
+
+// textOutput reports whether -o is table or wide, in which commands with
+// nested results print them as text. Other formats go through
+// Printer.PrintValue, which prints JSON and YAML and rejects the rest.
+func textOutput(cmd *cobra.Command) bool {
+	if text, _ := cmd.Flags().GetString("template"); text != "" {
+		return false
+	}
+	format, _ := cmd.Flags().GetString("output")
+	switch output.Format(format) {
+	case output.FormatTable, output.FormatWide, "":
+		return true
+	}
+	return false
+}
+
+// documentAsJSON reports whether a document such as an instance spec or a
+// config file is printed as JSON, for -o json, rather than as YAML, for
+// -o yaml and the default table. Formats that don't fit a document are an
+// error.
+func documentAsJSON(cmd *cobra.Command) (bool, error) {
+	if text, _ := cmd.Flags().GetString("template"); text != "" {
+		return false, fmt.Errorf("--template is not supported here: use -o json or -o yaml")
+	}
+	format, _ := cmd.Flags().GetString("output")
+	switch output.Format(format) {
+	case output.FormatJSON:
+		return true, nil
+	case output.FormatYAML, output.FormatTable, "":
+		return false, nil
+	}
+	return false, fmt.Errorf("output format %q is not supported here: use -o json or -o yaml", format)
+}
+
+// printStatus logs a message meant for people, such as a confirmation or
+// "No X found.", to the command's stderr at info level, so it follows
+// --log-format and is left out with --quiet. Stdout is reserved for command
+// results so that output like -o json can be piped safely.
+func printStatus(cmd *cobra.Command, format string, args ...any) {
+	slog.New(newLogHandler(cmd.ErrOrStderr())).Info(fmt.Sprintf(format, args...))
+}
+
+// printers are the printers created while running a command, checked for
+// sort and template errors once it finishes.
+var printers []*output.Printer
+
+func newPrinter(cmd *cobra.Command, fields []string, list bool) (*output.Printer, error) {
+	format, _ := cmd.Flags().GetString("output")
+	if output.Format(format) == output.FormatJSONL && !list {
+		return nil, fmt.Errorf("output format \"jsonl\" is only supported by list commands")
+	}
+
+	// --template on its own implies -o template
+	if text, _ := cmd.Flags().GetString("template"); text != "" || output.Format(format) == output.FormatTemplate {
+		if cmd.Flags().Changed("output") && output.Format(format) != output.FormatTemplate {
+			return nil, fmt.Errorf("--template cannot be combined with -o %s", format)
+		}
+		p, err := output.NewTemplate(cmd.OutOrStdout(), text, fields)
+		if err != nil {
+			return nil, err
+		}
+		return trackPrinter(cmd, p, list), nil
+	}
+
+	p, err := output.New(cmd.OutOrStdout(), output.Format(format), fields)
+	if err != nil {
+		return nil, err
+	}
+	color, err := colorEnabled(cmd, cmd.OutOrStdout())
+	if err != nil {
+		return nil, err
+	}
+	p.SetColor(color)
+	return trackPrinter(cmd, p, list), nil
+}
+
+// trackPrinter applies the global --sort to list printers and records p so
+// printerErr can report its errors. Commands with their own --sort flag sort
+// their data themselves.
+func trackPrinter(cmd *cobra.Command, p *output.Printer, list bool) *output.Printer {
+	ownSort := cmd.LocalNonPersistentFlags().Lookup("sort") != nil
+	if sortBy, _ := cmd.Flags().GetString("sort"); sortBy != "" && list && !ownSort {
+		p.SortBy(sortBy)
+	}
+	printers = append(printers, p)
+	return p
+}
+
+// printerErr returns the first error of the printers used by a command.
+func printerErr() error {
+	for _, p := range printers {
+		if err := p.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 var apiKey string
 
+// newAPIClient creates the client used by commands that depend on
+// client.ClientAPI. Tests replace it to return a fake.
+var newAPIClient = func(apiKey string) client.ClientAPI {
+	return client.New(apiKey, Version)
+}
+
+// configureTransport applies the global connection flags to the client
+// package before any command runs.
+func configureTransport(cmd *cobra.Command, args []string) error {
+	if proxy, _ := cmd.Flags().GetString("proxy"); proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return fmt.Errorf("invalid --proxy %q: expected a URL such as http://proxy.example.com:3128", proxy)
+		}
+		client.ProxyURL = proxyURL
+	}
+
+	if caCert, _ := cmd.Flags().GetString("ca-cert"); caCert != "" {
+		pool, err := client.LoadCACert(caCert)
+		if err != nil {
+			return err
+		}
+		client.RootCAs = pool
+	}
+
+	connectTimeout, err := durationFlag(cmd, "connect-timeout")
+	if err != nil {
+		return err
+	}
+	client.ConnectTimeout = connectTimeout
+
+	requestTimeout, err := durationFlag(cmd, "request-timeout")
+	if err != nil {
+		return err
+	}
+	client.RequestTimeout = requestTimeout
+
+	retryOn, _ := cmd.Flags().GetString("retry-on")
+	policy, err := client.ParseRetryOn(retryOn)
+	if err != nil {
+		return fmt.Errorf("invalid --retry-on: %w", err)
+	}
+	client.RetryOn = policy
+
+	rateLimit, _ := cmd.Flags().GetFloat64("rate-limit")
+	if rateLimit < 0 {
+		return fmt.Errorf("invalid --rate-limit %v: must be zero (unlimited) or more requests per second", rateLimit)
+	}
+	client.SetRateLimit(rateLimit)
+	client.RequestContext = commandContext(cmd)
+
+	// The file stays open until the process exits; writes are unbuffered,
+	// so the transcript is complete even when a command fails
+	if trace, _ := cmd.Flags().GetString("trace"); trace != "" {
+		file, err := os.OpenFile(trace, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open trace file: %w", err)
+		}
+		client.TraceWriter = file
+	}
+
+	if insecure, _ := cmd.Flags().GetBool("insecure"); insecure {
+		logWarn("--insecure disables TLS certificate verification. Your API key can be intercepted; never use this against production.")
+		client.InsecureSkipVerify = true
+	}
+	return nil
+}
+
+// durationFlag parses a duration flag; an empty flag is zero.
+func durationFlag(cmd *cobra.Command, name string) (time.Duration, error) {
+	text, _ := cmd.Flags().GetString(name)
+	if text == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid --%s %q: expected a duration such as 10s or 2m", name, text)
+	}
+	return d, nil
+}
+
 func getVersionString() string {
 	if Version == "dev" {
 		return fmt.Sprintf("%s (development build)", Version)
@@ -40,18 +236,65 @@ The CLI will look for your API key in the following order:
 
 Instance API keys are automatically saved when using 'instance get' command.`,
 	Version: getVersionString(),
+	// Execute logs the error instead, see there
+	SilenceErrors: true,
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := interruptContext(context.Background())
+	defer stop()
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	err = finishOutputFile(err)
+	// Cobra's own error printing is off, so the error is logged once here,
+	// in the --log-format format, unless the command silenced it. The root
+	// command is silenced for that reason only.
+	if err != nil && (cmd == rootCmd || !cmd.SilenceErrors) {
+		logError("Error: %v", err)
+	}
+	return err
 }
 
 func init() {
 	// Set custom version template to match gh style
 	rootCmd.SetVersionTemplate("cloudamqp version {{.Version}}\n")
 
-	rootCmd.PersistentFlags().StringP("output", "o", "table", "Output format: table or json")
+	rootCmd.PersistentFlags().StringP("output", "o", "table", "Output format: table, wide (more columns where supported), json, jsonl (list commands only), yaml or template")
+	rootCmd.PersistentFlags().String("template", "", "Go template applied to each record with -o template, e.g. '{{.Name}} {{.Plan}}'")
 	rootCmd.PersistentFlags().StringSlice("fields", nil, "Fields to include in output (comma-separated)")
+	rootCmd.PersistentFlags().String("sort", "", "Sort list output by this column, e.g. name or plan")
+	rootCmd.PersistentFlags().String("output-file", "", "Write command results to this file instead of stdout; status messages stay on stderr")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Print only essential output, such as IDs")
+	rootCmd.PersistentFlags().String("color", colorAuto, "Color table output: auto (terminals only, unless NO_COLOR is set), always or never")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output, like --color never")
+	rootCmd.RegisterFlagCompletionFunc("color", cobra.FixedCompletions([]string{colorAuto, colorAlways, colorNever}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.PersistentFlags().BoolVar(&noProgress, "no-progress", false, "Disable the progress spinner while waiting and screen redraws with --watch")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Bypass the completion cache and always query the API")
+	rootCmd.PersistentFlags().String("proxy", "", "Proxy URL for API requests (overrides HTTP_PROXY/HTTPS_PROXY)")
+	rootCmd.PersistentFlags().String("ca-cert", "", "PEM file with an extra CA certificate to trust for API requests")
+	rootCmd.PersistentFlags().Bool("insecure", false, "Skip TLS certificate verification (unsafe, for test endpoints only)")
+	rootCmd.PersistentFlags().String("connect-timeout", "", "Maximum time to establish a connection to the API, TCP connect and TLS handshake each (e.g., 5s; default: 30s connect, 10s handshake)")
+	rootCmd.PersistentFlags().String("request-timeout", "", "Maximum time for each API request as a whole, including reading the response (e.g., 2m; default: no limit)")
+	rootCmd.PersistentFlags().String("trace", "", "Write every API request and response, with credentials redacted, to this file for debugging")
+	rootCmd.PersistentFlags().String("retry-on", client.DefaultRetryOn, "Failures to retry up to 3 times, comma-separated: 429, 5xx, 5xx-on-idempotent (GET, HEAD, PUT, DELETE and OPTIONS, never POST), timeout, connreset (both idempotent requests only), or none")
+	rootCmd.PersistentFlags().Float64("rate-limit", 0, "Maximum API requests per second, shared by concurrent operations (0 = unlimited)")
+	rootCmd.PersistentFlags().IntVar(&concurrency, "concurrency", 0, "Maximum API operations run at once by commands that work on many instances (default: CLOUDAMQP_CONCURRENCY, else the number of CPUs up to 8)")
+	rootCmd.PersistentFlags().String("log-format", logFormatText, "Format of diagnostic output on stderr: text or json")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("log-format")
+		if err := configureLogging(os.Stderr, format, isQuiet(cmd)); err != nil {
+			return err
+		}
+		if err := configureTransport(cmd, args); err != nil {
+			return err
+		}
+		if err := checkConcurrency(); err != nil {
+			return err
+		}
+		return openOutputFile(cmd)
+	}
+	rootCmd.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
+		return printerErr()
+	}
 
 	rootCmd.AddCommand(instanceCmd)
 	rootCmd.AddCommand(vpcCmd)
@@ -59,5 +302,10 @@ func init() {
 	rootCmd.AddCommand(plansCmd)
 	rootCmd.AddCommand(teamCmd)
 	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(rotateKeyCmd)
+	rootCmd.AddCommand(whoamiCmd)
+	rootCmd.AddCommand(pingCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(examplesCmd)
 	rootCmd.AddCommand(completionCmd)
 }
@@ -24,8 +24,7 @@ var rotateKeyCmd = &cobra.Command{
 
 		resp, err := c.RotateAPIKey()
 		if err != nil {
-			fmt.Printf("Error rotating API key: %v\n", err)
-			return err
+			return fmt.Errorf("failed to rotate API key: %w", err)
 		}
 
 		output, err := json.MarshalIndent(resp, "", "  ")
@@ -33,15 +32,19 @@ var rotateKeyCmd = &cobra.Command{
 			return fmt.Errorf("failed to format response: %v", err)
 		}
 
-		fmt.Printf("API key rotated successfully:\n%s\n", string(output))
+		printStatus(cmd, "API key rotated successfully.")
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
 
-		// Update local config file with new key
+		// Update local config file with new key. The old key is already
+		// revoked at this point, so a failed save must not go unnoticed.
 		if err := saveAPIKey(resp.APIKey); err != nil {
-			fmt.Printf("Warning: Could not update local config file: %v\n", err)
-		} else {
-			fmt.Printf("Local config file updated with new API key.\n")
+			configPath, _ := getConfigPath()
+			logWarn("The API key was rotated but the local config file %s could not be updated: %v", configPath, err)
+			logWarn("Your previous API key no longer works. Save the new key manually: %s", resp.APIKey)
+			return fmt.Errorf("failed to save new API key: %w", err)
 		}
 
+		printStatus(cmd, "Local config file updated with new API key.")
 		return nil
 	},
 }
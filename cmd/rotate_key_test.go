@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRotateKeyServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/apikeys/rotate-apikey", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"apikey": "new-key"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSaveAPIKey_Atomic(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	require.NoError(t, saveAPIKey("first-key"))
+	require.NoError(t, saveAPIKey("  second-key\n"))
+
+	data, err := os.ReadFile(filepath.Join(home, ".cloudamqprc"))
+	require.NoError(t, err)
+	assert.Equal(t, "second-key", string(data))
+
+	info, err := os.Stat(filepath.Join(home, ".cloudamqprc"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// No temporary files should be left behind
+	entries, err := os.ReadDir(home)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestRotateKeyCmd_SavesNewKey(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CLOUDAMQP_APIKEY", "old-key")
+	t.Setenv("CLOUDAMQP_URL", newRotateKeyServer(t).URL)
+
+	err := rotateKeyCmd.RunE(rotateKeyCmd, []string{})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(home, ".cloudamqprc"))
+	require.NoError(t, err)
+	assert.Equal(t, "new-key", string(data))
+}
+
+func TestRotateKeyCmd_SaveFailure(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CLOUDAMQP_APIKEY", "old-key")
+	t.Setenv("CLOUDAMQP_URL", newRotateKeyServer(t).URL)
+
+	// A directory in place of the config file makes the rename fail
+	require.NoError(t, os.Mkdir(filepath.Join(home, ".cloudamqprc"), 0700))
+
+	err := rotateKeyCmd.RunE(rotateKeyCmd, []string{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to save new API key")
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+)
+
+// specSchemaURL is the JSON Schema dialect of the printed schema.
+const specSchemaURL = "https://json-schema.org/draft/2020-12/schema"
+
+// specSchema returns a JSON Schema for spec files, generated from the json
+// tags of InstanceSpec so it can't drift from what apply reads. Fields
+// without omitempty are required. The config section lists the RabbitMQ and
+// LavinMQ settings with their types and defaults; other keys are passed
+// through to the API, as with config set, so they are allowed.
+func specSchema() map[string]any {
+	schema := typeSchema(reflect.TypeOf(InstanceSpec{}))
+	schema["$schema"] = specSchemaURL
+	schema["title"] = "CloudAMQP instance spec"
+	schema["description"] = "Spec file written by 'cloudamqp instance export' and read by 'cloudamqp instance apply'"
+	schema["properties"].(map[string]any)["config"] = configPropertiesSchema()
+	return schema
+}
+
+// typeSchema returns the JSON Schema of a Go type as encoding/json would
+// write it.
+func typeSchema(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.Struct:
+		properties := map[string]any{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = typeSchema(field.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		// interface{} values may be anything
+		return map[string]any{}
+	}
+}
+
+// configPropertiesSchema describes a config section with the settings of
+// both brokers. The setting names don't overlap, so one spec schema covers
+// RabbitMQ and LavinMQ instances; each setting says which broker it is for.
+func configPropertiesSchema() map[string]any {
+	properties := map[string]any{}
+	for _, broker := range []struct {
+		name     string
+		settings []configSetting
+	}{
+		{"RabbitMQ", rabbitMQConfigSchema},
+		{"LavinMQ", lavinMQConfigSchema},
+	} {
+		for _, setting := range broker.settings {
+			property := map[string]any{
+				"default":     setting.Default,
+				"description": broker.name + " setting",
+			}
+			switch setting.Type {
+			case configInt:
+				property["type"] = "integer"
+			case configFloat:
+				property["type"] = "number"
+			case configBool:
+				property["type"] = "boolean"
+			case configString:
+				property["type"] = "string"
+			}
+			if len(setting.Values) > 0 {
+				property["enum"] = setting.Values
+			}
+			properties[setting.Key] = property
+		}
+	}
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": true,
+	}
+}
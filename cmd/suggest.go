@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"strings"
+)
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// suggestClosest returns the candidate closest to input, compared
+// case-insensitively, or "" if none is reasonably close.
+func suggestClosest(input string, candidates []string) string {
+	input = strings.ToLower(input)
+	best := ""
+	bestDist := -1
+	for _, c := range candidates {
+		d := levenshtein(input, strings.ToLower(c))
+		if bestDist == -1 || d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	// Only suggest when at most about a third of the input differs
+	if bestDist == -1 || bestDist > max(2, len(input)/3) {
+		return ""
+	}
+	return best
+}
@@ -40,8 +40,7 @@ Default role: member`,
 
 		resp, err := c.InviteTeamMember(req)
 		if err != nil {
-			fmt.Printf("Error inviting team member: %v\n", err)
-			return err
+			return fmt.Errorf("failed to invite team member: %w", err)
 		}
 
 		output, err := json.MarshalIndent(resp, "", "  ")
@@ -49,7 +48,8 @@ Default role: member`,
 			return fmt.Errorf("failed to format response: %v", err)
 		}
 
-		fmt.Printf("Team member invited:\n%s\n", string(output))
+		printStatus(cmd, "Team member invited.")
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
 		return nil
 	},
 }
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
 
 	"cloudamqp-cli/client"
 	"github.com/spf13/cobra"
@@ -10,9 +9,14 @@ import (
 
 var teamListCmd = &cobra.Command{
 	Use:     "list",
+	Aliases: []string{"ls"},
 	Short:   "List team members",
-	Long:    `Retrieves all team members.`,
-	Example: `  cloudamqp team list`,
+	Long: `Retrieves all team members, including pending invitations.
+
+The STATUS column is active for members and invited for invitations that
+have not been accepted yet.`,
+	Example: `  cloudamqp team list
+  cloudamqp team list -o json`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var err error
 		apiKey, err = getAPIKey()
@@ -24,32 +28,31 @@ var teamListCmd = &cobra.Command{
 
 		members, err := c.ListTeamMembers()
 		if err != nil {
-			fmt.Printf("Error listing team members: %v\n", err)
-			return err
+			return fmt.Errorf("failed to list team members: %w", err)
 		}
 
 		if len(members) == 0 {
-			fmt.Println("No team members found.")
+			printStatus(cmd, "No team members found.")
 			return nil
 		}
 
-		p, err := getPrinter(cmd)
+		p, err := getListPrinter(cmd)
 		if err != nil {
 			return err
 		}
 
-		headers := []string{"EMAIL", "ROLES", "2FA"}
+		headers := []string{"EMAIL", "ROLE", "STATUS", "2FA"}
 		rows := make([][]string, len(members))
 		for i, member := range members {
-			roles := strings.Join(member.Roles, ", ")
-			if roles == "" {
-				roles = "-"
+			role := member.Role
+			if role == "" {
+				role = "-"
 			}
 			tfa := "No"
 			if member.TFAAuthEnabled {
 				tfa = "Yes"
 			}
-			rows[i] = []string{member.Email, roles, tfa}
+			rows[i] = []string{member.Email, role, member.Status, tfa}
 		}
 		p.PrintRecords(headers, rows)
 
@@ -26,8 +26,7 @@ var teamRemoveCmd = &cobra.Command{
 
 		resp, err := c.RemoveTeamMember(removeEmail)
 		if err != nil {
-			fmt.Printf("Error removing team member: %v\n", err)
-			return err
+			return fmt.Errorf("failed to remove team member: %w", err)
 		}
 
 		output, err := json.MarshalIndent(resp, "", "  ")
@@ -35,7 +34,8 @@ var teamRemoveCmd = &cobra.Command{
 			return fmt.Errorf("failed to format response: %v", err)
 		}
 
-		fmt.Printf("Team member removed:\n%s\n", string(output))
+		printStatus(cmd, "Team member removed.")
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
 		return nil
 	},
 }
@@ -42,8 +42,7 @@ Available roles: admin, devops, member, monitor, billing manager`,
 
 		resp, err := c.UpdateTeamMember(updateUserID, req)
 		if err != nil {
-			fmt.Printf("Error updating team member: %v\n", err)
-			return err
+			return fmt.Errorf("failed to update team member: %w", err)
 		}
 
 		output, err := json.MarshalIndent(resp, "", "  ")
@@ -51,7 +50,8 @@ Available roles: admin, devops, member, monitor, billing manager`,
 			return fmt.Errorf("failed to format response: %v", err)
 		}
 
-		fmt.Printf("Team member updated:\n%s\n", string(output))
+		printStatus(cmd, "Team member updated.")
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
 		return nil
 	},
 }
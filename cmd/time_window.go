@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"cloudamqp-cli/internal/timeparse"
+	"github.com/spf13/cobra"
+)
+
+// addTimeWindowFlags registers --since and --until, which take a time or a
+// duration before now, on a command that shows what happened in a period.
+func addTimeWindowFlags(cmd *cobra.Command, what string) {
+	cmd.Flags().String("since", "", "Only show "+what+" from this time on: a duration ago such as 30m, 24h or 7d, or a time such as 2026-10-16T10:00:00Z")
+	cmd.Flags().String("until", "", "Only show "+what+" before this time, in the same formats as --since")
+}
+
+// timeWindow returns the --since and --until times, zero when not given.
+func timeWindow(cmd *cobra.Command) (since, until time.Time, err error) {
+	return timeRange(cmd, "since", "until")
+}
+
+// timeRange parses the time flags from and to, either of which may be
+// unset, and checks that to is after from.
+func timeRange(cmd *cobra.Command, from, to string) (start, end time.Time, err error) {
+	now := time.Now()
+	if start, err = timeFlag(cmd, from, now); err != nil {
+		return
+	}
+	if end, err = timeFlag(cmd, to, now); err != nil {
+		return
+	}
+	if !start.IsZero() && !end.IsZero() && !end.After(start) {
+		err = fmt.Errorf("--%s must be after --%s", to, from)
+	}
+	return
+}
+
+// timeFlag parses a flag holding a time or a duration before now.
+func timeFlag(cmd *cobra.Command, name string, now time.Time) (time.Time, error) {
+	value, _ := cmd.Flags().GetString(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := timeparse.Parse(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s %q: expected a duration such as 24h or 7d, or a time such as 2026-10-16T10:00:00Z", name, value)
+	}
+	return t, nil
+}
+
+// inTimeWindow reports whether t is at or after since and before until,
+// either of which may be zero for no bound.
+func inTimeWindow(t, since, until time.Time) bool {
+	return (since.IsZero() || !t.Before(since)) && (until.IsZero() || t.Before(until))
+}
@@ -15,17 +15,18 @@ var (
 )
 
 var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Show cloudamqp version information",
-	Long:  `Display the version number, build date, and release information for cloudamqp CLI.`,
+	Use:     "version",
+	Short:   "Show cloudamqp version information",
+	Long:    `Display the version number, build date, and release information for cloudamqp CLI.`,
+	Example: `  cloudamqp version`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if Version == "dev" {
-			fmt.Printf("cloudamqp version %s (development build)\n", Version)
+			fmt.Fprintf(cmd.OutOrStdout(), "cloudamqp version %s (development build)\n", Version)
 		} else {
-			fmt.Printf("cloudamqp version %s (%s)\n", Version, BuildDate)
+			fmt.Fprintf(cmd.OutOrStdout(), "cloudamqp version %s (%s)\n", Version, BuildDate)
 			// Strip leading 'v' if present to avoid double 'v' in URL
 			versionTag := strings.TrimPrefix(Version, "v")
-			fmt.Printf("https://github.com/cloudamqp/cli/releases/tag/v%s\n", versionTag)
+			fmt.Fprintf(cmd.OutOrStdout(), "https://github.com/cloudamqp/cli/releases/tag/v%s\n", versionTag)
 		}
 	},
 }
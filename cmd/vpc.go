@@ -7,7 +7,7 @@ import (
 var vpcCmd = &cobra.Command{
 	Use:   "vpc",
 	Short: "Manage CloudAMQP VPCs",
-	Long:  `Create, list, update, and delete CloudAMQP VPCs.`,
+	Long:  `Create, list, update, and delete CloudAMQP VPCs, and manage VPC peering.`,
 }
 
 func init() {
@@ -16,4 +16,5 @@ func init() {
 	vpcCmd.AddCommand(vpcGetCmd)
 	vpcCmd.AddCommand(vpcUpdateCmd)
 	vpcCmd.AddCommand(vpcDeleteCmd)
+	vpcCmd.AddCommand(vpcPeeringCmd)
 }
@@ -16,8 +16,9 @@ var (
 )
 
 var vpcCreateCmd = &cobra.Command{
-	Use:   "create",
-	Short: "Create a new CloudAMQP VPC",
+	Use:     "create",
+	Aliases: []string{"new"},
+	Short:   "Create a new CloudAMQP VPC",
 	Long: `Create a new CloudAMQP VPC with the specified configuration.
 
 Required flags:
@@ -42,13 +43,12 @@ Optional flags:
 			Name:   vpcName,
 			Region: vpcRegion,
 			Subnet: vpcSubnet,
-			Tags:   vpcTags,
+			Tags:   parseTags(vpcTags),
 		}
 
 		resp, err := c.CreateVPC(req)
 		if err != nil {
-			fmt.Printf("Error creating VPC: %v\n", err)
-			return err
+			return fmt.Errorf("failed to create VPC: %w", err)
 		}
 
 		output, err := json.MarshalIndent(resp, "", "  ")
@@ -56,7 +56,8 @@ Optional flags:
 			return fmt.Errorf("failed to format response: %v", err)
 		}
 
-		fmt.Printf("VPC created successfully:\n%s\n", string(output))
+		printStatus(cmd, "VPC created successfully.")
+		fmt.Fprintln(cmd.OutOrStdout(), string(output))
 		return nil
 	},
 }
@@ -1,11 +1,8 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
-	"os"
 	"strconv"
-	"strings"
 
 	"cloudamqp-cli/client"
 	"github.com/spf13/cobra"
@@ -17,8 +14,9 @@ var (
 )
 
 var vpcDeleteCmd = &cobra.Command{
-	Use:   "delete --id <id>",
-	Short: "Delete a CloudAMQP VPC",
+	Use:     "delete --id <id>",
+	Aliases: []string{"rm"},
+	Short:   "Delete a CloudAMQP VPC",
 	Long: `Delete a CloudAMQP VPC permanently.
 
 WARNING: This action cannot be undone. All instances in the VPC must be deleted first.`,
@@ -42,16 +40,12 @@ WARNING: This action cannot be undone. All instances in the VPC must be deleted
 		}
 
 		if !forceDeleteVPC {
-			fmt.Printf("Are you sure you want to delete VPC %d? This action cannot be undone. (y/N): ", vpcID)
-			reader := bufio.NewReader(os.Stdin)
-			response, err := reader.ReadString('\n')
+			ok, err := confirm(cmd, fmt.Sprintf("Are you sure you want to delete VPC %d? This action cannot be undone.", vpcID), "--force")
 			if err != nil {
-				return fmt.Errorf("failed to read confirmation: %v", err)
+				return err
 			}
-
-			response = strings.TrimSpace(strings.ToLower(response))
-			if response != "y" && response != "yes" {
-				fmt.Println("Delete operation cancelled.")
+			if !ok {
+				printStatus(cmd, "Delete operation cancelled.")
 				return nil
 			}
 		}
@@ -60,11 +54,10 @@ WARNING: This action cannot be undone. All instances in the VPC must be deleted
 
 		err = c.DeleteVPC(vpcID)
 		if err != nil {
-			fmt.Printf("Error deleting VPC: %v\n", err)
-			return err
+			return fmt.Errorf("failed to delete VPC: %w", err)
 		}
 
-		fmt.Printf("VPC %d deleted successfully.\n", vpcID)
+		printStatus(cmd, "VPC %d deleted successfully.", vpcID)
 		return nil
 	},
 }
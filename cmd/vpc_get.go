@@ -35,8 +35,7 @@ var vpcGetCmd = &cobra.Command{
 
 		vpc, err := c.GetVPC(vpcID)
 		if err != nil {
-			fmt.Printf("Error getting VPC: %v\n", err)
-			return err
+			return fmt.Errorf("failed to get VPC: %w", err)
 		}
 
 		p, err := getPrinter(cmd)
@@ -10,11 +10,17 @@ import (
 
 var vpcListCmd = &cobra.Command{
 	Use:     "list",
+	Aliases: []string{"ls"},
 	Short:   "List all CloudAMQP VPCs",
 	Long:    `Retrieves and displays all CloudAMQP VPCs in your account.`,
-	Example: `  cloudamqp vpc list`,
+	Example: `  cloudamqp vpc list
+  cloudamqp vpc list --limit 10`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var err error
+		opts, err := getListOptions(cmd)
+		if err != nil {
+			return err
+		}
+
 		apiKey, err = getAPIKey()
 		if err != nil {
 			return fmt.Errorf("failed to get API key: %w", err)
@@ -22,18 +28,17 @@ var vpcListCmd = &cobra.Command{
 
 		c := client.New(apiKey, Version)
 
-		vpcs, err := c.ListVPCs()
+		vpcs, err := c.ListVPCsWithOptions(opts)
 		if err != nil {
-			fmt.Printf("Error listing VPCs: %v\n", err)
-			return err
+			return fmt.Errorf("failed to list VPCs: %w", err)
 		}
 
 		if len(vpcs) == 0 {
-			fmt.Println("No VPCs found.")
+			printStatus(cmd, "No VPCs found.")
 			return nil
 		}
 
-		p, err := getPrinter(cmd)
+		p, err := getListPrinter(cmd)
 		if err != nil {
 			return err
 		}
@@ -53,3 +58,7 @@ var vpcListCmd = &cobra.Command{
 		return nil
 	},
 }
+
+func init() {
+	addListFlags(vpcListCmd)
+}
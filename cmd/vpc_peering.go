@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"cloudamqp-cli/client"
+	"github.com/spf13/cobra"
+)
+
+var vpcPeeringCmd = &cobra.Command{
+	Use:   "peering",
+	Short: "Manage VPC peering",
+	Long:  `List and request peering connections between a CloudAMQP VPC and your own VPC.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.Help()
+		cmd.SilenceUsage = true
+		return fmt.Errorf("subcommand required")
+	},
+}
+
+var vpcPeeringListCmd = &cobra.Command{
+	Use:     "list --vpc-id <id>",
+	Aliases: []string{"ls"},
+	Short:   "List peering connections",
+	Long:    `Retrieves all peering connections of a VPC and their status.`,
+	Example: `  cloudamqp vpc peering list --vpc-id 5678`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vpcID, err := vpcIDFromFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err = getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := client.New(apiKey, Version)
+
+		peerings, err := c.ListVPCPeerings(vpcID)
+		if err != nil {
+			return fmt.Errorf("failed to list VPC peerings: %w", err)
+		}
+
+		if len(peerings) == 0 {
+			printStatus(cmd, "No peering connections found.")
+			return nil
+		}
+
+		p, err := getListPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		headers := []string{"ID", "STATUS", "PEER_VPC_ID", "PEER_ACCOUNT_ID", "PEER_REGION", "PEER_SUBNET"}
+		rows := make([][]string, len(peerings))
+		for i, peering := range peerings {
+			rows[i] = []string{
+				peering.ID,
+				peering.Status,
+				peering.PeerVPCID,
+				peering.PeerAccountID,
+				peering.PeerRegion,
+				peering.PeerSubnet,
+			}
+		}
+		p.PrintRecords(headers, rows)
+
+		return nil
+	},
+}
+
+var vpcPeeringRequestCmd = &cobra.Command{
+	Use:   "request --vpc-id <id> --peer-account <account_id> --peer-vpc-id <vpc_id>",
+	Short: "Request a peering connection",
+	Long: `Request a peering connection from a CloudAMQP VPC to your own VPC.
+
+The request must be accepted in your cloud provider account before traffic can flow.`,
+	Example: `  cloudamqp vpc peering request --vpc-id 5678 --peer-account 210987654321 --peer-vpc-id vpc-0abc --peer-region us-east-1`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		vpcID, err := vpcIDFromFlag(cmd)
+		if err != nil {
+			return err
+		}
+
+		peerAccount, _ := cmd.Flags().GetString("peer-account")
+		peerVPCID, _ := cmd.Flags().GetString("peer-vpc-id")
+		peerRegion, _ := cmd.Flags().GetString("peer-region")
+		peerSubnet, _ := cmd.Flags().GetString("peer-subnet")
+
+		if peerSubnet != "" {
+			if err := validateCIDR(peerSubnet); err != nil {
+				return err
+			}
+		}
+
+		req := &client.VPCPeeringRequest{
+			PeerVPCID:     peerVPCID,
+			PeerAccountID: peerAccount,
+			PeerRegion:    peerRegion,
+			PeerSubnet:    peerSubnet,
+		}
+
+		if isDryRun(cmd) {
+			return printDryRun(cmd, "POST", "/vpcs/"+strconv.Itoa(vpcID)+"/vpc-peering/request", req)
+		}
+
+		apiKey, err = getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := client.New(apiKey, Version)
+
+		peering, err := c.RequestVPCPeering(vpcID, req)
+		if err != nil {
+			return fmt.Errorf("failed to request VPC peering: %w", err)
+		}
+
+		p, err := getPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		p.PrintRecord(
+			[]string{"ID", "STATUS", "PEER_VPC_ID", "PEER_ACCOUNT_ID"},
+			[]string{peering.ID, peering.Status, peering.PeerVPCID, peering.PeerAccountID},
+		)
+
+		return nil
+	},
+}
+
+// vpcIDFromFlag parses the --vpc-id flag.
+func vpcIDFromFlag(cmd *cobra.Command) (int, error) {
+	idFlag, _ := cmd.Flags().GetString("vpc-id")
+	if idFlag == "" {
+		return 0, fmt.Errorf("VPC ID is required. Use --vpc-id flag")
+	}
+
+	vpcID, err := strconv.Atoi(idFlag)
+	if err != nil {
+		return 0, fmt.Errorf("invalid VPC ID: %v", err)
+	}
+	return vpcID, nil
+}
+
+func init() {
+	vpcPeeringListCmd.Flags().String("vpc-id", "", "VPC ID (required)")
+	vpcPeeringListCmd.MarkFlagRequired("vpc-id")
+	vpcPeeringListCmd.RegisterFlagCompletionFunc("vpc-id", completeVPCIDFlag)
+
+	vpcPeeringRequestCmd.Flags().String("vpc-id", "", "VPC ID (required)")
+	vpcPeeringRequestCmd.Flags().String("peer-account", "", "Account ID that owns the peer VPC (required)")
+	vpcPeeringRequestCmd.Flags().String("peer-vpc-id", "", "ID of the peer VPC (required)")
+	vpcPeeringRequestCmd.Flags().String("peer-region", "", "Region of the peer VPC, if different")
+	vpcPeeringRequestCmd.Flags().String("peer-subnet", "", "Subnet of the peer VPC in CIDR notation")
+	addDryRunFlag(vpcPeeringRequestCmd)
+	vpcPeeringRequestCmd.MarkFlagRequired("vpc-id")
+	vpcPeeringRequestCmd.MarkFlagRequired("peer-account")
+	vpcPeeringRequestCmd.MarkFlagRequired("peer-vpc-id")
+	vpcPeeringRequestCmd.RegisterFlagCompletionFunc("vpc-id", completeVPCIDFlag)
+
+	vpcPeeringCmd.AddCommand(vpcPeeringListCmd)
+	vpcPeeringCmd.AddCommand(vpcPeeringRequestCmd)
+}
@@ -45,7 +45,7 @@ You can update the following fields:
 
 		req := &client.VPCUpdateRequest{
 			Name: updateVPCName,
-			Tags: updateVPCTags,
+			Tags: parseTags(updateVPCTags),
 		}
 
 		if req.Name == "" && len(req.Tags) == 0 {
@@ -54,11 +54,10 @@ You can update the following fields:
 
 		err = c.UpdateVPC(vpcID, req)
 		if err != nil {
-			fmt.Printf("Error updating VPC: %v\n", err)
-			return err
+			return fmt.Errorf("failed to update VPC: %w", err)
 		}
 
-		fmt.Printf("VPC %d updated successfully.\n", vpcID)
+		printStatus(cmd, "VPC %d updated successfully.", vpcID)
 		return nil
 	},
 }
@@ -2,52 +2,352 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"cloudamqp-cli/client"
+	"cloudamqp-cli/internal/ui"
 )
 
-func waitForInstanceReady(c *client.Client, instanceID int, timeout time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+// pollInterval is how often the wait helpers poll the API.
+var pollInterval = 10 * time.Second
+
+// maxNilInstances is how many polls in a row may return no instance before
+// waitForInstanceReady gives up.
+const maxNilInstances = 5
+
+// noProgress disables the progress spinner, set by --no-progress.
+var noProgress bool
+
+// startProgress starts a spinner on the log output when progress is shown
+// there (see showProgress). Otherwise it returns nil and the wait helpers
+// log a line per poll instead; with --quiet, progress is left out
+// altogether.
+func startProgress(message string) *ui.Spinner {
+	if logLevel > slog.LevelInfo {
+		return nil
+	}
+	if !showProgress(logOutput) {
+		logInfo("%s", message)
+		return nil
+	}
+	s := ui.NewSpinner(logOutput, message)
+	s.Start()
+	return s
+}
+
+// showProgress reports whether self-updating progress, such as the spinner
+// or the redrawn --watch screen, may be drawn on w: only when w is a
+// terminal, and not with --no-progress or structured logs.
+func showProgress(w io.Writer) bool {
+	if noProgress || structuredLogs() {
+		return false
+	}
+	f, ok := w.(*os.File)
+	return ok && ui.IsTerminal(f)
+}
+
+// errWaitTimeout is wrapped by the error pollUntil returns when the wait
+// times out.
+var errWaitTimeout = errors.New("timeout")
+
+// pollUntil waits for what, e.g. "instance 1234 to be ready", by calling
+// check right away and then every pollInterval until it reports done or
+// fails. While waiting it shows a spinner, or logs a line per poll when
+// there is none. It gives up with errWaitTimeout after timeout, and with
+// errInterrupted when ctx is cancelled.
+func pollUntil(ctx context.Context, timeout time.Duration, what string, check func() (bool, error)) error {
+	return pollUntilStatus(ctx, timeout, what, func() (bool, string, error) {
+		done, err := check()
+		return done, "", err
+	})
+}
+
+// pollUntilStatus is pollUntil for checks that also report progress, such
+// as "2/3 nodes resized", shown next to the spinner and in the per-poll
+// lines.
+func pollUntilStatus(ctx context.Context, timeout time.Duration, what string, check func() (bool, string, error)) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	ticker := time.NewTicker(10 * time.Second)
+	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
 	startTime := time.Now()
 
-	// Check immediately first
-	instance, err := c.GetInstance(instanceID)
-	if err != nil {
-		return fmt.Errorf("failed to check instance status: %w", err)
-	}
-	if instance.Ready {
-		return nil
+	done, status, err := check()
+	if err != nil || done {
+		return err
 	}
 
-	fmt.Fprintf(os.Stderr, "Waiting for instance %d to be ready...\n", instanceID)
+	spinner := startProgress(fmt.Sprintf("Waiting for %s...", what))
+	defer spinner.Stop()
+	spinner.SetStatus(status)
 
 	for {
 		select {
-		case <-ctx.Done():
-			elapsed := time.Since(startTime)
-			return fmt.Errorf("timeout after %s waiting for instance to be ready", elapsed.Round(time.Second))
+		case <-waitCtx.Done():
+			spinner.Stop()
+			elapsed := time.Since(startTime).Round(time.Second)
+			if ctx.Err() != nil {
+				return fmt.Errorf("%w after %s", errInterrupted, elapsed)
+			}
+			return fmt.Errorf("%w after %s waiting for %s", errWaitTimeout, elapsed, what)
 		case <-ticker.C:
-			instance, err := c.GetInstance(instanceID)
+			done, status, err := check()
 			if err != nil {
-				return fmt.Errorf("failed to check instance status: %w", err)
+				return err
 			}
 
-			if instance.Ready {
-				elapsed := time.Since(startTime)
-				fmt.Fprintf(os.Stderr, "Instance is ready! (took %s)\n", elapsed.Round(time.Second))
+			elapsed := time.Since(startTime).Round(time.Second)
+			if done {
+				spinner.Stop()
+				logInfo("Done waiting for %s (took %s)", what, elapsed)
 				return nil
 			}
 
-			elapsed := time.Since(startTime)
-			fmt.Fprintf(os.Stderr, "Still waiting... (elapsed: %s)\n", elapsed.Round(time.Second))
+			spinner.SetStatus(status)
+			if spinner == nil {
+				if status != "" {
+					logInfo("Still waiting... (elapsed: %s, %s)", elapsed, status)
+				} else {
+					logInfo("Still waiting... (elapsed: %s)", elapsed)
+				}
+			}
+		}
+	}
+}
+
+func waitForInstanceReady(ctx context.Context, c client.ClientAPI, instanceID int, timeout time.Duration) error {
+	// An empty response is treated as not ready, up to maxNilInstances
+	// times in a row
+	nilCount := 0
+	return pollUntil(ctx, timeout, fmt.Sprintf("instance %d to be ready", instanceID), func() (bool, error) {
+		instance, err := c.GetInstance(instanceID)
+		if err != nil {
+			return false, fmt.Errorf("failed to check instance status: %w", err)
+		}
+		if instance == nil {
+			nilCount++
+			if nilCount >= maxNilInstances {
+				return false, fmt.Errorf("failed to check instance status: no instance returned in %d consecutive attempts", nilCount)
+			}
+			return false, nil
+		}
+		nilCount = 0
+		return instance.Ready, nil
+	})
+}
+
+// waitForNodesHealthy polls the nodes of the instance until every node is
+// configured and running, and returns the nodes from the last poll. On
+// timeout the error names the nodes that were still unhealthy.
+func waitForNodesHealthy(ctx context.Context, c client.ClientAPI, instanceID string, timeout time.Duration) ([]client.Node, error) {
+	var nodes []client.Node
+	err := pollUntilStatus(ctx, timeout, fmt.Sprintf("all nodes of instance %s to be healthy", instanceID), func() (bool, string, error) {
+		var err error
+		nodes, err = c.ListNodes(instanceID)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to check node status: %w", err)
+		}
+		unhealthy := unhealthyNodes(nodes)
+		if len(nodes) == 0 {
+			return false, "no nodes reported", nil
+		}
+		return len(unhealthy) == 0, "unhealthy: " + strings.Join(unhealthy, ", "), nil
+	})
+	// A failed check leaves no nodes to show; on timeout or Ctrl-C the last
+	// poll's nodes are returned with the error
+	if err != nil && !errors.Is(err, errWaitTimeout) && !errors.Is(err, errInterrupted) {
+		return nil, err
+	}
+	if errors.Is(err, errWaitTimeout) {
+		if unhealthy := unhealthyNodes(nodes); len(unhealthy) > 0 {
+			return nodes, fmt.Errorf("%w, still unhealthy: %s", err, strings.Join(unhealthy, ", "))
+		}
+		return nodes, fmt.Errorf("%w: no nodes reported", err)
+	}
+	return nodes, err
+}
+
+// unhealthyNodes describes the nodes that are not configured and running,
+// e.g. "rabbit@host-02 (not running)".
+func unhealthyNodes(nodes []client.Node) []string {
+	var unhealthy []string
+	for _, node := range nodes {
+		var problems []string
+		if !node.Configured {
+			problems = append(problems, "not configured")
+		}
+		if !node.Running {
+			problems = append(problems, "not running")
+		}
+		if len(problems) > 0 {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", node.Name, strings.Join(problems, ", ")))
+		}
+	}
+	return unhealthy
+}
+
+// waitForPlanChange polls until the instance reports plan and is ready. The
+// plan field lags behind the update call, so Ready alone isn't enough: the
+// instance can still be ready on the old plan.
+func waitForPlanChange(ctx context.Context, c client.ClientAPI, instanceID int, plan string, timeout time.Duration) error {
+	return pollUntil(ctx, timeout, fmt.Sprintf("instance %d to change to plan %s", instanceID, plan), func() (bool, error) {
+		instance, err := c.GetInstance(instanceID)
+		if err != nil {
+			return false, fmt.Errorf("failed to check instance status: %w", err)
+		}
+		return instance != nil && instance.Plan == plan && instance.Ready, nil
+	})
+}
+
+// waitForInstanceHealthy polls until the management API of the instance
+// answers, which happens after the instance reports ready. Configuration
+// pushed before that point may be lost.
+func waitForInstanceHealthy(ctx context.Context, c client.ClientAPI, instanceID int, timeout time.Duration) error {
+	return pollUntil(ctx, timeout, fmt.Sprintf("the management API of instance %d", instanceID), func() (bool, error) {
+		healthy, err := c.InstanceHealthy(instanceID)
+		if err != nil {
+			return false, fmt.Errorf("failed to check instance health: %w", err)
+		}
+		return healthy, nil
+	})
+}
+
+// waitForNodeRejoin polls until node has restarted since rebootedAt, is
+// running without partitions and the management API of the instance answers.
+// The uptime tells a node that came back apart from one that hasn't gone
+// down yet.
+func waitForNodeRejoin(ctx context.Context, c client.ClientAPI, instanceID int, node string, rebootedAt time.Time, timeout time.Duration) error {
+	return pollUntil(ctx, timeout, fmt.Sprintf("node %s to rejoin the cluster", node), func() (bool, error) {
+		details, err := c.GetNode(instanceID, node)
+		if err != nil && !rebootTransientError(err) {
+			return false, fmt.Errorf("failed to check node %s: %w", node, err)
+		}
+		if err != nil || details == nil {
+			// The node is unreachable while it reboots
+			return false, nil
+		}
+		restarted := time.Duration(details.Uptime)*time.Millisecond < time.Since(rebootedAt)
+		if !details.Running || !restarted || len(details.Partitions) > 0 {
+			return false, nil
+		}
+		healthy, err := c.InstanceHealthy(instanceID)
+		if err != nil {
+			return false, fmt.Errorf("failed to check instance health: %w", err)
+		}
+		return healthy, nil
+	})
+}
+
+// rebootTransientError reports whether err is what a node answers with while
+// it reboots: a server error or a failed connection. Anything else, such as
+// a rejected API key or an unknown node, won't go away by waiting.
+func rebootTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// waitForPasswordRotation polls until the instance is ready with a password
+// other than oldPassword, and returns the new credentials.
+func waitForPasswordRotation(ctx context.Context, c client.ClientAPI, instanceID, oldPassword string, timeout time.Duration) (*client.PasswordRotation, error) {
+	var rotation *client.PasswordRotation
+	err := pollUntil(ctx, timeout, fmt.Sprintf("password rotation of instance %s", instanceID), func() (bool, error) {
+		var err error
+		rotation, err = c.GetPasswordRotationStatus(instanceID)
+		if err != nil {
+			return false, fmt.Errorf("failed to check password rotation: %w", err)
+		}
+		return rotation.Status == client.PasswordRotationComplete && rotation.Password != oldPassword, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rotation, nil
+}
+
+// waitForInstanceDeleted polls until the API answers 404 Not Found for the
+// instance. A delete request is accepted before the instance is gone, so
+// only the 404 shows that the instance no longer exists; other errors end
+// the wait.
+func waitForInstanceDeleted(ctx context.Context, c client.ClientAPI, instanceID int, timeout time.Duration) error {
+	err := pollUntil(ctx, timeout, fmt.Sprintf("instance %d to be deleted", instanceID), func() (bool, error) {
+		_, err := c.GetInstance(instanceID)
+		if client.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to check instance status: %w", err)
+		}
+		return false, nil
+	})
+	if errors.Is(err, errWaitTimeout) {
+		return fmt.Errorf("%w: instance %d still exists", err, instanceID)
+	}
+	return err
+}
+
+// waitForDiskResize polls the instance nodes until all of them report an
+// additional disk size of at least sizeGB.
+func waitForDiskResize(ctx context.Context, c client.ClientAPI, instanceID, sizeGB int, timeout time.Duration) error {
+	return pollUntilStatus(ctx, timeout, fmt.Sprintf("disk resize on instance %d", instanceID), func() (bool, string, error) {
+		nodes, err := c.ListNodes(strconv.Itoa(instanceID))
+		if err != nil {
+			return false, "", fmt.Errorf("failed to check disk status: %w", err)
+		}
+		count := 0
+		for _, node := range nodes {
+			if node.AdditionalDiskSize >= sizeGB {
+				count++
+			}
+		}
+		return len(nodes) > 0 && count == len(nodes), fmt.Sprintf("%d/%d nodes resized", count, len(nodes)), nil
+	})
+}
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// watchLoop calls render immediately and then every interval, until ctx is
+// cancelled by Ctrl-C. When progress is shown on w (see showProgress), the
+// screen is cleared before each frame and a footer says when it was drawn;
+// otherwise the frames are written one after the other, without escape
+// codes.
+func watchLoop(ctx context.Context, w io.Writer, interval time.Duration, render func() error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	tty := showProgress(w)
+	for {
+		if tty {
+			fmt.Fprint(w, clearScreen)
+		}
+		if err := render(); err != nil {
+			return err
+		}
+		if tty {
+			fmt.Fprintf(w, "\nEvery %s, last updated %s. Press Ctrl-C to stop.\n", interval, time.Now().Format("15:04:05"))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
 		}
 	}
 }
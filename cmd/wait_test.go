@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"cloudamqp-cli/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchLoop_StopsOnRenderError(t *testing.T) {
+	calls := 0
+	renderErr := errors.New("boom")
+
+	var out bytes.Buffer
+	err := watchLoop(context.Background(), &out, time.Millisecond, func() error {
+		calls++
+		fmt.Fprintln(&out, "frame", calls)
+		if calls == 3 {
+			return renderErr
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, renderErr)
+
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, "frame 1\nframe 2\nframe 3\n", out.String(), "no escape codes or footer when not a terminal")
+}
+
+func TestPollUntil(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	t.Run("done right away", func(t *testing.T) {
+		calls := 0
+		require.NoError(t, pollUntil(context.Background(), time.Second, "x", func() (bool, error) {
+			calls++
+			return true, nil
+		}))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("done after polling", func(t *testing.T) {
+		calls := 0
+		require.NoError(t, pollUntil(context.Background(), time.Second, "x", func() (bool, error) {
+			calls++
+			return calls == 3, nil
+		}))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("check error", func(t *testing.T) {
+		checkErr := errors.New("boom")
+		err := pollUntil(context.Background(), time.Second, "x", func() (bool, error) { return false, checkErr })
+		assert.ErrorIs(t, err, checkErr)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		err := pollUntil(context.Background(), 10*time.Millisecond, "instance 1 to be ready", func() (bool, error) { return false, nil })
+		assert.ErrorIs(t, err, errWaitTimeout)
+		assert.ErrorContains(t, err, "waiting for instance 1 to be ready")
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := pollUntil(ctx, time.Second, "x", func() (bool, error) {
+			if calls++; calls == 2 {
+				cancel()
+			}
+			return false, nil
+		})
+		assert.ErrorIs(t, err, errInterrupted)
+	})
+}
+
+func TestPollUntil_ProgressOnLogOutput(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+	defer configureLogging(os.Stderr, logFormatText, false)
+
+	var logs bytes.Buffer
+	require.NoError(t, configureLogging(&logs, logFormatText, false))
+	assert.False(t, showProgress(&logs), "no spinner on a stream that is not a terminal")
+
+	calls := 0
+	require.NoError(t, pollUntil(context.Background(), time.Second, "x", func() (bool, error) {
+		calls++
+		return calls == 2, nil
+	}))
+	assert.Contains(t, logs.String(), "Waiting for x...")
+	assert.Contains(t, logs.String(), "Done waiting for x")
+	assert.NotContains(t, logs.String(), "\r", "progress is logged line by line where logs go")
+
+	noProgress = true
+	defer func() { noProgress = false }()
+	assert.False(t, showProgress(os.Stderr))
+}
+
+// sequenceClient returns the given instances from GetInstance in order,
+// repeating the last one.
+type sequenceClient struct {
+	client.ClientAPI
+	instances []*client.Instance
+	calls     int
+}
+
+func (s *sequenceClient) GetInstance(id int) (*client.Instance, error) {
+	i := min(s.calls, len(s.instances)-1)
+	s.calls++
+	return s.instances[i], nil
+}
+
+func TestWaitForInstanceReady_NilInstance(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	c := &sequenceClient{instances: []*client.Instance{nil, {ID: 1, Ready: false}, nil, {ID: 1, Ready: true}}}
+
+	require.NoError(t, waitForInstanceReady(context.Background(), c, 1, time.Second))
+	assert.Equal(t, 4, c.calls)
+}
+
+func TestWaitForInstanceReady_EmptyResponses(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		switch polls {
+		case 1:
+			w.WriteHeader(http.StatusNoContent)
+		case 2:
+			w.Write(nil)
+		default:
+			w.Write([]byte(`{"id":1,"ready":true}`))
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewForTest(server.URL)
+	require.NoError(t, waitForInstanceReady(context.Background(), c, 1, time.Second), "empty responses count as not ready")
+	assert.Equal(t, 3, polls)
+}
+
+func TestWaitForInstanceReady_TooManyNilInstances(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	c := &sequenceClient{instances: []*client.Instance{nil}}
+
+	err := waitForInstanceReady(context.Background(), c, 1, time.Second)
+	assert.ErrorContains(t, err, "no instance returned")
+	assert.Equal(t, maxNilInstances, c.calls)
+}
+
+// healthClient reports the instance healthy after the given number of checks.
+type healthClient struct {
+	client.ClientAPI
+	healthyAfter int
+	calls        int
+}
+
+func (h *healthClient) InstanceHealthy(id int) (bool, error) {
+	h.calls++
+	return h.calls > h.healthyAfter, nil
+}
+
+func TestWaitForInstanceHealthy(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	c := &healthClient{healthyAfter: 2}
+
+	require.NoError(t, waitForInstanceHealthy(context.Background(), c, 1, time.Second))
+	assert.Equal(t, 3, c.calls)
+}
+
+func TestWaitForInstanceHealthy_Timeout(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	err := waitForInstanceHealthy(context.Background(), &healthClient{healthyAfter: 1 << 30}, 1, 20*time.Millisecond)
+	assert.ErrorContains(t, err, "waiting for the management API")
+}
+
+// rotationClient reports the old password until the given number of checks
+// have been made.
+type rotationClient struct {
+	client.ClientAPI
+	rotatedAfter int
+	calls        int
+}
+
+func (r *rotationClient) GetPasswordRotationStatus(instanceID string) (*client.PasswordRotation, error) {
+	r.calls++
+	password := "old"
+	if r.calls > r.rotatedAfter {
+		password = "new"
+	}
+	return &client.PasswordRotation{
+		Status:   client.PasswordRotationComplete,
+		Password: password,
+		URL:      "amqps://user:" + password + "@host/vhost",
+	}, nil
+}
+
+func TestWaitForPasswordRotation(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	c := &rotationClient{rotatedAfter: 2}
+
+	rotation, err := waitForPasswordRotation(context.Background(), c, "1", "old", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "new", rotation.Password)
+	assert.Equal(t, 3, c.calls)
+}
+
+func TestWaitForPlanChange(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	// Ready on the old plan must not end the wait
+	c := &sequenceClient{instances: []*client.Instance{
+		{ID: 1, Plan: "bunny-1", Ready: true},
+		{ID: 1, Plan: "bunny-1", Ready: false},
+		{ID: 1, Plan: "rabbit-1", Ready: false},
+		{ID: 1, Plan: "rabbit-1", Ready: true},
+	}}
+
+	require.NoError(t, waitForPlanChange(context.Background(), c, 1, "rabbit-1", time.Second))
+	assert.Equal(t, 4, c.calls)
+}
+
+func TestWaitForPlanChange_Timeout(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	c := &sequenceClient{instances: []*client.Instance{{ID: 1, Plan: "bunny-1", Ready: true}}}
+
+	err := waitForPlanChange(context.Background(), c, 1, "rabbit-1", 20*time.Millisecond)
+	assert.ErrorIs(t, err, errWaitTimeout)
+	assert.ErrorContains(t, err, "waiting for instance 1 to change to plan rabbit-1")
+}
+
+// nodeClient answers GetNode with errs in turn, then with the node running
+// since startedAt.
+type nodeClient struct {
+	client.ClientAPI
+	errs      []error
+	startedAt time.Time
+	calls     int
+}
+
+func (n *nodeClient) GetNode(id int, node string) (*client.NodeDetails, error) {
+	n.calls++
+	if n.calls <= len(n.errs) {
+		return nil, n.errs[n.calls-1]
+	}
+	uptime := time.Since(n.startedAt).Milliseconds()
+	return &client.NodeDetails{Node: client.Node{Name: node, Running: true}, Uptime: uptime}, nil
+}
+
+func (n *nodeClient) InstanceHealthy(id int) (bool, error) {
+	return true, nil
+}
+
+func TestWaitForNodeRejoin_Errors(t *testing.T) {
+	orig := pollInterval
+	pollInterval = time.Millisecond
+	defer func() { pollInterval = orig }()
+
+	rebootedAt := time.Now()
+	connErr := &url.Error{Op: "Get", URL: "https://api.example.com", Err: errors.New("connection refused")}
+
+	t.Run("waits out server and connection errors", func(t *testing.T) {
+		c := &nodeClient{errs: []error{&client.APIError{StatusCode: 503}, connErr}, startedAt: time.Now()}
+
+		require.NoError(t, waitForNodeRejoin(context.Background(), c, 1, "rabbit@host-01", rebootedAt, time.Second))
+		assert.Equal(t, 3, c.calls)
+	})
+
+	t.Run("stops at other errors", func(t *testing.T) {
+		c := &nodeClient{errs: []error{&client.APIError{StatusCode: 401, Message: "unauthorized"}}, startedAt: time.Now()}
+
+		err := waitForNodeRejoin(context.Background(), c, 1, "rabbit@host-01", rebootedAt, time.Second)
+		assert.EqualError(t, err, "failed to check node rabbit@host-01: API error (401): unauthorized")
+		assert.Equal(t, 1, c.calls)
+	})
+}
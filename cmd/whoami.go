@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var whoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the account of the active API key",
+	Long:  `Displays the account name, email, and team that the configured API key belongs to.`,
+	Example: `  cloudamqp whoami
+  cloudamqp whoami -o json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		apiKey, err = getAPIKey()
+		if err != nil {
+			return fmt.Errorf("failed to get API key: %w", err)
+		}
+
+		c := newAPIClient(apiKey)
+
+		account, err := c.GetAccount()
+		if err != nil {
+			return fmt.Errorf("failed to get account: %w", err)
+		}
+
+		p, err := getPrinter(cmd)
+		if err != nil {
+			return err
+		}
+
+		p.PrintRecord(
+			[]string{"NAME", "EMAIL", "TEAM"},
+			[]string{account.Name, account.Email, account.Team},
+		)
+
+		return nil
+	},
+}
@@ -0,0 +1,172 @@
+// Package examples keeps the example invocations of each command, keyed by
+// command path such as "instance config set".
+//
+// Examples are written once, in the Example field of a command, and parsed
+// into specs here. The specs back the examples command and let tests check
+// that every example still names a real command with real flags.
+package examples
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// substitution matches an invocation whose output is captured in a shell
+// variable, as in ID=$(cloudamqp instance create ... -q).
+var substitution = regexp.MustCompile(`^\w+=\$\((.*)\)$`)
+
+// Spec is one example invocation.
+type Spec struct {
+	// Comment is the text of the # lines directly above the invocation.
+	Comment string
+	// Line is the invocation as written, continuation lines joined.
+	Line string
+	// Args are the words of the invocation after the program name, up to
+	// the first pipe, redirection, command separator or &&.
+	Args []string
+}
+
+// Parse splits the Example text of a command into specs. Every invocation
+// must start with program, or capture its output with VAR=$(program ...);
+// lines that don't, apart from comments and blank lines, are reported as
+// errors, as are unbalanced quotes.
+func Parse(program, text string) ([]Spec, error) {
+	var specs []Spec
+	var comments []string
+	lines := strings.Split(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "":
+			comments = nil
+			continue
+		case strings.HasPrefix(line, "#"):
+			comments = append(comments, strings.TrimSpace(strings.TrimPrefix(line, "#")))
+			continue
+		}
+
+		for strings.HasSuffix(line, "\\") && i+1 < len(lines) {
+			i++
+			line = strings.TrimSuffix(line, "\\") + " " + strings.TrimSpace(lines[i])
+		}
+
+		command := line
+		if m := substitution.FindStringSubmatch(line); m != nil {
+			command = m[1]
+		}
+		words, err := split(command)
+		if err != nil {
+			return nil, fmt.Errorf("example %q: %w", line, err)
+		}
+		if len(words) == 0 || words[0] != program {
+			return nil, fmt.Errorf("example %q does not start with %s", line, program)
+		}
+		specs = append(specs, Spec{Comment: strings.Join(comments, " "), Line: line, Args: words[1:]})
+		comments = nil
+	}
+	return specs, nil
+}
+
+// split breaks line into words like a POSIX shell would, honouring single
+// and double quotes and backslash escapes. It stops at the first unquoted
+// |, >, <, ; or &, which end the command itself.
+func split(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' {
+				escaped = true
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case strings.ContainsRune("|><;&", r):
+			if inWord {
+				words = append(words, word.String())
+			}
+			return words, nil
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// Registry maps command paths to their examples.
+type Registry struct {
+	mu    sync.RWMutex
+	specs map[string][]Spec
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{specs: map[string][]Spec{}}
+}
+
+// Add registers the examples of the command at path, after any already
+// registered for it.
+func (r *Registry) Add(path string, specs ...Spec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.specs[path] = append(r.specs[path], specs...)
+}
+
+// Lookup returns the examples of the command at path.
+func (r *Registry) Lookup(path string) []Spec {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.specs[path]
+}
+
+// Paths returns the command paths that have examples, sorted.
+func (r *Registry) Paths() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	paths := make([]string, 0, len(r.specs))
+	for path := range r.specs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Under returns the paths that are path itself or commands below it, sorted.
+func (r *Registry) Under(path string) []string {
+	var paths []string
+	for _, p := range r.Paths() {
+		if path == "" || p == path || strings.HasPrefix(p, path+" ") {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,59 @@
+package examples
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	specs, err := Parse("cloudamqp", `  cloudamqp instance get --id 1234
+  # Only some fields
+  cloudamqp instance get --id 1234 --template '{{.Name}} {{.Plan}}'
+
+  cloudamqp instance create --name=my-instance \
+    --plan=bunny-1
+  cloudamqp instance list -o json | jq '.[].id'
+  cloudamqp instance export --id 1234 > "my instance.yaml"
+  ID=$(cloudamqp instance create --name=x -q)
+  cloudamqp instance exists --id 1234 && echo "still there"`)
+	require.NoError(t, err)
+	require.Len(t, specs, 7)
+
+	assert.Equal(t, []string{"instance", "get", "--id", "1234"}, specs[0].Args)
+	assert.Empty(t, specs[0].Comment)
+
+	assert.Equal(t, "Only some fields", specs[1].Comment)
+	assert.Equal(t, []string{"instance", "get", "--id", "1234", "--template", "{{.Name}} {{.Plan}}"}, specs[1].Args)
+
+	assert.Equal(t, []string{"instance", "create", "--name=my-instance", "--plan=bunny-1"}, specs[2].Args)
+	assert.Equal(t, []string{"instance", "list", "-o", "json"}, specs[3].Args)
+	assert.Equal(t, []string{"instance", "export", "--id", "1234"}, specs[4].Args)
+	assert.Equal(t, []string{"instance", "create", "--name=x", "-q"}, specs[5].Args)
+	assert.Equal(t, "ID=$(cloudamqp instance create --name=x -q)", specs[5].Line)
+	assert.Equal(t, []string{"instance", "exists", "--id", "1234"}, specs[6].Args)
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse("cloudamqp", "  cloudamqp instance get --template '{{.Name}}")
+	assert.ErrorContains(t, err, "unterminated ' quote")
+
+	_, err = Parse("cloudamqp", "  instance get --id 1234")
+	assert.ErrorContains(t, err, "does not start with cloudamqp")
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.Add("instance get", Spec{Line: "cloudamqp instance get --id 1"})
+	r.Add("instance config set", Spec{Line: "cloudamqp instance config set --id 1 a 1"})
+	r.Add("instance config get", Spec{Line: "cloudamqp instance config get --id 1 a"})
+	r.Add("vpc list", Spec{Line: "cloudamqp vpc list"})
+
+	assert.Len(t, r.Lookup("instance get"), 1)
+	assert.Nil(t, r.Lookup("instance"))
+	assert.Equal(t, []string{"instance config get", "instance config set", "instance get", "vpc list"}, r.Paths())
+	assert.Equal(t, []string{"instance config get", "instance config set"}, r.Under("instance config"))
+	assert.Equal(t, []string{"instance config get", "instance config set", "instance get"}, r.Under("instance"))
+	assert.Empty(t, r.Under("inst"))
+}
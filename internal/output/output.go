@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"text/template"
 
 	"cloudamqp-cli/internal/table"
+	"cloudamqp-cli/internal/ui"
 )
 
 type Format string
@@ -14,22 +16,36 @@ type Format string
 const (
 	FormatTable Format = "table"
 	FormatJSON  Format = "json"
+	// FormatJSONL prints one compact JSON object per line.
+	FormatJSONL Format = "jsonl"
+	// FormatWide is a table with more columns. Commands without a wide
+	// column set print it as a plain table.
+	FormatWide Format = "wide"
 )
 
 type Printer struct {
-	format Format
-	fields []string
-	writer io.Writer
+	format   Format
+	fields   []string
+	writer   io.Writer
+	template *template.Template
+	sortBy   string
+	err      error
+	// color enables styled table output; columnStyles are keyed by the
+	// upper-cased header.
+	color        bool
+	columnStyles map[string]table.Style
 }
 
 func New(writer io.Writer, format Format, fields []string) (*Printer, error) {
 	switch format {
-	case FormatTable, FormatJSON, "":
+	case FormatTable, FormatWide, FormatJSON, FormatJSONL, FormatYAML, "":
 		if format == "" {
 			format = FormatTable
 		}
+	case FormatTemplate:
+		return nil, fmt.Errorf("output format \"template\" requires a template, e.g. --template '{{.Name}} {{.Plan}}'")
 	default:
-		return nil, fmt.Errorf("unknown output format %q: use \"table\" or \"json\"", format)
+		return nil, fmt.Errorf("unknown output format %q: use \"table\", \"wide\", \"json\", \"jsonl\", \"yaml\" or \"template\"", format)
 	}
 	return &Printer{format: format, fields: fields, writer: writer}, nil
 }
@@ -67,7 +83,65 @@ func (p *Printer) filterColumns(headers []string, rows [][]string) ([]string, []
 	return filteredHeaders, filteredRows
 }
 
+// SortBy makes PrintRecords sort rows by the column named column, matched
+// case-insensitively against the headers. Numeric columns sort numerically.
+func (p *Printer) SortBy(column string) {
+	p.sortBy = column
+}
+
+// sortRows sorts rows by the SortBy column. An unknown column is kept as
+// the printer's error and leaves the rows unsorted.
+func (p *Printer) sortRows(headers []string, rows [][]string) {
+	if p.sortBy == "" {
+		return
+	}
+	for i, h := range headers {
+		if strings.EqualFold(h, strings.TrimSpace(p.sortBy)) {
+			table.SortRows(rows, i, true)
+			return
+		}
+	}
+	if p.err == nil {
+		lower := make([]string, len(headers))
+		for i, h := range headers {
+			lower[i] = strings.ToLower(h)
+		}
+		p.err = fmt.Errorf("unknown sort column %q: use one of %s", p.sortBy, strings.Join(lower, ", "))
+	}
+}
+
+// SetColor turns ANSI styling of table output on or off: bold headers and
+// the styles given to StyleColumn. JSON and template output are never
+// styled.
+func (p *Printer) SetColor(enabled bool) {
+	p.color = enabled
+}
+
+// StyleColumn styles the values of the column named header in table output
+// when color is on.
+func (p *Printer) StyleColumn(header string, style table.Style) {
+	if p.columnStyles == nil {
+		p.columnStyles = map[string]table.Style{}
+	}
+	p.columnStyles[strings.ToUpper(header)] = style
+}
+
+// Err returns the first error from sorting or executing a template, if any.
+// Other formats cannot fail.
+func (p *Printer) Err() error {
+	return p.err
+}
+
+// flush flushes buffered writers so each JSON line reaches the consumer
+// as soon as it is printed.
+func (p *Printer) flush() {
+	if f, ok := p.writer.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+}
+
 func (p *Printer) PrintRecords(headers []string, rows [][]string) {
+	p.sortRows(headers, rows)
 	headers, rows = p.filterColumns(headers, rows)
 
 	switch p.format {
@@ -84,8 +158,38 @@ func (p *Printer) PrintRecords(headers []string, rows [][]string) {
 		}
 		data, _ := json.MarshalIndent(records, "", "  ")
 		fmt.Fprintln(p.writer, string(data))
+	case FormatJSONL:
+		for _, row := range rows {
+			record := make(map[string]string, len(headers))
+			for j, h := range headers {
+				if j < len(row) {
+					record[strings.ToLower(h)] = row[j]
+				}
+			}
+			data, _ := json.Marshal(record)
+			fmt.Fprintln(p.writer, string(data))
+			p.flush()
+		}
+	case FormatYAML:
+		records := make([]any, len(rows))
+		for i, row := range rows {
+			records[i] = yamlRecord(headers, row)
+		}
+		p.printYAML(records)
+	case FormatTemplate:
+		for _, row := range rows {
+			p.executeTemplate(headers, row)
+		}
 	default:
 		t := table.New(p.writer, headers...)
+		if p.color {
+			t.SetHeaderStyle(ui.Bold)
+			for i, h := range headers {
+				if style, ok := p.columnStyles[strings.ToUpper(h)]; ok {
+					t.SetColumnStyle(i, style)
+				}
+			}
+		}
 		for _, row := range rows {
 			t.AddRow(row...)
 		}
@@ -101,15 +205,24 @@ func (p *Printer) PrintRecord(headers []string, values []string) {
 	}
 
 	switch p.format {
-	case FormatJSON:
+	case FormatJSON, FormatJSONL:
 		record := make(map[string]string, len(headers))
 		for i, h := range headers {
 			if i < len(row) {
 				record[strings.ToLower(h)] = row[i]
 			}
 		}
-		data, _ := json.MarshalIndent(record, "", "  ")
+		var data []byte
+		if p.format == FormatJSONL {
+			data, _ = json.Marshal(record)
+		} else {
+			data, _ = json.MarshalIndent(record, "", "  ")
+		}
 		fmt.Fprintln(p.writer, string(data))
+	case FormatYAML:
+		p.printYAML(yamlRecord(headers, row))
+	case FormatTemplate:
+		p.executeTemplate(headers, row)
 	default:
 		for i, h := range headers {
 			val := ""
@@ -120,3 +233,33 @@ func (p *Printer) PrintRecord(headers []string, values []string) {
 		}
 	}
 }
+
+// PrintValue prints v, which may nest maps, lists and structs, as indented
+// JSON or as YAML with the same keys. Tables, JSON Lines and templates
+// can't show nested data, so for those it sets Err instead.
+func (p *Printer) PrintValue(v any) {
+	switch p.format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			p.err = err
+			return
+		}
+		fmt.Fprintln(p.writer, string(data))
+	case FormatYAML:
+		// Round-trip through JSON so structs get their JSON keys
+		data, err := json.Marshal(v)
+		if err != nil {
+			p.err = err
+			return
+		}
+		var generic any
+		if err := json.Unmarshal(data, &generic); err != nil {
+			p.err = err
+			return
+		}
+		p.printYAML(generic)
+	default:
+		p.err = fmt.Errorf("output format %q can't show nested data: use -o json or -o yaml", p.format)
+	}
+}
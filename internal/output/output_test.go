@@ -0,0 +1,209 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"cloudamqp-cli/internal/ui"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintRecords_JSONL(t *testing.T) {
+	var buf bytes.Buffer
+	p, err := New(&buf, FormatJSONL, nil)
+	require.NoError(t, err)
+
+	p.PrintRecords([]string{"ID", "NAME"}, [][]string{{"1", "a"}, {"2", "b"}})
+
+	assert.Equal(t, "{\"id\":\"1\",\"name\":\"a\"}\n{\"id\":\"2\",\"name\":\"b\"}\n", buf.String())
+}
+
+func TestNew_UnknownFormat(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, "xml", nil)
+	assert.ErrorContains(t, err, "unknown output format")
+}
+
+func TestWide_PrintsTable(t *testing.T) {
+	var wide, table bytes.Buffer
+	pw, err := New(&wide, FormatWide, nil)
+	require.NoError(t, err)
+	pt, err := New(&table, FormatTable, nil)
+	require.NoError(t, err)
+
+	pw.PrintRecords([]string{"ID", "NAME"}, [][]string{{"1", "a"}})
+	pt.PrintRecords([]string{"ID", "NAME"}, [][]string{{"1", "a"}})
+
+	assert.Equal(t, table.String(), wide.String())
+}
+
+func TestTemplate_Records(t *testing.T) {
+	var buf bytes.Buffer
+	p, err := NewTemplate(&buf, `{{.Name}} {{.plan | upper}} {{.VpcId}} {{.TAGS | split "," | join " "}}`, nil)
+	require.NoError(t, err)
+
+	p.PrintRecords(
+		[]string{"NAME", "PLAN", "VPC_ID", "TAGS"},
+		[][]string{{"orders", "bunny-1", "7", "prod,eu"}, {"billing", "lemur", "", ""}},
+	)
+
+	require.NoError(t, p.Err())
+	assert.Equal(t, "orders BUNNY-1 7 prod eu\nbilling LEMUR  \n", buf.String())
+}
+
+func TestTemplate_Record(t *testing.T) {
+	var buf bytes.Buffer
+	p, err := NewTemplate(&buf, "{{.ID}}={{.Name}}\n", nil)
+	require.NoError(t, err)
+
+	p.PrintRecord([]string{"ID", "NAME"}, []string{"1234", "orders"})
+
+	require.NoError(t, p.Err())
+	assert.Equal(t, "1234=orders\n", buf.String())
+}
+
+func TestTemplate_Errors(t *testing.T) {
+	_, err := NewTemplate(&bytes.Buffer{}, "{{.Name", nil)
+	assert.ErrorContains(t, err, "invalid template")
+
+	_, err = New(&bytes.Buffer{}, FormatTemplate, nil)
+	assert.ErrorContains(t, err, "requires a template")
+
+	var buf bytes.Buffer
+	p, err := NewTemplate(&buf, "{{.Missing}}", nil)
+	require.NoError(t, err)
+	p.PrintRecords([]string{"NAME"}, [][]string{{"a"}, {"b"}})
+	assert.ErrorContains(t, p.Err(), "failed to execute template")
+	assert.Empty(t, buf.String())
+}
+
+func TestPrintRecords_SortBy(t *testing.T) {
+	var buf bytes.Buffer
+	p, err := New(&buf, FormatJSONL, []string{"name"})
+	require.NoError(t, err)
+	p.SortBy("ID")
+
+	p.PrintRecords([]string{"ID", "NAME"}, [][]string{{"10", "a"}, {"2", "b"}, {"1", "c"}})
+
+	require.NoError(t, p.Err())
+	assert.Equal(t, "{\"name\":\"c\"}\n{\"name\":\"b\"}\n{\"name\":\"a\"}\n", buf.String(), "sorting works on columns left out by --fields")
+}
+
+func TestPrintRecords_SortByUnknownColumn(t *testing.T) {
+	p, err := New(&bytes.Buffer{}, FormatTable, nil)
+	require.NoError(t, err)
+	p.SortBy("size")
+
+	p.PrintRecords([]string{"ID", "NAME"}, [][]string{{"1", "a"}})
+
+	assert.ErrorContains(t, p.Err(), `unknown sort column "size": use one of id, name`)
+}
+
+func TestPrintRecords_Color(t *testing.T) {
+	headers := []string{"ID", "READY"}
+	rows := func() [][]string { return [][]string{{"1", "Yes"}} }
+
+	var plain bytes.Buffer
+	p, err := New(&plain, FormatTable, nil)
+	require.NoError(t, err)
+	p.StyleColumn("ready", ui.Green)
+	p.PrintRecords(headers, rows())
+	assert.NotContains(t, plain.String(), "\x1b[")
+
+	var colored bytes.Buffer
+	p, err = New(&colored, FormatTable, nil)
+	require.NoError(t, err)
+	p.SetColor(true)
+	p.StyleColumn("ready", ui.Green)
+	p.PrintRecords(headers, rows())
+	assert.Contains(t, colored.String(), ui.Bold("ID"))
+	assert.Contains(t, colored.String(), ui.Green("Yes"))
+	assert.Equal(t, plain.String(), ui.StripANSI(colored.String()))
+
+	var data bytes.Buffer
+	p, err = New(&data, FormatJSON, nil)
+	require.NoError(t, err)
+	p.SetColor(true)
+	p.StyleColumn("ready", ui.Green)
+	p.PrintRecords(headers, rows())
+	assert.NotContains(t, data.String(), "\x1b[")
+}
+
+func TestMarshalSortedYAML_Deterministic(t *testing.T) {
+	config := map[string]any{
+		"rabbit.vm_memory_high_watermark": 0.6,
+		"rabbit.heartbeat":                float64(60),
+		"rabbit.channel_max":              float64(2047),
+		"rabbit.log.exchange":             true,
+		"rabbit.cluster_name":             "orders",
+		"rabbit.tcp_listen_options": map[string]any{
+			"nodelay": true,
+			"backlog": float64(256),
+		},
+		"rabbit.auth_mechanisms": []any{"PLAIN", "AMQPLAIN"},
+		"rabbit.default_vhost":   nil,
+	}
+
+	first, err := MarshalSortedYAML(config)
+	require.NoError(t, err)
+	for i := 0; i < 50; i++ {
+		data, err := MarshalSortedYAML(config)
+		require.NoError(t, err)
+		require.Equal(t, first, data, "run %d", i)
+	}
+
+	assert.Equal(t, `rabbit.auth_mechanisms:
+    - PLAIN
+    - AMQPLAIN
+rabbit.channel_max: 2047
+rabbit.cluster_name: orders
+rabbit.default_vhost: null
+rabbit.heartbeat: 60
+rabbit.log.exchange: true
+rabbit.tcp_listen_options:
+    backlog: 256
+    nodelay: true
+rabbit.vm_memory_high_watermark: 0.6
+`, string(first))
+}
+
+func TestYAML_Records(t *testing.T) {
+	var buf bytes.Buffer
+	p, err := New(&buf, FormatYAML, nil)
+	require.NoError(t, err)
+
+	p.PrintRecords([]string{"NAME", "ID"}, [][]string{{"a", "1"}, {"b", "2"}})
+	assert.Equal(t, "- id: \"1\"\n  name: a\n- id: \"2\"\n  name: b\n", buf.String())
+
+	buf.Reset()
+	p.PrintRecord([]string{"Status", "URL"}, []string{"complete", "amqps://host"})
+	assert.Equal(t, "status: complete\nurl: amqps://host\n", buf.String())
+}
+
+func TestPrintValue(t *testing.T) {
+	type node struct {
+		Name    string `json:"name"`
+		Running bool   `json:"running"`
+	}
+	value := map[string]any{"id": 1234, "nodes": []node{{Name: "rabbit@host-01", Running: true}}}
+
+	var buf bytes.Buffer
+	p, err := New(&buf, FormatJSON, nil)
+	require.NoError(t, err)
+	p.PrintValue(value)
+	require.NoError(t, p.Err())
+	assert.JSONEq(t, `{"id":1234,"nodes":[{"name":"rabbit@host-01","running":true}]}`, buf.String())
+
+	buf.Reset()
+	p, err = New(&buf, FormatYAML, nil)
+	require.NoError(t, err)
+	p.PrintValue(value)
+	require.NoError(t, p.Err())
+	assert.Equal(t, "id: 1234\nnodes:\n    - name: rabbit@host-01\n      running: true\n", buf.String())
+
+	p, err = New(&buf, FormatTable, nil)
+	require.NoError(t, err)
+	p.PrintValue(value)
+	assert.ErrorContains(t, p.Err(), "use -o json or -o yaml")
+}
@@ -0,0 +1,87 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// FormatTemplate executes a Go text/template against each record.
+const FormatTemplate Format = "template"
+
+// templateFuncs are available in --template templates. join and split take
+// the separator first so they work at the end of a pipeline:
+// {{.Tags | split "," | join " "}}.
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join": func(sep string, elems []string) string {
+		return strings.Join(elems, sep)
+	},
+	"split": func(sep, s string) []string {
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, sep)
+	},
+}
+
+// NewTemplate returns a printer that executes text once per record, adding
+// a newline after each unless the template ends with one. Fields are the
+// column names in their original, lower and CamelCase spelling, so a NAME
+// column is available as {{.NAME}}, {{.name}} and {{.Name}}.
+func NewTemplate(writer io.Writer, text string, fields []string) (*Printer, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("output format \"template\" requires a template, e.g. --template '{{.Name}} {{.Plan}}'")
+	}
+	tmpl, err := template.New("output").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return &Printer{format: FormatTemplate, fields: fields, writer: writer, template: tmpl}, nil
+}
+
+// executeTemplate renders one record. After the first failure nothing more
+// is printed and the error is kept for Err.
+func (p *Printer) executeTemplate(headers, row []string) {
+	if p.err != nil {
+		return
+	}
+
+	data := make(map[string]string, 3*len(headers))
+	for i, h := range headers {
+		val := ""
+		if i < len(row) {
+			val = row[i]
+		}
+		data[h] = val
+		data[strings.ToLower(h)] = val
+		data[camelCase(h)] = val
+	}
+
+	var buf bytes.Buffer
+	if err := p.template.Execute(&buf, data); err != nil {
+		p.err = fmt.Errorf("failed to execute template: %w", err)
+		return
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	p.writer.Write(buf.Bytes())
+}
+
+// camelCase turns a column name such as VPC_ID or "RMQ VERSION" into VpcId
+// or RmqVersion.
+func camelCase(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		word = strings.ToLower(word)
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FormatYAML prints records as YAML with the keys sorted.
+const FormatYAML Format = "yaml"
+
+// MarshalSortedYAML formats v as YAML with the keys of every map, nested
+// ones included, in alphabetical order, so the same settings always give
+// byte-identical documents and diff cleanly under version control.
+func MarshalSortedYAML(v any) ([]byte, error) {
+	node, err := sortedYAMLNode(v)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(node)
+}
+
+// sortedYAMLNode builds the YAML node for v, ordering map keys explicitly
+// rather than relying on the encoder to do it.
+func sortedYAMLNode(v any) (*yaml.Node, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, err := sortedYAMLNode(v[key])
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+		}
+		return node, nil
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			value, err := sortedYAMLNode(item)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		return node, nil
+	default:
+		var node yaml.Node
+		if err := node.Encode(v); err != nil {
+			return nil, fmt.Errorf("failed to format YAML: %w", err)
+		}
+		return &node, nil
+	}
+}
+
+// yamlRecord converts a record to a map of headers, lower-cased as in JSON
+// output, to values.
+func yamlRecord(headers, row []string) map[string]any {
+	record := make(map[string]any, len(headers))
+	for i, h := range headers {
+		if i < len(row) {
+			record[strings.ToLower(h)] = row[i]
+		}
+	}
+	return record
+}
+
+func (p *Printer) printYAML(v any) {
+	data, err := MarshalSortedYAML(v)
+	if err != nil {
+		p.err = err
+		return
+	}
+	p.writer.Write(data)
+}
@@ -0,0 +1,57 @@
+// Package prompt asks the user to confirm an action.
+//
+// Commands only prompt when stdin is a terminal. Each command that prompts
+// also has a flag, such as --yes or --force, to skip the question in
+// scripts; without it a non-interactive run is refused rather than guessed.
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cloudamqp-cli/internal/ui"
+)
+
+// Confirm writes question to w and reads the answer from r. Only "y" and
+// "yes", in any case, confirm; anything else, including an empty answer or
+// end of input, declines.
+func Confirm(r io.Reader, w io.Writer, question string) (bool, error) {
+	fmt.Fprintf(w, "%s (y/N): ", question)
+	answer, err := readLine(r)
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+// ConfirmMatch writes question to w and asks the user to type expected,
+// e.g. the name of the instance to delete. Only the exact text confirms.
+func ConfirmMatch(r io.Reader, w io.Writer, question, expected string) (bool, error) {
+	fmt.Fprintf(w, "%s Type %q to confirm: ", question, expected)
+	answer, err := readLine(r)
+	if err != nil {
+		return false, err
+	}
+	return answer == expected, nil
+}
+
+// IsInteractive reports whether r is a terminal, so a prompt on it can be
+// answered.
+func IsInteractive(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && ui.IsTerminal(f)
+}
+
+// readLine reads one line from r without the line ending. A last line
+// without a newline is returned as is.
+func readLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
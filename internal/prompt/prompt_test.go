@@ -0,0 +1,56 @@
+package prompt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{" yes \n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+		{"y", true},
+		{"yep\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := Confirm(strings.NewReader(tt.input), &out, "Delete instance 1234?")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, "Delete instance 1234? (y/N): ", out.String())
+		})
+	}
+}
+
+func TestConfirmMatch(t *testing.T) {
+	var out bytes.Buffer
+	ok, err := ConfirmMatch(strings.NewReader("orders\n"), &out, "This deletes instance orders.", "orders")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, `This deletes instance orders. Type "orders" to confirm: `, out.String())
+
+	ok, err = ConfirmMatch(strings.NewReader("Orders\n"), &bytes.Buffer{}, "", "orders")
+	require.NoError(t, err)
+	assert.False(t, ok, "the match is case-sensitive")
+
+	ok, err = ConfirmMatch(strings.NewReader("y\n"), &bytes.Buffer{}, "", "orders")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestIsInteractive(t *testing.T) {
+	assert.False(t, IsInteractive(strings.NewReader("y\n")))
+}
@@ -3,9 +3,18 @@ package table
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
+
+	"cloudamqp-cli/internal/ui"
 )
 
+// Style decorates a value for display, e.g. with ANSI colors. Column widths
+// are always measured on the undecorated value.
+type Style func(value string) string
+
 // Column represents a column in the table
 type Column struct {
 	Header string
@@ -14,9 +23,11 @@ type Column struct {
 
 // Printer handles dynamic table printing with automatic width calculation
 type Printer struct {
-	columns []Column
-	rows    [][]string
-	writer  io.Writer
+	columns     []Column
+	rows        [][]string
+	writer      io.Writer
+	headerStyle Style
+	cellStyles  map[int]Style
 }
 
 // New creates a new table printer
@@ -25,7 +36,7 @@ func New(writer io.Writer, headers ...string) *Printer {
 	for i, header := range headers {
 		columns[i] = Column{
 			Header: header,
-			Width:  len(header),
+			Width:  width(header),
 		}
 	}
 	return &Printer{
@@ -43,8 +54,8 @@ func (p *Printer) AddRow(values ...string) error {
 
 	// Update column widths based on this row's values
 	for i, value := range values {
-		if len(value) > p.columns[i].Width {
-			p.columns[i].Width = len(value)
+		if w := width(value); w > p.columns[i].Width {
+			p.columns[i].Width = w
 		}
 	}
 
@@ -52,6 +63,19 @@ func (p *Printer) AddRow(values ...string) error {
 	return nil
 }
 
+// SetHeaderStyle styles the header row.
+func (p *Printer) SetHeaderStyle(style Style) {
+	p.headerStyle = style
+}
+
+// SetColumnStyle styles the values of column, e.g. to color a status.
+func (p *Printer) SetColumnStyle(column int, style Style) {
+	if p.cellStyles == nil {
+		p.cellStyles = map[int]Style{}
+	}
+	p.cellStyles[column] = style
+}
+
 // Print outputs the table with calculated column widths
 func (p *Printer) Print() {
 	// Add padding to widths
@@ -59,29 +83,108 @@ func (p *Printer) Print() {
 		p.columns[i].Width += 2
 	}
 
-	// Build format string
-	formatParts := make([]string, len(p.columns))
-	for i, col := range p.columns {
-		formatParts[i] = fmt.Sprintf("%%-%ds", col.Width)
-	}
-	format := strings.Join(formatParts, " ") + "\n"
-
-	// Print header
-	headers := make([]interface{}, len(p.columns))
-	separators := make([]interface{}, len(p.columns))
+	headers := make([]string, len(p.columns))
+	separators := make([]string, len(p.columns))
 	for i, col := range p.columns {
 		headers[i] = col.Header
 		separators[i] = strings.Repeat("-", col.Width)
 	}
-	fmt.Fprintf(p.writer, format, headers...)
-	fmt.Fprintf(p.writer, format, separators...)
+	p.printLine(headers, func(int) Style { return p.headerStyle })
+	p.printLine(separators, func(int) Style { return nil })
 
-	// Print rows
 	for _, row := range p.rows {
-		rowInterface := make([]interface{}, len(row))
-		for i, v := range row {
-			rowInterface[i] = v
+		p.printLine(row, func(i int) Style { return p.cellStyles[i] })
+	}
+}
+
+// printLine prints values padded to the column widths. Padding is computed
+// before styling so ANSI codes don't shift the columns.
+func (p *Printer) printLine(values []string, style func(column int) Style) {
+	var b strings.Builder
+	for i, value := range values {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		padding := strings.Repeat(" ", max(p.columns[i].Width-width(value), 0))
+		if s := style(i); s != nil {
+			value = s(value)
 		}
-		fmt.Fprintf(p.writer, format, rowInterface...)
+		b.WriteString(value)
+		b.WriteString(padding)
+	}
+	b.WriteByte('\n')
+	io.WriteString(p.writer, b.String())
+}
+
+// width is the number of columns value takes up in a terminal, not
+// counting ANSI style codes.
+func width(value string) int {
+	return utf8.RuneCountInString(ui.StripANSI(value))
+}
+
+// SortBy sorts the rows added so far by the value in column. Columns whose
+// values all start with a number, such as "2 GB" and "10 GB", are compared
+// numerically; other columns are compared as case-insensitive strings.
+func (p *Printer) SortBy(column int, ascending bool) error {
+	if column < 0 || column >= len(p.columns) {
+		return fmt.Errorf("column %d out of range: table has %d columns", column, len(p.columns))
+	}
+	SortRows(p.rows, column, ascending)
+	return nil
+}
+
+// SortRows sorts rows by column like Printer.SortBy. The sort is stable, so
+// rows with equal values keep their order.
+func SortRows(rows [][]string, column int, ascending bool) {
+	numeric := true
+	for _, row := range rows {
+		if column >= len(row) || row[column] == "" {
+			continue
+		}
+		if _, ok := leadingNumber(row[column]); !ok {
+			numeric = false
+			break
+		}
+	}
+
+	less := func(a, b string) bool {
+		if numeric {
+			x, xok := leadingNumber(a)
+			y, yok := leadingNumber(b)
+			if xok && yok && x != y {
+				return x < y
+			}
+			if xok != yok {
+				// Empty values sort first
+				return yok
+			}
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := cell(rows[i], column), cell(rows[j], column)
+		if ascending {
+			return less(a, b)
+		}
+		return less(b, a)
+	})
+}
+
+func cell(row []string, column int) string {
+	if column < len(row) {
+		return row[column]
+	}
+	return ""
+}
+
+// leadingNumber parses the number at the start of s, e.g. 2 in "2 GB".
+func leadingNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	end := 0
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.' || end == 0 && s[end] == '-') {
+		end++
 	}
+	n, err := strconv.ParseFloat(strings.TrimSuffix(s[:end], "."), 64)
+	return n, err == nil
 }
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"cloudamqp-cli/internal/ui"
 )
 
 func TestTablePrinter(t *testing.T) {
@@ -36,6 +38,41 @@ func TestTablePrinter(t *testing.T) {
 	}
 }
 
+func TestTablePrinterStyles(t *testing.T) {
+	var plain, styled bytes.Buffer
+	for _, buf := range []*bytes.Buffer{&plain, &styled} {
+		p := New(buf, "NAME", "READY", "PLAN")
+		if buf == &styled {
+			p.SetHeaderStyle(ui.Bold)
+			p.SetColumnStyle(1, ui.Green)
+		}
+		p.AddRow("orders", "Yes", "bunny-1")
+		p.AddRow("billing-eu", "No", "lemur")
+		p.Print()
+	}
+
+	if !strings.Contains(styled.String(), ui.Green("Yes")) {
+		t.Errorf("Expected styled READY values, got %q", styled.String())
+	}
+	// Styling must not change the alignment
+	if got := ui.StripANSI(styled.String()); got != plain.String() {
+		t.Errorf("Styled table misaligned:\n%s\nwant:\n%s", got, plain.String())
+	}
+}
+
+func TestTablePrinterANSIWidth(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf, "A", "B")
+	p.AddRow(ui.Green("xx"), "1")
+	p.AddRow("yyyy", "2")
+	p.Print()
+
+	lines := strings.Split(ui.StripANSI(buf.String()), "\n")
+	if strings.Index(lines[2], "1") != strings.Index(lines[3], "2") {
+		t.Errorf("ANSI codes counted in column width:\n%s", ui.StripANSI(buf.String()))
+	}
+}
+
 func TestTablePrinterColumnMismatch(t *testing.T) {
 	var buf bytes.Buffer
 	p := New(&buf, "COL1", "COL2")
@@ -45,3 +82,56 @@ func TestTablePrinterColumnMismatch(t *testing.T) {
 		t.Error("Expected error when adding row with wrong number of columns")
 	}
 }
+
+func firstColumn(t *testing.T, output string) []string {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(output), "\n")[2:]
+	values := make([]string, len(lines))
+	for i, line := range lines {
+		values[i] = strings.Fields(line)[0]
+	}
+	return values
+}
+
+func TestSortBy_Strings(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf, "NAME", "PLAN")
+	p.AddRow("orders", "bunny-1")
+	p.AddRow("Billing", "lemur")
+	p.AddRow("analytics", "rabbit-1")
+
+	if err := p.SortBy(0, true); err != nil {
+		t.Fatal(err)
+	}
+	p.Print()
+
+	got := strings.Join(firstColumn(t, buf.String()), ",")
+	if got != "analytics,Billing,orders" {
+		t.Errorf("expected case-insensitive ascending order, got %s", got)
+	}
+}
+
+func TestSortBy_Numeric(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf, "DISK", "NAME")
+	p.AddRow("10 GB", "a")
+	p.AddRow("2 GB", "b")
+	p.AddRow("100 GB", "c")
+
+	if err := p.SortBy(0, false); err != nil {
+		t.Fatal(err)
+	}
+	p.Print()
+
+	got := strings.Join(firstColumn(t, buf.String()), ",")
+	if got != "100,10,2" {
+		t.Errorf("expected numeric descending order, got %s", got)
+	}
+}
+
+func TestSortBy_OutOfRange(t *testing.T) {
+	p := New(&bytes.Buffer{}, "NAME")
+	if err := p.SortBy(1, true); err == nil {
+		t.Error("Expected error when sorting by a column that does not exist")
+	}
+}
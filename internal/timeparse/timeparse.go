@@ -0,0 +1,91 @@
+// Package timeparse parses the points in time given to flags such as
+// --since and --until, either absolute or relative to now.
+package timeparse
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// relative matches a duration made of one or more number-unit pairs, such
+// as 30m, 7d or 1d12h.
+var relative = regexp.MustCompile(`^(\d+(\.\d+)?(ns|us|µs|ms|s|m|h|d|w))+$`)
+
+var part = regexp.MustCompile(`(\d+(?:\.\d+)?)(ns|us|µs|ms|s|m|h|d|w)`)
+
+// absoluteLayouts are the accepted absolute formats. A date alone is
+// midnight in the local time zone.
+var absoluteLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// ParseDuration is time.ParseDuration with days (d, 24h) and weeks (w, 7d)
+// added. Units can be combined, as in 1d12h. Negative durations are not
+// accepted.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if !relative.MatchString(s) {
+		return 0, fmt.Errorf("invalid duration %q: expected e.g. 30m, 24h, 7d or 1d12h", s)
+	}
+
+	var total time.Duration
+	for _, m := range part.FindAllStringSubmatch(s, -1) {
+		number, unit := m[1], m[2]
+		var d time.Duration
+		switch unit {
+		case "d", "w":
+			days, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+			}
+			if unit == "w" {
+				days *= 7
+			}
+			if days*float64(24*time.Hour) > math.MaxInt64 {
+				return 0, fmt.Errorf("invalid duration %q: too long", s)
+			}
+			d = time.Duration(days * float64(24*time.Hour))
+		default:
+			var err error
+			if d, err = time.ParseDuration(number + unit); err != nil {
+				return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+			}
+		}
+		if d < 0 || total+d < total {
+			return 0, fmt.Errorf("invalid duration %q: too long", s)
+		}
+		total += d
+	}
+	return total, nil
+}
+
+// Parse returns the time s stands for: an absolute time in RFC 3339
+// (2026-10-16T10:00:00Z), with a space instead of the T, or a date alone;
+// or a duration before now as accepted by ParseDuration, e.g. 24h or 7d.
+// Absolute times without a zone are in the local time zone.
+func Parse(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time: expected e.g. 24h, 7d or 2026-10-16T10:00:00Z")
+	}
+	if relative.MatchString(s) {
+		d, err := ParseDuration(s)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(-d), nil
+	}
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected a duration such as 24h or 7d, or a time such as 2026-10-16T10:00:00Z", s)
+}
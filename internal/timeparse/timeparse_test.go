@@ -0,0 +1,95 @@
+package timeparse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"30m", 30 * time.Minute},
+		{"24h", 24 * time.Hour},
+		{"1d", 24 * time.Hour},
+		{"7d", 7 * 24 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"1d12h", 36 * time.Hour},
+		{"1w2d3h4m5s", (9*24+3)*time.Hour + 4*time.Minute + 5*time.Second},
+		{"1.5d", 36 * time.Hour},
+		{"1h30m", 90 * time.Minute},
+		{"500ms", 500 * time.Millisecond},
+		{" 2d ", 48 * time.Hour},
+		{"0s", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDuration(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseDuration_Invalid(t *testing.T) {
+	for _, in := range []string{"", "d", "7", "-1d", "1y", "1d-2h", "1 d", "yesterday", "1e3h", "99999999999d", "2562048h"} {
+		t.Run(in, func(t *testing.T) {
+			_, err := ParseDuration(in)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParse_Relative(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	got, err := Parse("7d", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 9, 12, 0, 0, 0, time.UTC), got)
+
+	got, err = Parse("1d12h", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), got)
+
+	got, err = Parse("30m", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-30*time.Minute), got)
+}
+
+func TestParse_Absolute(t *testing.T) {
+	now := time.Now()
+
+	got, err := Parse("2026-10-16T10:00:00Z", now)
+	require.NoError(t, err)
+	assert.True(t, got.Equal(time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)))
+
+	got, err = Parse("2026-10-16T10:00:00+02:00", now)
+	require.NoError(t, err)
+	assert.True(t, got.Equal(time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)))
+
+	got, err = Parse("2026-10-16T10:00:00.5Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, 500*time.Millisecond, time.Duration(got.Nanosecond()))
+
+	got, err = Parse("2026-10-16 10:00:00", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 16, 10, 0, 0, 0, time.Local), got)
+
+	got, err = Parse("2026-10-16", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.Local), got)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	now := time.Now()
+	for _, in := range []string{"", "  ", "yesterday", "2026-13-01", "2026-10-16T25:00:00Z", "16/10/2026", "-24h", "24"} {
+		t.Run(in, func(t *testing.T) {
+			_, err := Parse(in, now)
+			assert.Error(t, err)
+		})
+	}
+}
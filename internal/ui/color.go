@@ -0,0 +1,25 @@
+package ui
+
+import "regexp"
+
+// ANSI styles for terminal output.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiRed    = "\x1b[31m"
+)
+
+// Bold, Green, Yellow and Red wrap s in the ANSI codes for that style.
+func Bold(s string) string   { return ansiBold + s + ansiReset }
+func Green(s string) string  { return ansiGreen + s + ansiReset }
+func Yellow(s string) string { return ansiYellow + s + ansiReset }
+func Red(s string) string    { return ansiRed + s + ansiReset }
+
+var ansiCode = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// StripANSI removes ANSI style codes from s, leaving the visible text.
+func StripANSI(s string) string {
+	return ansiCode.ReplaceAllString(s, "")
+}
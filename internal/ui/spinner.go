@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+var frames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner draws a single self-updating line with a message and the time
+// elapsed since it was started.
+type Spinner struct {
+	writer   io.Writer
+	interval time.Duration
+
+	mu      sync.Mutex
+	message string
+	status  string
+	start   time.Time
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewSpinner creates a spinner that writes to writer.
+func NewSpinner(writer io.Writer, message string) *Spinner {
+	return &Spinner{
+		writer:   writer,
+		interval: 100 * time.Millisecond,
+		message:  message,
+	}
+}
+
+// IsTerminal reports whether f is attached to a terminal.
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// Start begins drawing the spinner in the background.
+func (s *Spinner) Start() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	s.mu.Lock()
+	s.start = time.Now()
+	s.stop = stop
+	s.done = done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for frame := 0; ; frame++ {
+			s.draw(frames[frame%len(frames)])
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SetStatus sets a short status shown after the elapsed time. It is a no-op
+// on a nil spinner.
+func (s *Spinner) SetStatus(status string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// Stop stops the spinner and clears its line. It is a no-op on a nil or
+// already stopped spinner.
+func (s *Spinner) Stop() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop = nil
+	s.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+	fmt.Fprint(s.writer, "\r\033[K")
+}
+
+func (s *Spinner) draw(frame string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line := fmt.Sprintf("%s %s %s", frame, s.message, time.Since(s.start).Round(time.Second))
+	if s.status != "" {
+		line += " (" + s.status + ")"
+	}
+	fmt.Fprintf(s.writer, "\r\033[K%s", line)
+}
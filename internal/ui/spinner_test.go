@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a bytes.Buffer safe for use by the spinner goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSpinner(t *testing.T) {
+	var out syncBuffer
+	s := NewSpinner(&out, "Waiting for instance to be ready...")
+	s.interval = time.Millisecond
+
+	s.Start()
+	s.SetStatus("configuring")
+	time.Sleep(10 * time.Millisecond)
+	s.Stop()
+	s.Stop() // stopping twice is harmless
+
+	got := out.String()
+	assert.Contains(t, got, "Waiting for instance to be ready... 0s")
+	assert.Contains(t, got, "(configuring)")
+	assert.True(t, len(got) > 0 && got[len(got)-4:] == "\r\033[K", "the line is cleared on stop")
+}
+
+func TestSpinner_Nil(t *testing.T) {
+	var s *Spinner
+	s.SetStatus("ignored")
+	s.Stop()
+}
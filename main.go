@@ -7,8 +7,7 @@ import (
 )
 
 func main() {
-	err := cmd.Execute()
-	if err != nil {
-		os.Exit(1)
+	if err := cmd.Execute(); err != nil {
+		os.Exit(cmd.ExitCode(err))
 	}
 }